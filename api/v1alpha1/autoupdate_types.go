@@ -0,0 +1,39 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// AutoUpdatePolicy lets the operator roll patch releases of the current
+// minor version automatically. Versions are taken from the manifest the
+// operator is configured with.
+type AutoUpdatePolicy struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// (Optional) Release channel of the version manifest to follow
+	// Default: stable
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// (Optional) Time window updates are allowed to start in. When not
+	// set, updates start as soon as a release is available.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+type MaintenanceWindow struct {
+	// (Optional) Days of the week the window is open on, every day when not set
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start of the window, HH:MM in UTC
+	// +kubebuilder:validation:Pattern:=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +required
+	StartTime string `json:"startTime"`
+
+	// Length of the window
+	// +required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
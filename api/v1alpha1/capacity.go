@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CapacityEstimate is the total of the resources requested by all pods and
+// volumes of a Storage or Database. Container limits are counted when no
+// requests are set.
+type CapacityEstimate struct {
+	CPU    resource.Quantity `json:"cpu"`
+	Memory resource.Quantity `json:"memory"`
+
+	// Size of the storage volumes, Storage only
+	Storage resource.Quantity `json:"storage"`
+
+	// Number of storage units allocated to the tenant, Database only
+	StorageUnits uint64 `json:"storageUnits,omitempty"`
+}
+
+// EstimateCapacity sums the resources requested by the storage node
+// containers and data volumes, spare pods included
+func (r *Storage) EstimateCapacity() CapacityEstimate {
+	containers := int64(r.TotalNodes())
+	if r.Spec.NodesPerPod > 1 {
+		containers *= int64(r.Spec.NodesPerPod)
+	}
+
+	estimate := CapacityEstimate{
+		CPU:    multiply(requested(r.Spec.Resources, corev1.ResourceCPU), containers),
+		Memory: multiply(requested(r.Spec.Resources, corev1.ResourceMemory), containers),
+	}
+	// Every node container gets its own volume of each data store
+	for _, spec := range r.Spec.DataStore {
+		estimate.Storage.Add(multiply(requested(spec.Resources, corev1.ResourceStorage), containers))
+	}
+	return estimate
+}
+
+// EstimateCapacity sums the resources requested by the database node
+// containers. Serverless databases request nothing of their own.
+func (r *Database) EstimateCapacity() CapacityEstimate {
+	resources := r.Spec.Resources
+	if resources == nil {
+		resources = r.Spec.SharedResources
+	}
+	if resources == nil {
+		return CapacityEstimate{}
+	}
+
+	estimate := CapacityEstimate{
+		CPU:    multiply(requested(resources.ContainerResources, corev1.ResourceCPU), int64(r.Spec.Nodes)),
+		Memory: multiply(requested(resources.ContainerResources, corev1.ResourceMemory), int64(r.Spec.Nodes)),
+	}
+	for _, unit := range resources.StorageUnits {
+		estimate.StorageUnits += unit.Count
+	}
+	return estimate
+}
+
+// Equal reports whether both estimates request the same amounts
+func (e *CapacityEstimate) Equal(other *CapacityEstimate) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	return e.CPU.Cmp(other.CPU) == 0 &&
+		e.Memory.Cmp(other.Memory) == 0 &&
+		e.Storage.Cmp(other.Storage) == 0 &&
+		e.StorageUnits == other.StorageUnits
+}
+
+func (e CapacityEstimate) String() string {
+	s := fmt.Sprintf("cpu %s, memory %s", e.CPU.String(), e.Memory.String())
+	if !e.Storage.IsZero() {
+		s += fmt.Sprintf(", storage %s", e.Storage.String())
+	}
+	if e.StorageUnits > 0 {
+		s += fmt.Sprintf(", storage units %d", e.StorageUnits)
+	}
+	return s
+}
+
+func requested(requirements corev1.ResourceRequirements, name corev1.ResourceName) resource.Quantity {
+	if quantity, ok := requirements.Requests[name]; ok {
+		return quantity
+	}
+	return requirements.Limits[name]
+}
+
+func multiply(quantity resource.Quantity, n int64) resource.Quantity {
+	result := resource.Quantity{Format: quantity.Format}
+	for i := int64(0); i < n; i++ {
+		result.Add(quantity)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const CapacityWarningWebhookPath = "/warn-ydb-tech-v1alpha1-capacity"
+
+//+kubebuilder:webhook:path=/warn-ydb-tech-v1alpha1-capacity,mutating=true,failurePolicy=ignore,sideEffects=None,groups=ydb.tech,resources=storages;databases,verbs=create,versions=v1alpha1,name=capacity-warning.ydb.tech,admissionReviewVersions=v1
+
+// CapacityWarner returns the capacity requested by a new Storage or
+// Database as an admission warning, so oversized requests are noticed before
+// the pods fail to schedule. It never rejects a request.
+type CapacityWarner struct{}
+
+func SetupCapacityWarningWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(CapacityWarningWebhookPath, &webhook.Admission{Handler: &CapacityWarner{}})
+}
+
+func (w *CapacityWarner) Handle(_ context.Context, req admission.Request) admission.Response {
+	var name string
+	var estimate CapacityEstimate
+	switch req.Kind.Kind {
+	case "Storage":
+		storage := &Storage{}
+		if err := json.Unmarshal(req.Object.Raw, storage); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		name, estimate = storage.Name, storage.EstimateCapacity()
+	case "Database":
+		database := &Database{}
+		if err := json.Unmarshal(req.Object.Raw, database); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if database.Spec.ServerlessResources != nil {
+			return admission.Allowed("")
+		}
+		name, estimate = database.Name, database.EstimateCapacity()
+	default:
+		return admission.Allowed("")
+	}
+
+	return admission.Allowed("").WithWarnings(
+		fmt.Sprintf("%s %s requests %s in total", req.Kind.Kind, name, estimate),
+	)
+}
@@ -23,6 +23,8 @@ const (
 	DiskPathPrefix      = "/dev/kikimr_ssd"
 	DiskNumberMaxDigits = 2
 	DiskFilePath        = "/data"
+	DiskFilesDir        = "/ydb_pdisks"
+	DiskFileName        = "pdisk.data"
 
 	ConfigDir      = "/opt/ydb/cfg"
 	ConfigFileName = "config.yaml"
@@ -31,6 +33,35 @@ const (
 	DaemonBinaryName = "ydbd"
 
 	TenantNameFormat = "/%s/%s"
+
+	NodeReadyGateConditionType = "ydb.tech/node-ready"
+
+	// DisasterRecoveryAnnotation starts the disaster recovery of a Storage
+	// when set to "confirm-<storage name>"
+	DisasterRecoveryAnnotation = "ydb.tech/disaster-recovery"
+
+	// DecommissionAnnotation lists the ordinals of the Storage pods to
+	// remove for good, comma separated, e.g. "7,8"
+	DecommissionAnnotation = "ydb.tech/decommission-nodes"
+
+	// Links to the observability pages of a Storage or Database, also
+	// kept in status.links
+	UILinkAnnotation      = "ydb.tech/ui-url"
+	GrafanaLinkAnnotation = "ydb.tech/grafana-url"
+
+	// RotateCredentialsAnnotation regenerates the credentials the operator
+	// generated for a Storage or Database, the monitoring password and the
+	// operator-managed certificate, whenever its value changes, e.g. set it
+	// to the current time
+	RotateCredentialsAnnotation = "ydb.tech/rotate-credentials"
+
+	// TenantRemovalFinalizer keeps a deleted Database until its tenant is
+	// removed from CMS
+	TenantRemovalFinalizer = "ydb.tech/remove-tenant"
+
+	// YdbUserRemovalFinalizer keeps a deleted YdbUser until its users,
+	// groups and grants are removed from the database
+	YdbUserRemovalFinalizer = "ydb.tech/remove-users"
 )
 
 type ErasureType string
@@ -40,3 +71,10 @@ const (
 	ErasureMirror3DC ErasureType = "mirror-3-dc"
 	None             ErasureType = "none"
 )
+
+type UnutilizedNodesPolicy string
+
+const (
+	UnutilizedNodesWarn   UnutilizedNodesPolicy = "Warn"
+	UnutilizedNodesReject UnutilizedNodesPolicy = "Reject"
+)
@@ -1,18 +1,23 @@
 package v1alpha1
 
 import (
-	"fmt"
-
 	v1 "k8s.io/api/core/v1"
 )
 
 const (
 	DefaultDatabaseDomain = "root"
+
+	DefaultStorageAutoscalingThresholdPercent = 80
+	DefaultStorageAutoscalingStep             = 1
 )
 
-// SetDatabaseSpecDefaults sets various values to the default vars.
+// SetDatabaseSpecDefaults sets various values to the default vars. It is
+// shared by the defaulting webhook and the controller. Defaults that don't
+// depend on other fields are also declared with kubebuilder:default markers,
+// so the API server applies them and kubectl explain shows them; keep the
+// two in sync.
 func SetDatabaseSpecDefaults(ydbCr *Database, ydbSpec *DatabaseSpec) {
-	if ydbSpec.StorageClusterRef.Namespace == "" {
+	if ydbSpec.StorageClusterRef != nil && ydbSpec.StorageClusterRef.Namespace == "" {
 		ydbSpec.StorageClusterRef.Namespace = ydbCr.Namespace
 	}
 
@@ -23,11 +28,7 @@ func SetDatabaseSpecDefaults(ydbCr *Database, ydbSpec *DatabaseSpec) {
 	}
 
 	if ydbSpec.Image.Name == "" {
-		if ydbSpec.YDBVersion == "" {
-			ydbSpec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, DefaultTag)
-		} else {
-			ydbSpec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, ydbSpec.YDBVersion)
-		}
+		ydbSpec.Image.Name = DefaultImageName(ydbSpec.YDBVersion)
 	}
 
 	if ydbSpec.Image.PullPolicyName == nil {
@@ -39,6 +40,18 @@ func SetDatabaseSpecDefaults(ydbCr *Database, ydbSpec *DatabaseSpec) {
 		ydbSpec.Domain = DefaultDatabaseDomain
 	}
 
+	if ydbSpec.Workload == "" {
+		ydbSpec.Workload = WorkloadStatefulSet
+	}
+
+	if ydbSpec.OperationalState == "" {
+		ydbSpec.OperationalState = OperationalStateRunning
+	}
+
+	if ydbSpec.DeletionPolicy == "" {
+		ydbSpec.DeletionPolicy = DeletionPolicyDelete
+	}
+
 	if ydbSpec.Service.GRPC.TLSConfiguration == nil {
 		ydbSpec.Service.GRPC.TLSConfiguration = &TLSConfiguration{Enabled: false}
 	}
@@ -48,4 +61,49 @@ func SetDatabaseSpecDefaults(ydbCr *Database, ydbSpec *DatabaseSpec) {
 	if ydbSpec.Service.Datastreams.TLSConfiguration == nil {
 		ydbSpec.Service.Datastreams.TLSConfiguration = &TLSConfiguration{Enabled: false}
 	}
+
+	if ydbSpec.Encryption == nil {
+		ydbSpec.Encryption = &EncryptionConfig{Enabled: false}
+	}
+	if ydbSpec.Datastreams == nil {
+		ydbSpec.Datastreams = &DatastreamsConfig{Enabled: false}
+	}
+	if ydbSpec.Monitoring == nil {
+		ydbSpec.Monitoring = &MonitoringOptions{Enabled: false}
+	}
+
+	if ydbSpec.StorageAutoscaling != nil {
+		if ydbSpec.StorageAutoscaling.UsageThresholdPercent == 0 {
+			ydbSpec.StorageAutoscaling.UsageThresholdPercent = DefaultStorageAutoscalingThresholdPercent
+		}
+		if ydbSpec.StorageAutoscaling.Step == 0 {
+			ydbSpec.StorageAutoscaling.Step = DefaultStorageAutoscalingStep
+		}
+		if ydbSpec.StorageAutoscaling.UnitKind == "" {
+			if ydbSpec.Resources != nil && len(ydbSpec.Resources.StorageUnits) > 0 {
+				ydbSpec.StorageAutoscaling.UnitKind = ydbSpec.Resources.StorageUnits[0].UnitKind
+			} else if ydbSpec.SharedResources != nil && len(ydbSpec.SharedResources.StorageUnits) > 0 {
+				ydbSpec.StorageAutoscaling.UnitKind = ydbSpec.SharedResources.StorageUnits[0].UnitKind
+			}
+		}
+	}
+
+	if ydbSpec.Proxy != nil {
+		if ydbSpec.Proxy.Replicas == nil {
+			replicas := int32(1)
+			ydbSpec.Proxy.Replicas = &replicas
+		}
+		if ydbSpec.Proxy.Image.PullPolicyName == nil {
+			policy := v1.PullIfNotPresent
+			ydbSpec.Proxy.Image.PullPolicyName = &policy
+		}
+	}
+
+	if ydbSpec.UIAuth != nil {
+		ydbSpec.UIAuth.SetDefaults()
+	}
+
+	if ydbSpec.DedicatedNodes != nil && ydbSpec.DedicatedNodes.TaintEffect == "" {
+		ydbSpec.DedicatedNodes.TaintEffect = v1.TaintEffectNoSchedule
+	}
 }
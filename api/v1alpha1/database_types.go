@@ -1,13 +1,19 @@
 package v1alpha1
 
 import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DatabaseSpec defines the desired state of Database
 type DatabaseSpec struct {
-	// Number of nodes (pods) in the cluster
+	// Number of nodes (pods) in the cluster. It can be changed through the
+	// scale subresource, e.g. by a HorizontalPodAutoscaler.
 	// +required
 	Nodes int32 `json:"nodes"`
 
@@ -17,21 +23,49 @@ type DatabaseSpec struct {
 
 	// (Optional) Storage services parameter overrides
 	// Default: (not specified)
+	// +kubebuilder:default:={}
 	// +optional
 	Service DatabaseServices `json:"service,omitempty"`
 
-	// YDB Storage cluster reference
-	// +required
-	StorageClusterRef StorageRef `json:"storageClusterRef"`
+	// YDB Storage cluster reference, exactly one of storageClusterRef and
+	// externalStorageEndpoint is set
+	// +optional
+	StorageClusterRef *StorageRef `json:"storageClusterRef,omitempty"`
+
+	// (Optional) YDB storage cluster not managed by this operator, running
+	// outside Kubernetes or managed by another operator instance
+	// +optional
+	ExternalStorageEndpoint *ExternalStorageEndpoint `json:"externalStorageEndpoint,omitempty"`
 
 	// Encryption
+	// +kubebuilder:default:={enabled: false}
 	// +optional
 	Encryption *EncryptionConfig `json:"encryption,omitempty"`
 
 	// Datastreams config
+	// +kubebuilder:default:={enabled: false}
 	// +optional
 	Datastreams *DatastreamsConfig `json:"datastreams,omitempty"`
 
+	// (Optional) S3 tiers column tables offload cold data to
+	// +optional
+	Tiering *TieringConfig `json:"tiering,omitempty"`
+
+	// (Optional) Query service limits of the dynamic nodes, rendered into
+	// table_service_config over the settings of the Storage configuration
+	// +optional
+	QueryService *QueryServiceConfig `json:"queryService,omitempty"`
+
+	// (Optional) Limits of the data the tenant may store, applied through CMS
+	// and kept in sync
+	// +optional
+	DataSizeQuota *DataSizeQuota `json:"dataSizeQuota,omitempty"`
+
+	// (Optional) Options the tenant is created with in CMS, they cannot be
+	// changed afterwards
+	// +optional
+	TenantOptions *TenantOptions `json:"tenantOptions,omitempty"`
+
 	// (Optional) Name of the root storage domain
 	// Default: root
 	// +kubebuilder:validation:Pattern:=[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?
@@ -40,6 +74,13 @@ type DatabaseSpec struct {
 	// +optional
 	Domain string `json:"domain"`
 
+	// (Optional) Path of the tenant, for tenants whose path does not follow
+	// the name of the Database, e.g. of migrated clusters. It must be in
+	// the domain. The path cannot be changed once the Database is created.
+	// Default: /<domain>/<name>
+	// +optional
+	Path string `json:"path,omitempty"`
+
 	// (Optional) Database storage and compute resources
 	// +optional
 	Resources *DatabaseResources `json:"resources,omitempty"` // TODO: Add validation webhook: some resources must be specified
@@ -75,6 +116,7 @@ type DatabaseSpec struct {
 
 	// (Optional) Monitoring sets configuration options for YDB observability
 	// Default: ""
+	// +kubebuilder:default:={enabled: false}
 	// +optional
 	Monitoring *MonitoringOptions `json:"monitoring,omitempty"`
 
@@ -84,9 +126,16 @@ type DatabaseSpec struct {
 	YDBVersion string `json:"version,omitempty"`
 
 	// (Optional) YDB Image
+	// +kubebuilder:default:={}
 	// +optional
 	Image PodImage `json:"image,omitempty"`
 
+	// (Optional) Additional arguments appended to the ydbd command line,
+	// e.g. to enable experimental features. The arguments the operator
+	// sets itself are rejected.
+	// +optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+
 	// NodeSelector is a selector which must be true for the pod to fit on a node.
 	// Selector which must match a node's labels for the pod to be scheduled on that node.
 	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
@@ -101,6 +150,78 @@ type DatabaseSpec struct {
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// (Optional) Pod management policy of the StatefulSet. Parallel starts all
+	// pods at once, OrderedReady starts them one by one. Changing the policy
+	// recreates the StatefulSet, leaving the running pods in place.
+	// Default: Parallel
+	// +kubebuilder:validation:Enum=Parallel;OrderedReady
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// (Optional) Kind of the workload running the dynamic nodes. Deployment
+	// pods have no stable names: nodes advertise their pod IP and take a new
+	// node ID from the node broker on every start, in exchange pods are
+	// scaled and rescheduled without waiting for ordinals. Changing the kind
+	// replaces all the pods.
+	// Default: StatefulSet
+	// +kubebuilder:validation:Enum=StatefulSet;Deployment
+	// +kubebuilder:default:=StatefulSet
+	// +optional
+	Workload DatabaseWorkload `json:"workload,omitempty"`
+
+	// (Optional) Stopped scales the dynamic nodes to zero, keeping the tenant
+	// and the configuration, Running brings them back
+	// Default: Running
+	// +kubebuilder:validation:Enum=Running;Stopped
+	// +kubebuilder:default:=Running
+	// +optional
+	OperationalState DatabaseOperationalState `json:"operationalState,omitempty"`
+
+	// (Optional) What happens to the tenant when the Database is deleted.
+	// Delete removes it from CMS with all its data, Retain only removes the
+	// Kubernetes resources. A Database created again at the same path
+	// adopts a retained tenant.
+	// Default: Delete
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default:=Delete
+	// +optional
+	DeletionPolicy DatabaseDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// (Optional) Add the ydb.tech/node-ready readiness gate to the pods. The operator
+	// sets it once the node answers the gRPC health service and is registered in
+	// the node broker, so Services do not route to nodes that are still starting.
+	// Default: false
+	// +optional
+	ReadinessGate bool `json:"readinessGate,omitempty"`
+
+	// (Optional) Roll out pod template changes one pod at a time, restarting
+	// each node only after the YDB CMS permits taking it down. The StatefulSet
+	// uses the OnDelete update strategy and the operator deletes the pods.
+	// Ignored for the Deployment workload.
+	// Default: false
+	// +optional
+	CoordinatedRollout bool `json:"coordinatedRollout,omitempty"`
+
+	// (Optional) How long a pod restarted by the coordinated rollout is kept
+	// out of the Service endpoints before it is deleted, so clients move to
+	// the other nodes before its node gets SIGTERM. Takes effect with
+	// readinessGate, which also keeps the new pod out of the endpoints until
+	// its node is registered in the node broker
+	// Default: 5s
+	// +optional
+	EndpointDrainDelay *metav1.Duration `json:"endpointDrainDelay,omitempty"`
+
+	// (Optional) Automatic updates to new patch releases of the current version
+	// +optional
+	AutoUpdate *AutoUpdatePolicy `json:"autoUpdate,omitempty"`
+
+	// (Optional) Roll out changes of spec.image in batches of pods, waiting
+	// for the database to be healthy between the batches. The new version
+	// must not be newer than the version of the Storage.
+	// Ignored for the Deployment workload.
+	// +optional
+	Upgrade *UpgradeStrategy `json:"upgrade,omitempty"`
+
 	// (Optional) Additional custom resource labels that are added to all resources
 	// +optional
 	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
@@ -108,6 +229,223 @@ type DatabaseSpec struct {
 	// (Optional) Additional custom resource annotations that are added to all resources
 	// +optional
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
+
+	// (Optional) Additional labels of the pods of the nodes, the labels of
+	// the operator take precedence. Services with selectAdditionalPodLabels
+	// only route to the pods carrying them, relabel a pod to take it out.
+	// +optional
+	AdditionalPodLabels map[string]string `json:"additionalPodLabels,omitempty"`
+
+	// (Optional) Connection proxy deployed in front of the database nodes
+	// +optional
+	Proxy *DatabaseProxy `json:"proxy,omitempty"`
+
+	// (Optional) OIDC login in front of the embedded UI
+	// +optional
+	UIAuth *UIAuth `json:"uiAuth,omitempty"`
+
+	// (Optional) Automatic growth of storage units based on used space
+	// +optional
+	StorageAutoscaling *StorageAutoscaling `json:"storageAutoscaling,omitempty"`
+
+	// (Optional) Reserve matching Kubernetes nodes exclusively for this database.
+	// The operator labels and taints the selected nodes and schedules the
+	// database pods onto them with the matching toleration.
+	// +optional
+	DedicatedNodes *DedicatedNodes `json:"dedicatedNodes,omitempty"`
+
+	// (Optional) Placement of the database pods relative to the pods of the
+	// referenced Storage, merged into the pod affinity rules
+	// +optional
+	StorageLocality *StorageLocality `json:"storageLocality,omitempty"`
+
+	// (Optional) Tenant user attributes, e.g. billing or ownership tags
+	// shown by YDB tooling. They are set when the tenant is created and kept
+	// in sync with the spec.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// (Optional) Resource labels and annotations copied into the tenant user
+	// attributes. The attributes are kept in sync when the metadata changes.
+	// Copied values win over spec.attributes with the same key.
+	// +optional
+	TenantAttributes *TenantAttributes `json:"tenantAttributes,omitempty"`
+
+	// (Optional) YQL scripts run against the database once its tenant is
+	// initialized, in the order of the list. A script is run again only
+	// when its text changes, so scripts should be idempotent, e.g. use
+	// CREATE TABLE IF NOT EXISTS.
+	// +optional
+	InitScripts []InitScript `json:"initScripts,omitempty"`
+
+	// (Optional) Followers of the datashards of the tables, read-only copies
+	// of the shards serving stale reads close to the clients. They are set
+	// through ALTER TABLE once the tenant is initialized and kept in sync,
+	// the followers of tables removed from the list are turned off.
+	// +optional
+	ReadReplicas []TableReadReplicas `json:"readReplicas,omitempty"`
+
+	// (Optional) Suspend the reconciliation of the database: child
+	// resources and the tenant are left as they are, so they can be changed
+	// by hand. Deleting the database still removes the tenant.
+	// Default: false
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+}
+
+type TenantAttributes struct {
+	// (Optional) Keys of the labels to copy
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// (Optional) Keys of the annotations to copy. An annotation wins over a
+	// label with the same key.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// InitScript is a YQL script given inline or by a ConfigMap key, one of
+// them has to be set
+type InitScript struct {
+	// Name the script is tracked by in status
+	// +kubebuilder:validation:Pattern:=[a-z0-9]([-a-z0-9]*[a-z0-9])?
+	// +kubebuilder:validation:MaxLength:=63
+	// +required
+	Name string `json:"name"`
+
+	// (Optional) Text of the script
+	// +optional
+	Script string `json:"script,omitempty"`
+
+	// (Optional) Key of a ConfigMap in the namespace of the database holding
+	// the script
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+type ReadReplicasPlacement string
+
+const (
+	// ReadReplicasPerAZ places the followers in every availability zone
+	ReadReplicasPerAZ ReadReplicasPlacement = "PerAZ"
+	// ReadReplicasAnyAZ places the followers in any availability zones
+	ReadReplicasAnyAZ ReadReplicasPlacement = "AnyAZ"
+)
+
+// TableReadReplicas sets the number and placement of the followers of
+// every datashard of a table
+type TableReadReplicas struct {
+	// Path of the table relative to the database, e.g. orders or app/orders
+	// +required
+	Table string `json:"table"`
+
+	// Number of followers of every shard, in every zone with placement
+	// PerAZ. 0 turns the followers off.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=3
+	// +required
+	Count int32 `json:"count"`
+
+	// (Optional) Placement of the followers
+	// Default: AnyAZ
+	// +kubebuilder:validation:Enum=PerAZ;AnyAZ
+	// +kubebuilder:default:=AnyAZ
+	// +optional
+	Placement ReadReplicasPlacement `json:"placement,omitempty"`
+}
+
+// Setting returns the READ_REPLICAS_SETTINGS value of the table
+func (t TableReadReplicas) Setting() string {
+	if t.Placement == ReadReplicasPerAZ {
+		return fmt.Sprintf("PER_AZ:%d", t.Count)
+	}
+	return fmt.Sprintf("ANY_AZ:%d", t.Count)
+}
+
+type InitScriptState string
+
+const (
+	InitScriptSucceeded InitScriptState = "Succeeded"
+	InitScriptFailed    InitScriptState = "Failed"
+)
+
+type InitScriptStatus struct {
+	Name string `json:"name"`
+
+	// SHA-256 of the text of the script run last
+	Checksum string `json:"checksum"`
+
+	State InitScriptState `json:"state"`
+
+	// (Optional) Error of the last run
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	LastRunTime metav1.Time `json:"lastRunTime"`
+}
+
+type DatabaseWorkload string
+
+const (
+	WorkloadStatefulSet DatabaseWorkload = "StatefulSet"
+	WorkloadDeployment  DatabaseWorkload = "Deployment"
+)
+
+type DatabaseOperationalState string
+
+const (
+	OperationalStateRunning DatabaseOperationalState = "Running"
+	OperationalStateStopped DatabaseOperationalState = "Stopped"
+)
+
+type DatabaseDeletionPolicy string
+
+const (
+	DeletionPolicyDelete DatabaseDeletionPolicy = "Delete"
+	DeletionPolicyRetain DatabaseDeletionPolicy = "Retain"
+)
+
+type ZoneAffinityMode string
+
+const (
+	ZoneAffinityPreferred ZoneAffinityMode = "Preferred"
+	ZoneAffinityRequired  ZoneAffinityMode = "Required"
+)
+
+type StorageLocality struct {
+	// (Optional) Keep database pods in the zones that run storage pods, to
+	// reduce cross-zone traffic. Preferred is a soft rule, Required a hard one.
+	// Default: (not specified)
+	// +kubebuilder:validation:Enum=Preferred;Required
+	// +optional
+	SameZone ZoneAffinityMode `json:"sameZone,omitempty"`
+
+	// (Optional) Never schedule database pods on the nodes running storage pods
+	// Default: false
+	// +optional
+	AvoidStorageNodes bool `json:"avoidStorageNodes,omitempty"`
+
+	// (Optional) Keep database pods off the nodes running pods of other
+	// Databases of the same Storage, to limit noisy neighbors. Preferred is a
+	// soft rule, Required a hard one that gives every database its own nodes.
+	// Default: (not specified)
+	// +kubebuilder:validation:Enum=Preferred;Required
+	// +optional
+	AvoidOtherDatabases ZoneAffinityMode `json:"avoidOtherDatabases,omitempty"`
+}
+
+type DedicatedNodes struct {
+	// Selector of the nodes to dedicate to the database
+	// +kubebuilder:validation:MinProperties:=1
+	// +required
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// (Optional) Effect of the taint put on the dedicated nodes
+	// Default: NoSchedule
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// +kubebuilder:default:="NoSchedule"
+	// +optional
+	TaintEffect corev1.TaintEffect `json:"taintEffect,omitempty"`
 }
 
 type DatabaseResources struct {
@@ -128,6 +466,102 @@ type ServerlessDatabaseResources struct {
 	// Reference to YDB Database with configured shared resources
 	// +required
 	SharedDatabaseRef SharedDatabaseRef `json:"sharedDatabaseRef,omitempty"`
+
+	// (Optional) Limits CMS enforces on the serverless tenant, applied on
+	// creation and kept in sync afterwards
+	// +optional
+	Quotas *TenantQuotas `json:"quotas,omitempty"`
+}
+
+// TenantQuotas cap what a tenant may take from the shared resources. CMS of
+// the supported YDB versions has no request unit or session limits, the
+// tenant is capped by the rate of its schema operations and the shards of
+// its data streams.
+type TenantQuotas struct {
+	// (Optional) Leaky buckets limiting the rate of schema operations, all
+	// of them have to allow an operation
+	// +optional
+	SchemaOperations []SchemaOperationsQuota `json:"schemaOperations,omitempty"`
+
+	// (Optional) Maximum number of shards in all data streams
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	DataStreamShards *int64 `json:"dataStreamShards,omitempty"`
+}
+
+// DataSizeQuota bounds the data of a tenant. Writes are rejected once the
+// data grows above the hard limit, and are accepted again when it shrinks
+// below the soft one.
+type DataSizeQuota struct {
+	// Size above which new data is rejected
+	// +required
+	Hard resource.Quantity `json:"hard"`
+
+	// (Optional) Size below which new data is accepted again, lower than
+	// hard. Keeps the database from rapidly entering and leaving the
+	// overloaded state
+	// +optional
+	Soft *resource.Quantity `json:"soft,omitempty"`
+}
+
+// TenantOptions are passed to CreateDatabase. CMS takes the plan resolution
+// only, the numbers of coordinators, mediators and time cast buckets of a
+// tenant are not in its API and come from the domain configuration.
+type TenantOptions struct {
+	// (Optional) Resolution of the transaction plans of the mediators, e.g.
+	// 50ms. Coarser plans let the coordinators of large tenants keep up with
+	// more transactions at the cost of latency. Rounded down to milliseconds
+	// Default: (domain default)
+	// +optional
+	PlanResolution *metav1.Duration `json:"planResolution,omitempty"`
+}
+
+// PlanResolutionMilliseconds returns the plan resolution in CMS units, 0
+// for the domain default
+func (o *TenantOptions) PlanResolutionMilliseconds() uint32 {
+	if o == nil || o.PlanResolution == nil {
+		return 0
+	}
+	return uint32(o.PlanResolution.Milliseconds())
+}
+
+type SchemaOperationsQuota struct {
+	// Number of schema operations allowed per period
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	Operations int64 `json:"operations"`
+
+	// Period the operations are counted over, e.g. 1h
+	// +required
+	Period metav1.Duration `json:"period"`
+}
+
+type StorageAutoscaling struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// (Optional) Storage unit kind to add, must be one of the database storage units
+	// Default: kind of the first storage unit
+	// +optional
+	UnitKind string `json:"unitKind,omitempty"`
+
+	// (Optional) Used space, in percents of the allocated limit, above which units are added
+	// Default: 80
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +kubebuilder:default:=80
+	// +optional
+	UsageThresholdPercent int32 `json:"usageThresholdPercent,omitempty"`
+
+	// (Optional) Number of units added at once
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +optional
+	Step uint64 `json:"step,omitempty"`
+
+	// Upper limit for the total number of units of this kind
+	// +required
+	MaxUnits uint64 `json:"maxUnits"`
 }
 
 type StorageUnit struct {
@@ -146,11 +580,203 @@ type StorageUnit struct {
 type DatabaseStatus struct {
 	State      string             `json:"state"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Generation of the spec the last complete reconcile went through
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Time of the last State change
+	StateTransitionTime *metav1.Time `json:"stateTransitionTime,omitempty"`
+
+	ResourcesSync *ResourcesSyncStatus `json:"resourcesSync,omitempty"`
+
+	// ConfigMaps and Secrets the pods of the workload use, with the hashes
+	// of their data
+	MountedObjects []MountedObject `json:"mountedObjects,omitempty"`
+
+	// Pods CPU and memory usage, recorded when the metrics API is available
+	ResourceUsage *ResourceUsageStatus `json:"resourceUsage,omitempty"`
+
+	// Most recent actions taken by the operator, oldest first
+	History []HistoryEntry `json:"history,omitempty"`
+
+	StorageAutoscaling *StorageAutoscalingStatus `json:"storageAutoscaling,omitempty"`
+
+	// Generation whose storage units were last applied to the tenant
+	StorageUnitsGeneration int64 `json:"storageUnitsGeneration,omitempty"`
+
+	// Tenant user attributes last set from spec.attributes and spec.tenantAttributes
+	TenantAttributes map[string]string `json:"tenantAttributes,omitempty"`
+
+	// Scripts of spec.initScripts that have been run
+	InitScripts []InitScriptStatus `json:"initScripts,omitempty"`
+
+	// READ_REPLICAS_SETTINGS last set on the tables of spec.readReplicas
+	ReadReplicas map[string]string `json:"readReplicas,omitempty"`
+
+	// Tenant quotas last applied through CMS
+	TenantQuotas *TenantQuotas `json:"tenantQuotas,omitempty"`
+
+	// Data size quota last applied through CMS
+	DataSizeQuota *DataSizeQuota `json:"dataSizeQuota,omitempty"`
+
+	// Kind of the compute resources the tenant currently runs with, one of
+	// Dedicated, Shared or Serverless
+	ResourcesKind string `json:"resourcesKind,omitempty"`
+
+	// Kind of the resources the tenant is registered with in CMS. CMS keeps
+	// the kind a tenant is created with, after a migration it can differ
+	// from resourcesKind.
+	TenantResourcesKind string `json:"tenantResourcesKind,omitempty"`
+
+	// Total resources requested by the pods and volumes
+	Capacity *CapacityEstimate `json:"capacity,omitempty"`
+
+	// Links to the embedded UI and the dashboards of the database
+	Links *ResourceLinks `json:"links,omitempty"`
+
+	// Nodes being drained before spec.nodes is decreased
+	Drain *NodeDrainStatus `json:"drain,omitempty"`
+
+	// Pod being restarted by a coordinated rollout
+	Rollout *CoordinatedRolloutStatus `json:"rollout,omitempty"`
+
+	// Progress of the upgrade to a new spec.image
+	Upgrade *DatabaseUpgradeStatus `json:"upgrade,omitempty"`
+
+	// Resource version of the <name>-monitoring Secret whose credentials
+	// are registered as a YDB user, see spec.monitoring.isolatedCredentials
+	MonitoringCredentialsVersion string `json:"monitoringCredentialsVersion,omitempty"`
+
+	// CreateDatabase operation of the tenant still running in CMS
+	TenantOperation *TenantOperationStatus `json:"tenantOperation,omitempty"`
+
+	// gRPC endpoints (host:port) clients of the database connect to
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Path of the tenant, the database name clients pass on connect
+	DatabasePath string `json:"databasePath,omitempty"`
+
+	// YDB version reported by the running nodes
+	Version string `json:"version,omitempty"`
+
+	// Number of ready dynamic node pods
+	ReadyNodes int32 `json:"readyNodes,omitempty"`
+
+	// Selector of the dynamic node pods, used by the scale subresource
+	Selector string `json:"selector,omitempty"`
+
+	// What the operator does or waits for before the reconcile completes,
+	// empty when there is nothing left to do
+	NextAction string `json:"nextAction,omitempty"`
+}
+
+const (
+	ResourcesKindDedicated  = "Dedicated"
+	ResourcesKindShared     = "Shared"
+	ResourcesKindServerless = "Serverless"
+)
+
+type StorageAutoscalingStatus struct {
+	// Number of units added on top of the ones in spec
+	AddedUnits uint64 `json:"addedUnits"`
+
+	// Time units were last added
+	LastScaleTime metav1.Time `json:"lastScaleTime"`
+}
+
+type TenantOperationStatus struct {
+	// Id of the CMS operation, polled until it is ready
+	ID string `json:"id"`
+
+	// Time the operation was started
+	StartTime metav1.Time `json:"startTime"`
+}
+
+type CoordinatedRolloutStatus struct {
+	Pod string `json:"pod"`
+
+	// UID of the pod while it is kept out of the Service endpoints before
+	// it is deleted, empty once it is deleted
+	PodUID types.UID `json:"podUID,omitempty"`
+
+	// CMS permission to restart the node, released once the pod is ready
+	PermissionID string `json:"permissionId,omitempty"`
+
+	// Time the restart of the pod started, the pod is deleted after the
+	// endpoint drain delay
+	StartTime metav1.Time `json:"startTime"`
+}
+
+type UpgradeStrategy struct {
+	// (Optional) Number of pods updated at once
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// (Optional) How long the pods of a batch have to stay ready before the
+	// next batch is started
+	// Default: 30s
+	// +kubebuilder:default:="30s"
+	// +optional
+	BatchInterval *metav1.Duration `json:"batchInterval,omitempty"`
+}
+
+type DatabaseUpgradeStatus struct {
+	FromImage string `json:"fromImage"`
+	ToImage   string `json:"toImage"`
+
+	// Pods with an ordinal at or above the partition run the new image
+	Partition int32 `json:"partition"`
+
+	// Number of pods running the new image
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+	Replicas        int32 `json:"replicas"`
+
+	// Time the upgrade started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Time every pod of the current batch was ready and healthy
+	BatchReadyTime *metav1.Time `json:"batchReadyTime,omitempty"`
+}
+
+type NodeDrainStatus struct {
+	// Number of pods kept running until the drain completes
+	Replicas int32 `json:"replicas"`
+
+	// Number of pods to run once the drain completes
+	TargetReplicas int32 `json:"targetReplicas"`
+
+	// Time the drain started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Nodes of the pods to be removed
+	Nodes []DrainedNode `json:"nodes,omitempty"`
+}
+
+type DrainedNode struct {
+	Pod string `json:"pod"`
+
+	// Node broker ID, zero until the node is found in discovery
+	NodeID uint32 `json:"nodeId,omitempty"`
+
+	// Tablet leaders still running on the node, as last observed
+	Tablets int32 `json:"tablets"`
+
+	// The drain was requested from Hive
+	Requested bool `json:"requested,omitempty"`
+
+	// No tablets are left on the node
+	Drained bool `json:"drained,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.nodes,statuspath=.status.readyNodes,selectorpath=.status.selector
+//+kubebuilder:resource:shortName={ydb,ydbdb},categories=ydb-all
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this DB"
+//+kubebuilder:printcolumn:name="Next Action",type="string",JSONPath=".status.nextAction",description="What the operator does or waits for"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Database is the Schema for the databases API
@@ -164,6 +790,26 @@ type Database struct {
 	Status DatabaseStatus `json:"status,omitempty"`
 }
 
+// TenantPath returns the path of the tenant, spec.path or /<domain>/<name>
+func (r *Database) TenantPath() string {
+	if r.Spec.Path != "" {
+		return r.Spec.Path
+	}
+	return fmt.Sprintf(TenantNameFormat, r.Spec.Domain, r.Name)
+}
+
+// Replicas returns the number of dynamic node pods to run, zero for a
+// stopped database. Pods being drained are kept until the drain completes.
+func (r *Database) Replicas() int32 {
+	if r.Spec.OperationalState == OperationalStateStopped {
+		return 0
+	}
+	if r.Status.Drain != nil && r.Status.Drain.Replicas > r.Spec.Nodes {
+		return r.Status.Drain.Replicas
+	}
+	return r.Spec.Nodes
+}
+
 //+kubebuilder:object:root=true
 
 // DatabaseList contains a list of Database
@@ -184,6 +830,7 @@ type PodImage struct {
 
 	// (Optional) PullPolicy for the image, which defaults to IfNotPresent.
 	// Default: IfNotPresent
+	// +kubebuilder:default:=IfNotPresent
 	// +optional
 	PullPolicyName *corev1.PullPolicy `json:"pullPolicy,omitempty"`
 
@@ -214,6 +861,67 @@ type DatastreamsConfig struct {
 	IAMServiceAccountKey *corev1.SecretKeySelector `json:"iam_service_account_key,omitempty"`
 }
 
+type QueryServiceConfig struct {
+	// (Optional) Maximum number of sessions on a node, new sessions over the
+	// limit are rejected
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxSessionsPerNode *int32 `json:"maxSessionsPerNode,omitempty"`
+
+	// (Optional) Memory a single query can use on a node
+	// +optional
+	QueryMemoryLimit *resource.Quantity `json:"queryMemoryLimit,omitempty"`
+
+	// (Optional) Maximum number of rows in a query result set
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxResultRows *int64 `json:"maxResultRows,omitempty"`
+}
+
+type TieringConfig struct {
+	// +kubebuilder:validation:MinItems:=1
+	// +required
+	Tiers []StorageTier `json:"tiers"`
+}
+
+// StorageTier is an S3 compatible object storage. The credentials are mounted
+// into the database pods, only their paths get into the configuration.
+type StorageTier struct {
+	// Name of the tier, tables refer to it in their TTL settings
+	// +kubebuilder:validation:Pattern:=[a-z0-9]([-a-z0-9]*[a-z0-9])?
+	// +kubebuilder:validation:MaxLength:=40
+	// +required
+	Name string `json:"name"`
+
+	// Host and optional port of the object storage, e.g. storage.yandexcloud.net
+	// +required
+	Endpoint string `json:"endpoint"`
+
+	// +required
+	Bucket string `json:"bucket"`
+
+	// (Optional) Default: HTTPS
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Access key id of the object storage account
+	// +required
+	AccessKey corev1.SecretKeySelector `json:"accessKey"`
+
+	// Secret access key of the object storage account
+	// +required
+	SecretKey corev1.SecretKeySelector `json:"secretKey"`
+
+	// (Optional) Age after which the data of tables using the tier is
+	// evicted to it, tables may override it in their TTL settings
+	// +optional
+	EvictAfter *metav1.Duration `json:"evictAfter,omitempty"`
+}
+
 // StorageRef todo
 type StorageRef struct {
 	// +kubebuilder:validation:Pattern:=[a-z0-9]([-a-z0-9]*[a-z0-9])?
@@ -240,10 +948,13 @@ type SharedDatabaseRef struct {
 }
 
 type DatabaseServices struct {
-	GRPC         GRPCService         `json:"grpc,omitempty"`
+	// +kubebuilder:default:={}
+	GRPC GRPCService `json:"grpc,omitempty"`
+	// +kubebuilder:default:={}
 	Interconnect InterconnectService `json:"interconnect,omitempty"`
 	Status       StatusService       `json:"status,omitempty"`
-	Datastreams  DatastreamsService  `json:"datastreams,omitempty"`
+	// +kubebuilder:default:={}
+	Datastreams DatastreamsService `json:"datastreams,omitempty"`
 }
 
 func init() {
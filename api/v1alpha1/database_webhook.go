@@ -3,8 +3,9 @@ package v1alpha1
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,80 +29,261 @@ var _ webhook.Defaulter = &Database{}
 func (r *Database) Default() {
 	databaselog.Info("default", "name", r.Name)
 
-	if r.Spec.StorageClusterRef.Namespace == "" {
-		r.Spec.StorageClusterRef.Namespace = r.Namespace
-	}
+	SetDatabaseSpecDefaults(r, &r.Spec)
+}
 
-	if r.Spec.ServerlessResources != nil {
-		if r.Spec.ServerlessResources.SharedDatabaseRef.Namespace == "" {
-			r.Spec.ServerlessResources.SharedDatabaseRef.Namespace = r.Namespace
-		}
-	}
+//+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-database,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=databases,verbs=create;update,versions=v1alpha1,name=validate-database.ydb.tech,admissionReviewVersions=v1
 
-	if r.Spec.Image.Name == "" {
-		if r.Spec.YDBVersion == "" {
-			r.Spec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, DefaultTag)
-		} else {
-			r.Spec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, r.Spec.YDBVersion)
-		}
+var _ webhook.Validator = &Database{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Database) ValidateCreate() error {
+	databaselog.Info("validate create", "name", r.Name)
+
+	if r.Spec.Resources == nil && r.Spec.SharedResources == nil && r.Spec.ServerlessResources == nil {
+		return errors.New("incorrect database resources configuration, must be one of: Resources, SharedResources, ServerlessResources")
 	}
 
-	if r.Spec.Image.PullPolicyName == nil {
-		policy := v1.PullIfNotPresent
-		r.Spec.Image.PullPolicyName = &policy
+	if err := r.validateStorage(); err != nil {
+		return err
+	}
+	if err := r.validatePath(); err != nil {
+		return err
+	}
+	if err := r.validateTiering(); err != nil {
+		return err
+	}
+	if err := r.validateAttributes(); err != nil {
+		return err
+	}
+	if err := r.validateTenantOptions(); err != nil {
+		return err
+	}
+	if err := r.validateInitScripts(); err != nil {
+		return err
+	}
+	if err := r.validateReadReplicas(); err != nil {
+		return err
+	}
+	if err := r.Spec.Service.GRPC.Server.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.UIAuth.Validate(); err != nil {
+		return err
 	}
+	if err := validateAdditionalArgs(r.Spec.AdditionalArgs); err != nil {
+		return err
+	}
+	return r.validateQuotas()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Database) ValidateUpdate(old runtime.Object) error {
+	databaselog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Service.GRPC.TLSConfiguration == nil {
-		r.Spec.Service.GRPC.TLSConfiguration = &TLSConfiguration{Enabled: false}
+	// The nodes register in the tenant and CMS knows it by its path, there
+	// is no renaming a tenant
+	if oldDatabase, ok := old.(*Database); ok && oldDatabase.TenantPath() != r.TenantPath() {
+		return fmt.Errorf("tenant path cannot be changed from %s to %s", oldDatabase.TenantPath(), r.TenantPath())
+	}
+	if oldDatabase, ok := old.(*Database); ok &&
+		oldDatabase.Spec.TenantOptions.PlanResolutionMilliseconds() != r.Spec.TenantOptions.PlanResolutionMilliseconds() {
+		return errors.New("spec.tenantOptions cannot be changed, the tenant is created with them")
+	}
+	if err := r.validateStorage(); err != nil {
+		return err
+	}
+	if err := r.validatePath(); err != nil {
+		return err
+	}
+	if err := r.validateTiering(); err != nil {
+		return err
+	}
+	if err := r.validateAttributes(); err != nil {
+		return err
 	}
-	if r.Spec.Service.Interconnect.TLSConfiguration == nil {
-		r.Spec.Service.Interconnect.TLSConfiguration = &TLSConfiguration{Enabled: false}
+	if err := r.validateTenantOptions(); err != nil {
+		return err
 	}
+	if err := r.validateInitScripts(); err != nil {
+		return err
+	}
+	if err := r.validateReadReplicas(); err != nil {
+		return err
+	}
+	if err := r.Spec.Service.GRPC.Server.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.UIAuth.Validate(); err != nil {
+		return err
+	}
+	if err := validateAdditionalArgs(r.Spec.AdditionalArgs); err != nil {
+		return err
+	}
+	return r.validateQuotas()
+}
 
-	if r.Spec.Service.Datastreams.TLSConfiguration == nil {
-		r.Spec.Service.Datastreams.TLSConfiguration = &TLSConfiguration{Enabled: false}
+// validateStorage checks that the database runs on exactly one storage
+// cluster, the Storage of the operator or an external one
+func (r *Database) validateStorage() error {
+	if (r.Spec.StorageClusterRef == nil) == (r.Spec.ExternalStorageEndpoint == nil) {
+		return errors.New("exactly one of spec.storageClusterRef and spec.externalStorageEndpoint must be set")
 	}
+	if r.Spec.ExternalStorageEndpoint == nil {
+		return nil
+	}
+	if locality := r.Spec.StorageLocality; locality != nil && (locality.SameZone != "" || locality.AvoidStorageNodes) {
+		return errors.New("spec.storageLocality.sameZone and avoidStorageNodes follow the storage pods, they cannot be used with spec.externalStorageEndpoint")
+	}
+	return r.Spec.ExternalStorageEndpoint.Validate()
+}
 
-	if r.Spec.Domain == "" {
-		r.Spec.Domain = DefaultDatabaseDomain // FIXME
+// validatePath checks that spec.path is a clean path in the domain
+func (r *Database) validatePath() error {
+	if r.Spec.Path == "" {
+		return nil
+	}
+	domain := r.Spec.Domain
+	if domain == "" {
+		domain = DefaultDatabaseDomain
+	}
+	prefix := "/" + domain + "/"
+	if !strings.HasPrefix(r.Spec.Path, prefix) {
+		return fmt.Errorf("spec.path %s must be in the domain, starting with %s", r.Spec.Path, prefix)
 	}
+	for _, name := range strings.Split(strings.TrimPrefix(r.Spec.Path, prefix), "/") {
+		if name == "" || name == "." || name == ".." {
+			return fmt.Errorf("spec.path %s must not have empty, . or .. elements", r.Spec.Path)
+		}
+	}
+	return nil
+}
 
-	if r.Spec.Encryption == nil {
-		r.Spec.Encryption = &EncryptionConfig{Enabled: false}
+// validateTiering checks the tier names, as they name the credentials
+// directories in the pods
+func (r *Database) validateTiering() error {
+	if r.Spec.Tiering == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, tier := range r.Spec.Tiering.Tiers {
+		if names[tier.Name] {
+			return fmt.Errorf("duplicate tier name %q in spec.tiering.tiers", tier.Name)
+		}
+		names[tier.Name] = true
 	}
+	return nil
+}
 
-	if r.Spec.Datastreams == nil {
-		r.Spec.Datastreams = &DatastreamsConfig{Enabled: false}
+// validateTenantOptions checks that the plan resolution fits CMS, which
+// takes whole milliseconds
+func (r *Database) validateTenantOptions() error {
+	if r.Spec.TenantOptions == nil || r.Spec.TenantOptions.PlanResolution == nil {
+		return nil
+	}
+	resolution := r.Spec.TenantOptions.PlanResolution.Duration
+	if resolution < time.Millisecond || resolution > time.Minute {
+		return fmt.Errorf("spec.tenantOptions.planResolution %s must be between 1ms and 1m", resolution)
 	}
+	return nil
+}
 
-	if r.Spec.Monitoring == nil {
-		r.Spec.Monitoring = &MonitoringOptions{
-			Enabled: false,
+// validateInitScripts checks the names, status tracks the scripts by them
+func (r *Database) validateInitScripts() error {
+	names := map[string]bool{}
+	for _, script := range r.Spec.InitScripts {
+		if names[script.Name] {
+			return fmt.Errorf("duplicate script name %q in spec.initScripts", script.Name)
+		}
+		names[script.Name] = true
+		if (script.Script == "") == (script.ConfigMapRef == nil) {
+			return fmt.Errorf("exactly one of script and configMapRef must be set in spec.initScripts %q", script.Name)
 		}
 	}
+	return nil
 }
 
-//+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-database,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=databases,verbs=create;update,versions=v1alpha1,name=validate-database.ydb.tech,admissionReviewVersions=v1
-
-var _ webhook.Validator = &Database{}
+// validateReadReplicas checks the table paths, status tracks the tables by
+// them and they are put into ALTER TABLE statements
+func (r *Database) validateReadReplicas() error {
+	tables := map[string]bool{}
+	for _, replicas := range r.Spec.ReadReplicas {
+		if tables[replicas.Table] {
+			return fmt.Errorf("duplicate table %q in spec.readReplicas", replicas.Table)
+		}
+		tables[replicas.Table] = true
+		if strings.HasPrefix(replicas.Table, "/") || strings.Contains(replicas.Table, "`") {
+			return fmt.Errorf("table %q in spec.readReplicas must be relative to the database and have no backticks", replicas.Table)
+		}
+		for _, name := range strings.Split(replicas.Table, "/") {
+			if name == "" || name == "." || name == ".." {
+				return fmt.Errorf("table %q in spec.readReplicas must not have empty, . or .. elements", replicas.Table)
+			}
+		}
+	}
+	return nil
+}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *Database) ValidateCreate() error {
-	databaselog.Info("validate create", "name", r.Name)
+// reservedArgs are the ydbd options set by the operator, repeating them in
+// spec.additionalArgs would make the nodes listen or register elsewhere
+var reservedArgs = []string{
+	"--mon-port",
+	"--ic-port",
+	"--grpc-port",
+	"--yaml-config",
+	"--tenant",
+	"--node",
+	"--node-broker",
+	"--node-host",
+	"--node-address",
+	"--grpc-public-host",
+	"--grpc-public-port",
+}
 
-	if r.Spec.Resources == nil && r.Spec.SharedResources == nil && r.Spec.ServerlessResources == nil {
-		return errors.New("incorrect database resources configuration, must be one of: Resources, SharedResources, ServerlessResources")
+func validateAdditionalArgs(args []string) error {
+	for _, arg := range args {
+		for _, reserved := range reservedArgs {
+			if arg == reserved || strings.HasPrefix(arg, reserved+"=") {
+				return fmt.Errorf("spec.additionalArgs must not set %s, the operator sets it", reserved)
+			}
+		}
 	}
-
-	// TODO(user): fill in your validation logic upon object creation.
 	return nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Database) ValidateUpdate(old runtime.Object) error {
-	databaselog.Info("validate update", "name", r.Name)
+// validateAttributes rejects empty values, CMS treats them as removal of
+// the attribute
+func (r *Database) validateAttributes() error {
+	for key, value := range r.Spec.Attributes {
+		if key == "" {
+			return errors.New("spec.attributes keys must not be empty")
+		}
+		if value == "" {
+			return fmt.Errorf("value of spec.attributes %q must not be empty", key)
+		}
+	}
+	return nil
+}
 
-	// TODO(user): fill in your validation logic upon object update.
+// validateQuotas checks the data size limits and the periods of the schema
+// operation quotas, CMS counts them in whole seconds
+func (r *Database) validateQuotas() error {
+	if quota := r.Spec.DataSizeQuota; quota != nil {
+		if quota.Hard.Sign() <= 0 {
+			return fmt.Errorf("spec.dataSizeQuota.hard must be positive, got %s", quota.Hard.String())
+		}
+		if quota.Soft != nil && quota.Soft.Cmp(quota.Hard) >= 0 {
+			return fmt.Errorf("spec.dataSizeQuota.soft %s must be lower than hard %s", quota.Soft.String(), quota.Hard.String())
+		}
+	}
+	if r.Spec.ServerlessResources == nil || r.Spec.ServerlessResources.Quotas == nil {
+		return nil
+	}
+	for _, quota := range r.Spec.ServerlessResources.Quotas.SchemaOperations {
+		if quota.Period.Duration < time.Second {
+			return fmt.Errorf("period %s of spec.serverlessResources.quotas.schemaOperations is shorter than 1s", quota.Period.Duration)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,106 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DynamicConfigSpec defines the desired state of DynamicConfig
+type DynamicConfigSpec struct {
+	// YDB Storage cluster the configuration is applied to
+	// +required
+	StorageRef StorageRef `json:"storageRef"`
+
+	// Dynamic configuration document in YAML, as accepted by
+	// `ydb admin config replace`: the metadata section with the cluster name
+	// and the version the document replaces, and the config section
+	// +required
+	Config string `json:"config"`
+
+	// (Optional) Accept configuration fields unknown to the YDB version of
+	// the storage
+	// Default: false
+	// +optional
+	AllowUnknownFields bool `json:"allowUnknownFields,omitempty"`
+}
+
+// DynamicConfigStatus defines the observed state of DynamicConfig
+type DynamicConfigStatus struct {
+	State      string             `json:"state"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Generation of the resource applied last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Version YDB assigned to the configuration applied last, the next
+	// document has to carry it in metadata.version
+	AppliedVersion uint64 `json:"appliedVersion,omitempty"`
+
+	// Error of the last attempt
+	Message string `json:"message,omitempty"`
+}
+
+// DynamicConfigMetadata is the metadata section of a dynamic configuration
+// document
+type DynamicConfigMetadata struct {
+	Kind    string `yaml:"kind"`
+	Cluster string `yaml:"cluster"`
+	Version uint64 `yaml:"version"`
+}
+
+// ParseDynamicConfigMetadata checks the layout of a dynamic configuration
+// document and returns its metadata. The settings themselves are checked by
+// YDB when the document is applied.
+func ParseDynamicConfigMetadata(config string) (*DynamicConfigMetadata, error) {
+	document := struct {
+		Metadata *DynamicConfigMetadata `yaml:"metadata"`
+		Config   map[string]interface{} `yaml:"config"`
+	}{}
+	if err := yaml.Unmarshal([]byte(config), &document); err != nil {
+		return nil, fmt.Errorf("failed to parse the dynamic config: %w", err)
+	}
+	if document.Metadata == nil {
+		return nil, errors.New("the dynamic config has no metadata section")
+	}
+	if document.Config == nil {
+		return nil, errors.New("the dynamic config has no config section")
+	}
+	return document.Metadata, nil
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:categories=ydb-all
+//+kubebuilder:printcolumn:name="Storage",type="string",JSONPath=".spec.storageRef.name"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this configuration"
+//+kubebuilder:printcolumn:name="Version",type="integer",JSONPath=".status.appliedVersion",description="Version YDB assigned to the applied configuration"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DynamicConfig is the Schema for the dynamicconfigs API. The document is
+// applied through the dynamic configuration service of the console, which
+// distributes it to the nodes without restarts.
+type DynamicConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DynamicConfigSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default:={state: "Pending"}
+	Status DynamicConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DynamicConfigList contains a list of DynamicConfig
+type DynamicConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DynamicConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DynamicConfig{}, &DynamicConfigList{})
+}
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var dynamicconfiglog = logf.Log.WithName("dynamicconfig-resource")
+
+func (r *DynamicConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-dynamicconfig,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=dynamicconfigs,verbs=create;update,versions=v1alpha1,name=validate-dynamicconfig.ydb.tech,admissionReviewVersions=v1
+
+var _ webhook.Validator = &DynamicConfig{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *DynamicConfig) ValidateCreate() error {
+	dynamicconfiglog.Info("validate create", "name", r.Name)
+
+	_, err := ParseDynamicConfigMetadata(r.Spec.Config)
+	return err
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *DynamicConfig) ValidateUpdate(old runtime.Object) error {
+	dynamicconfiglog.Info("validate update", "name", r.Name)
+
+	_, err := ParseDynamicConfigMetadata(r.Spec.Config)
+	return err
+}
+
+func (r *DynamicConfig) ValidateDelete() error {
+	return nil
+}
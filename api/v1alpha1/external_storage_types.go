@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExternalStorageEndpoint is a YDB storage cluster the operator does not
+// manage. Tenants of the Database are created in its CMS and the dynamic
+// nodes register in its node broker, the cluster itself is neither watched
+// nor changed.
+type ExternalStorageEndpoint struct {
+	// gRPC endpoint of the storage cluster, host:port
+	// +required
+	Endpoint string `json:"endpoint"`
+
+	// (Optional) Domain of the storage cluster
+	// Default: root
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// Configuration of the storage cluster in YAML, the configuration of
+	// the dynamic nodes is generated from it. It has to list the hosts of
+	// the static nodes and the domains, there are no pods to generate them
+	// from.
+	// +required
+	Configuration string `json:"configuration"`
+
+	// (Optional) TLS of the gRPC endpoint
+	// Default: disabled
+	// +optional
+	TLS *ExternalStorageTLS `json:"tls,omitempty"`
+}
+
+type ExternalStorageTLS struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// (Optional) Secret key with the CA the certificate of the endpoint is
+	// verified with, by the operator and by the dynamic nodes
+	// Default: (system trust store)
+	// +optional
+	CertificateAuthority *corev1.SecretKeySelector `json:"CA,omitempty"`
+}
+
+// Secure reports whether the endpoint is called over TLS
+func (e *ExternalStorageEndpoint) Secure() bool {
+	return e.TLS != nil && e.TLS.Enabled
+}
+
+// CA returns the selector of the CA of the endpoint, nil when the system
+// trust store is used
+func (e *ExternalStorageEndpoint) CA() *corev1.SecretKeySelector {
+	if !e.Secure() {
+		return nil
+	}
+	return e.TLS.CertificateAuthority
+}
+
+// Validate checks the endpoint and the CA reference, the nodes fail to
+// register in the node broker without them
+func (e *ExternalStorageEndpoint) Validate() error {
+	if e == nil {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(e.Endpoint)
+	if err != nil || host == "" {
+		return fmt.Errorf("spec.externalStorageEndpoint.endpoint %q is not a host:port", e.Endpoint)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("spec.externalStorageEndpoint.endpoint %q has an invalid port", e.Endpoint)
+	}
+	if e.Configuration == "" {
+		return errors.New("spec.externalStorageEndpoint.configuration must be set")
+	}
+	if ca := e.CA(); ca != nil && (ca.Name == "" || ca.Key == "") {
+		return errors.New("spec.externalStorageEndpoint.tls.CA must name a secret and a key")
+	}
+	return nil
+}
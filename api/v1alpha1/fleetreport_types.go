@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FleetReportSpec defines the desired state of FleetReport
+type FleetReportSpec struct {
+	// (Optional) How often the report is regenerated
+	// Default: 5m
+	// +kubebuilder:default:="5m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// (Optional) Namespaces to report on, all namespaces when empty
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// FleetReportStatus defines the observed state of FleetReport
+type FleetReportStatus struct {
+	// Time the report was generated
+	GeneratedAt *metav1.Time `json:"generatedAt,omitempty"`
+
+	// Generation of the spec the report was generated for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	Storages  FleetSummary `json:"storages,omitempty"`
+	Databases FleetSummary `json:"databases,omitempty"`
+}
+
+// FleetSummary counts the resources of a kind
+type FleetSummary struct {
+	Total int32 `json:"total"`
+
+	// Number of resources by status.state
+	States map[string]int32 `json:"states,omitempty"`
+
+	// Number of resources by the YDB version they run, as reported by the
+	// nodes or taken from the image tag
+	Versions map[string]int32 `json:"versions,omitempty"`
+
+	// Number of resources with the Stalled condition
+	StalledCount int32 `json:"stalledCount"`
+
+	// Stalled resources, the longest stalled first
+	Stalled []FleetStalledResource `json:"stalled,omitempty"`
+}
+
+type FleetStalledResource struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+
+	// Time the resource entered the state
+	Since *metav1.Time `json:"since,omitempty"`
+
+	// Message of the Stalled condition
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,categories=ydb-all
+//+kubebuilder:printcolumn:name="Storages",type="integer",JSONPath=".status.storages.total"
+//+kubebuilder:printcolumn:name="Databases",type="integer",JSONPath=".status.databases.total"
+//+kubebuilder:printcolumn:name="Stalled",type="integer",JSONPath=".status.databases.stalledCount",description="Number of stalled databases"
+//+kubebuilder:printcolumn:name="Generated",type="date",JSONPath=".status.generatedAt"
+
+// FleetReport is the Schema for the fleetreports API. The operator
+// summarizes the Storages and Databases it manages in the status, so owners
+// of large fleets get their health without listing every resource.
+type FleetReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FleetReportSpec   `json:"spec,omitempty"`
+	Status FleetReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FleetReportList contains a list of FleetReport
+type FleetReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FleetReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FleetReport{}, &FleetReportList{})
+}
@@ -0,0 +1,22 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// HistoryEntry records a significant action taken by the operator
+type HistoryEntry struct {
+	// Action taken, e.g. TenantCreated or Scaled
+	Action string `json:"action"`
+
+	// Time the action was taken
+	Time metav1.Time `json:"time"`
+
+	// Outcome of the action, Succeeded or Failed
+	Outcome string `json:"outcome"`
+
+	// Generation of the spec that initiated the action
+	Generation int64 `json:"generation"`
+
+	// (Optional) Details of the action
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sync"
+)
+
+var imageDefaults = struct {
+	sync.RWMutex
+	repository string
+	tag        string
+}{repository: RegistryPath, tag: DefaultTag}
+
+// SetImageDefaults overrides the repository and tag of the images set by
+// the defaulting webhooks. Empty values restore the built-in defaults.
+func SetImageDefaults(repository, tag string) {
+	if repository == "" {
+		repository = RegistryPath
+	}
+	if tag == "" {
+		tag = DefaultTag
+	}
+	imageDefaults.Lock()
+	defer imageDefaults.Unlock()
+	imageDefaults.repository = repository
+	imageDefaults.tag = tag
+}
+
+// DefaultImageName returns the image for the given YDB version, the default
+// tag is used when the version is empty
+func DefaultImageName(version string) string {
+	imageDefaults.RLock()
+	defer imageDefaults.RUnlock()
+	if version == "" {
+		version = imageDefaults.tag
+	}
+	return fmt.Sprintf(ImagePathFormat, imageDefaults.repository, version)
+}
@@ -0,0 +1,12 @@
+package v1alpha1
+
+// ResourceLinks point at the observability pages of a Storage or Database
+type ResourceLinks struct {
+	// URL of the embedded UI
+	// +optional
+	UI string `json:"ui,omitempty"`
+
+	// URL of the Grafana dashboard
+	// +optional
+	Grafana string `json:"grafana,omitempty"`
+}
@@ -1,6 +1,9 @@
 package v1alpha1
 
-import v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+import (
+	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 type MonitoringOptions struct {
 	Enabled bool `json:"enabled"`
@@ -9,4 +12,22 @@ type MonitoringOptions struct {
 	Interval string `json:"interval,omitempty"`
 	// RelabelConfig allows dynamic rewriting of the label set, being applied to sample before ingestion.
 	MetricRelabelings []*v1.RelabelConfig `json:"metricRelabelings,omitempty"`
+
+	// (Optional) Scrape the metrics with basic auth credentials of this resource
+	// only, instead of access shared with the storage. The operator generates the
+	// credentials into the <name>-monitoring Secret along with the endpoints, so
+	// the Secret can be handed to the team owning the resource, and registers
+	// them as the YDB user monitoring_<name>, of the database or of the root
+	// domain of the storage. The status port has to enforce the credentials,
+	// e.g. with YDB authentication enabled.
+	// +optional
+	IsolatedCredentials bool `json:"isolatedCredentials,omitempty"`
+
+	// (Optional) Regenerate the isolated credentials after this time, e.g.
+	// 720h. The username, the password and the endpoints are updated in the
+	// Secret at once, scrapers pick the new password up from it. They are
+	// also regenerated on demand with the ydb.tech/rotate-credentials
+	// annotation
+	// +optional
+	CredentialsRotationInterval *metav1.Duration `json:"credentialsRotationInterval,omitempty"`
 }
@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type OperationKind string
+
+const (
+	OperationDrainNode      OperationKind = "DrainNode"
+	OperationReassignGroups OperationKind = "ReassignGroups"
+	OperationSetConfig      OperationKind = "SetConfig"
+)
+
+// OperationSpec defines the desired state of Operation
+type OperationSpec struct {
+	// YDB Storage cluster the operation is executed against
+	// +required
+	StorageRef StorageRef `json:"storageRef"`
+
+	// Kind of the operation, the matching parameters field must be set
+	// +kubebuilder:validation:Enum=DrainNode;ReassignGroups;SetConfig
+	// +required
+	Kind OperationKind `json:"kind"`
+
+	// (Optional) Parameters of the DrainNode operation
+	// +optional
+	DrainNode *DrainNodeOperation `json:"drainNode,omitempty"`
+
+	// (Optional) Parameters of the ReassignGroups operation
+	// +optional
+	ReassignGroups *ReassignGroupsOperation `json:"reassignGroups,omitempty"`
+
+	// (Optional) Parameters of the SetConfig operation
+	// +optional
+	SetConfig *SetConfigOperation `json:"setConfig,omitempty"`
+
+	// (Optional) Number of attempts before the operation is marked Failed
+	// Default: 3
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+}
+
+type DrainNodeOperation struct {
+	// Id of the node to move tablets and VDisks off
+	// +required
+	NodeID uint32 `json:"nodeId"`
+}
+
+type ReassignGroupsOperation struct {
+	// VDisks to move to other PDisks
+	// +kubebuilder:validation:MinItems:=1
+	// +required
+	VDisks []VDiskID `json:"vdisks"`
+}
+
+type VDiskID struct {
+	GroupID         uint32 `json:"groupId"`
+	GroupGeneration uint32 `json:"groupGeneration"`
+	FailRealmIdx    uint32 `json:"failRealmIdx"`
+	FailDomainIdx   uint32 `json:"failDomainIdx"`
+	VDiskIdx        uint32 `json:"vdiskIdx"`
+}
+
+type SetConfigOperation struct {
+	// Console request in protobuf text format, as accepted by `ydbd admin console execute`
+	// +required
+	Request string `json:"request"`
+}
+
+// OperationStatus defines the observed state of Operation
+type OperationStatus struct {
+	State      string             `json:"state"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Number of attempts made so far
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Output of the last attempt
+	Message string `json:"message,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:categories=ydb-all
+//+kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.kind"
+//+kubebuilder:printcolumn:name="Storage",type="string",JSONPath=".spec.storageRef.name"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this operation"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Operation is the Schema for the operations API
+type Operation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OperationSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default:={state: "Pending"}
+	Status OperationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OperationList contains a list of Operation
+type OperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Operation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Operation{}, &OperationList{})
+}
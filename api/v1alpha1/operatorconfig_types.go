@@ -0,0 +1,167 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the only OperatorConfig the operator reads
+const OperatorConfigName = "default"
+
+// OperatorConfigSpec defines operator-wide settings. Unset fields fall back
+// to the defaults of the operator.
+type OperatorConfigSpec struct {
+	// (Optional) Mark Storage and Database resources Stalled after spending
+	// this long in Provisioning or Initializing, zero disables the check
+	// +optional
+	StalledThreshold *metav1.Duration `json:"stalledThreshold,omitempty"`
+
+	// (Optional) Period of the full sync of child resources when the spec
+	// does not change
+	// +optional
+	ResourcesResyncPeriod *metav1.Duration `json:"resourcesResyncPeriod,omitempty"`
+
+	// (Optional) Delete Jobs of Storage and Database resources this long
+	// after they finish, zero keeps them
+	// +optional
+	FinishedJobTTL *metav1.Duration `json:"finishedJobTTL,omitempty"`
+
+	// (Optional) Number of superseded revisions of the configuration
+	// ConfigMap kept for every Storage and Database, zero keeps no revisions
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	ConfigRevisionHistory *int32 `json:"configRevisionHistory,omitempty"`
+
+	// (Optional) Minimum interval between tenant operations sent to the CMS
+	// of the same Storage
+	// +optional
+	CMSOperationInterval *metav1.Duration `json:"cmsOperationInterval,omitempty"`
+
+	// (Optional) Maximum number of tenants of the same Storage initializing
+	// at once, zero means no limit
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxConcurrentTenantInitializations *int32 `json:"maxConcurrentTenantInitializations,omitempty"`
+
+	// (Optional) Number of storage pods checked at once while waiting for the
+	// PDisks to be formatted on the first boot
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	PDiskCheckConcurrency *int32 `json:"pdiskCheckConcurrency,omitempty"`
+
+	// (Optional) Image used for Storage and Database resources that set
+	// neither image nor version
+	// +optional
+	DefaultImage *DefaultImage `json:"defaultImage,omitempty"`
+
+	// (Optional) Operator features to turn on or off, e.g. StorageAutoscaling
+	// or ResourceUsageReporting
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// (Optional) Metrics collection options
+	// +optional
+	Metrics *OperatorMetrics `json:"metrics,omitempty"`
+
+	// (Optional) Source of the releases rolled by Storage and Database
+	// auto-update policies
+	// +optional
+	VersionManifest *VersionManifest `json:"versionManifest,omitempty"`
+
+	// (Optional) Links published in the status and annotations of Storage
+	// and Database resources
+	// +optional
+	Dashboards *Dashboards `json:"dashboards,omitempty"`
+
+	// (Optional) Maximum number of Databases per namespace, Databases over
+	// the limit are held with the QuotaExceeded condition. 0 means no limit
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxDatabasesPerNamespace *int32 `json:"maxDatabasesPerNamespace,omitempty"`
+
+	// (Optional) Time a Database reconcile may run before its remaining
+	// tenant steps are deferred to a new queue item, zero means no limit
+	// +optional
+	ReconcileBudget *metav1.Duration `json:"reconcileBudget,omitempty"`
+
+	// (Optional) Attach the changed fields to the ResourceUpdated events,
+	// they are always logged
+	// +optional
+	EventDiffs *bool `json:"eventDiffs,omitempty"`
+}
+
+type DefaultImage struct {
+	// (Optional) Image repository, also used for resources that only set the version
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// (Optional) Image tag
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+type OperatorMetrics struct {
+	// (Optional) Interval of pods CPU and memory usage collection
+	// +optional
+	ResourceUsageInterval *metav1.Duration `json:"resourceUsageInterval,omitempty"`
+}
+
+type VersionManifest struct {
+	// (Optional) URL of the JSON manifest listing the released versions per
+	// channel, e.g. {"channels": {"stable": ["23.1.19", "23.1.26"]}}
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// (Optional) Interval between manifest downloads
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// Dashboards are Go templates of URLs, executed with .Kind, .Namespace,
+// .Name and .Path, the database path, empty for Storage. An empty template
+// keeps the default one of the operator.
+type Dashboards struct {
+	// (Optional) URL of the embedded UI, e.g.
+	// https://ydb.example.com/{{.Namespace}}/{{.Name}}/monitoring/
+	// +optional
+	UIURLTemplate string `json:"uiURLTemplate,omitempty"`
+
+	// (Optional) URL of the Grafana dashboard, e.g.
+	// https://grafana.example.com/d/ydb?var-namespace={{.Namespace}}&var-database={{.Path}}
+	// +optional
+	GrafanaURLTemplate string `json:"grafanaURLTemplate,omitempty"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	// Generation of the spec applied by the operator
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,categories=ydb-all
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OperatorConfig is the Schema for the operatorconfigs API. The operator
+// reads only the one named "default" and reloads it on change.
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// DatabaseProxy describes an optional connection proxy placed in front of
+// the database dynamic nodes. It is meant for clients that cannot use
+// client-side balancing and need a single stable endpoint.
+type DatabaseProxy struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// Proxy container image. The proxy receives the upstream gRPC endpoint
+	// in YDB_ENDPOINT and the database path in YDB_DATABASE environment variables.
+	// +required
+	Image PodImage `json:"image,omitempty"`
+
+	// (Optional) Arguments passed to the proxy container
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// (Optional) Number of proxy replicas, ignored when autoscaling is enabled
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// (Optional) Proxy container resource limits
+	// Default: (not specified)
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// (Optional) Proxy service parameter overrides
+	// +optional
+	Service Service `json:"service,omitempty"`
+
+	// (Optional) Horizontal autoscaling of the proxy Deployment
+	// +optional
+	Autoscaling *ProxyAutoscaling `json:"autoscaling,omitempty"`
+}
+
+type ProxyAutoscaling struct {
+	// (Optional) Lower limit for the number of proxy replicas
+	// Default: 1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Upper limit for the number of proxy replicas
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// (Optional) Target average CPU utilization over all proxy pods
+	// Default: 80
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
@@ -1,6 +1,13 @@
 package v1alpha1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 type Service struct {
 	AdditionalLabels      map[string]string `json:"additionalLabels,omitempty"`
@@ -8,6 +15,13 @@ type Service struct {
 
 	IPFamilies     []corev1.IPFamily          `json:"ipFamilies,omitempty"`
 	IPFamilyPolicy *corev1.IPFamilyPolicyType `json:"ipFamilyPolicy,omitempty"`
+
+	// (Optional) Route only to the nodes whose pods carry the labels of
+	// spec.additionalPodLabels, so a pod is taken out of the Service by
+	// relabeling it, e.g. while debugging. Ignored by the interconnect
+	// Service, the nodes find each other through it, and the proxy ones.
+	// +optional
+	SelectAdditionalPodLabels bool `json:"selectAdditionalPodLabels,omitempty"`
 }
 
 type TLSConfiguration struct {
@@ -15,19 +29,109 @@ type TLSConfiguration struct {
 	CertificateAuthority corev1.SecretKeySelector `json:"CA,omitempty"`
 	Certificate          corev1.SecretKeySelector `json:"certificate,omitempty"`
 	Key                  corev1.SecretKeySelector `json:"key,omitempty"` // fixme validate: all three or none
+	// (Optional) The operator issues the certificate from its own CA into
+	// the <name>-tls Secret, with the names of the services and of every
+	// pod, and reissues it when they change. CA, certificate and key
+	// selectors are ignored
+	// +optional
+	OperatorManaged bool `json:"operatorManaged,omitempty"`
 }
 
 type GRPCService struct {
 	Service `json:""`
 
+	// +kubebuilder:default:={enabled: false}
 	TLSConfiguration *TLSConfiguration `json:"tls,omitempty"`
 	ExternalHost     string            `json:"externalHost,omitempty"` // TODO implementation
+
+	// (Optional) Settings of the gRPC server of the nodes, rendered into
+	// grpc_config. Settings of a Database replace the ones of its Storage.
+	// +optional
+	Server *GRPCServerConfig `json:"server,omitempty"`
+}
+
+// GRPCServerConfig holds the limits of the gRPC server clients run into.
+// Compression is not among them: the YDB server has no setting for it and
+// compresses responses only when a client asks for it.
+type GRPCServerConfig struct {
+	// (Optional) Maximum size of a request or response message
+	// Default: (YDB default, 64Mi)
+	// +optional
+	MaxMessageSize *resource.Quantity `json:"maxMessageSize,omitempty"`
+
+	// (Optional) TCP keepalive of client connections, so the nodes drop
+	// connections of clients gone without closing them
+	// +optional
+	KeepAlive *GRPCKeepAlive `json:"keepAlive,omitempty"`
+}
+
+type GRPCKeepAlive struct {
+	// (Optional) Probe idle connections
+	// Default: true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// (Optional) Time a connection has to be idle before it is probed
+	// Default: (YDB default, 90s)
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// (Optional) Interval between the probes
+	// Default: (YDB default, 10s)
+	// +optional
+	ProbeInterval *metav1.Duration `json:"probeInterval,omitempty"`
+
+	// (Optional) Number of unanswered probes before the connection is dropped
+	// Default: (YDB default, 3)
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxProbeCount *int32 `json:"maxProbeCount,omitempty"`
+}
+
+// Validate checks what the schema can't: positive sizes and durations of
+// whole seconds, as YDB takes them in seconds
+func (c *GRPCServerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxMessageSize != nil && c.MaxMessageSize.Sign() <= 0 {
+		return fmt.Errorf("grpc server maxMessageSize must be positive, got %s", c.MaxMessageSize.String())
+	}
+	if c.KeepAlive == nil {
+		return nil
+	}
+	if timeout := c.KeepAlive.IdleTimeout; timeout != nil && timeout.Duration < time.Second {
+		return fmt.Errorf("grpc server keepAlive.idleTimeout %s is shorter than 1s", timeout.Duration)
+	}
+	if interval := c.KeepAlive.ProbeInterval; interval != nil && interval.Duration < time.Second {
+		return fmt.Errorf("grpc server keepAlive.probeInterval %s is shorter than 1s", interval.Duration)
+	}
+	return nil
 }
 
 type InterconnectService struct {
 	Service `json:""`
 
+	// +kubebuilder:default:={enabled: false}
 	TLSConfiguration *TLSConfiguration `json:"tls,omitempty"`
+
+	// (Optional) Address family the nodes resolve and connect to each
+	// other with. The headless service is made single stack of the family,
+	// unless ipFamilies is set, so its DNS names only have records of it.
+	// Default: (resolver behavior)
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	// +optional
+	AddressFamily corev1.IPFamily `json:"addressFamily,omitempty"`
+}
+
+// ServiceIPFamilies returns the IP families and the policy of the headless
+// service, derived from AddressFamily when they are not set explicitly
+func (s *InterconnectService) ServiceIPFamilies() ([]corev1.IPFamily, *corev1.IPFamilyPolicyType) {
+	if s.AddressFamily == "" || len(s.IPFamilies) > 0 {
+		return s.IPFamilies, s.IPFamilyPolicy
+	}
+	policy := corev1.IPFamilyPolicySingleStack
+	return []corev1.IPFamily{s.AddressFamily}, &policy
 }
 
 type StatusService struct {
@@ -37,5 +141,6 @@ type StatusService struct {
 type DatastreamsService struct {
 	Service `json:""`
 
+	// +kubebuilder:default:={enabled: false}
 	TLSConfiguration *TLSConfiguration `json:"tls,omitempty"`
 }
@@ -1,16 +1,54 @@
 package v1alpha1
 
 import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // StorageSpec defines the desired state of Storage
 type StorageSpec struct {
-	// Number of nodes (pods) in the cluster
+	// Number of pods in the cluster
 	// +required
 	Nodes int32 `json:"nodes"`
 
+	// (Optional) Number of static nodes run by every pod, for dense hosts with
+	// many disks. Each node runs in its own container with the interconnect,
+	// gRPC and status ports shifted by its index within the pod, and gets its
+	// own volume of every data store. The first host config of the
+	// configuration describes the drives of the first node, the host configs
+	// of the other nodes are derived from it with their own devices.
+	// Resources apply to every node container.
+	// Default: 1
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=16
+	// +kubebuilder:default:=1
+	// +optional
+	NodesPerPod int32 `json:"nodesPerPod,omitempty"`
+
+	// (Optional) Number of standby pods run in addition to nodes. Spare pods
+	// get their disks formatted and are listed in the generated hosts, but
+	// their drives are decommitted, out of group placement. While a storage
+	// pod is not ready, the drives of a spare pod are enabled, so self heal
+	// moves the VDisks of the failed node to them right away instead of
+	// waiting for a new volume, and decommitted again once all the pods are
+	// ready. Spare pods are not added when the configuration lists the hosts
+	// itself.
+	// Default: 0
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	SpareNodes int32 `json:"spareNodes,omitempty"`
+
+	// (Optional) Maximum number of Databases using the storage. Databases
+	// over the limit are held with the QuotaExceeded condition, the ones
+	// with an initialized tenant and then the oldest are admitted first.
+	// Default: 0, no limit
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxDatabases int32 `json:"maxDatabases,omitempty"`
+
 	// YDB configuration in YAML format. Will be applied on top of generated one in internal/configuration
 	// +optional
 	Configuration string `json:"configuration"`
@@ -23,12 +61,29 @@ type StorageSpec struct {
 	// +kubebuilder:default:=block-4-2
 	Erasure ErasureType `json:"erasure"`
 
+	// (Optional) What to do when spec.nodes is not a multiple of the nodes
+	// the erasure places its groups on, which leaves the remainder of the
+	// nodes unutilized. Warn admits the spec and reports the utilized nodes
+	// in status, Reject has the admission webhook reject it.
+	// Default: Warn
+	// +kubebuilder:validation:Enum=Warn;Reject
+	// +kubebuilder:default:=Warn
+	// +optional
+	UnutilizedNodesPolicy UnutilizedNodesPolicy `json:"unutilizedNodesPolicy,omitempty"`
+
 	// Where cluster data should be kept
 	// +required
 	DataStore []corev1.PersistentVolumeClaimSpec `json:"dataStore"`
 
+	// (Optional) How the storage nodes access the disks of the data stores.
+	// Switching between FileBacked and the other modes requires new volumes
+	// and is only possible on a new Storage.
+	// +optional
+	DiskAccess *DiskAccess `json:"diskAccess,omitempty"`
+
 	// (Optional) Storage services parameter overrides
 	// Default: (not specified)
+	// +kubebuilder:default:={}
 	// +optional
 	Service StorageServices `json:"service,omitempty"`
 
@@ -40,16 +95,40 @@ type StorageSpec struct {
 	// +optional
 	Domain string `json:"domain"`
 
+	// (Optional) Names of additional storage domains Databases can be
+	// created in. Domains missing from domains_config of the configuration
+	// are generated as copies of the root domain with their own name and
+	// domain_id.
+	// +kubebuilder:validation:items:Pattern:=[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?
+	// +kubebuilder:validation:items:MaxLength:=63
+	// +optional
+	AdditionalDomains []string `json:"additionalDomains,omitempty"`
+
 	// (Optional) Storage container resource limits. Any container limits
 	// can be specified.
 	// Default: (not specified)
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// (Optional) Hugepages requested by the storage containers and mounted
+	// at /dev/hugepages
+	// +optional
+	HugePages *HugePages `json:"hugePages,omitempty"`
+
+	// (Optional) Kernel parameters required by the storage nodes
+	// +optional
+	Sysctls *Sysctls `json:"sysctls,omitempty"`
+
 	// Container image information
 	// +required
 	Image PodImage `json:"image,omitempty"`
 
+	// (Optional) Additional arguments appended to the ydbd command line,
+	// e.g. to enable experimental features. The arguments the operator
+	// sets itself are rejected.
+	// +optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+
 	// List of initialization containers belonging to the pod.
 	// Init containers are executed in order prior to containers being started. If any
 	// init container fails, the pod is considered to have failed and is handled according
@@ -73,9 +152,14 @@ type StorageSpec struct {
 
 	// (Optional) Monitoring sets configuration options for YDB observability
 	// Default: ""
+	// +kubebuilder:default:={enabled: false}
 	// +optional
 	Monitoring *MonitoringOptions `json:"monitoring,omitempty"`
 
+	// (Optional) OIDC login in front of the embedded UI
+	// +optional
+	UIAuth *UIAuth `json:"uiAuth,omitempty"`
+
 	// User-defined root certificate authority that is added to system trust
 	// store of Storage pods on startup.
 	// +optional
@@ -101,6 +185,25 @@ type StorageSpec struct {
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// (Optional) Pod management policy of the StatefulSet. Parallel starts all
+	// pods at once, OrderedReady starts them one by one. Changing the policy
+	// recreates the StatefulSet, leaving the running pods in place.
+	// Default: Parallel
+	// +kubebuilder:validation:Enum=Parallel;OrderedReady
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// (Optional) Add the ydb.tech/node-ready readiness gate to the pods. The operator
+	// sets it once the node answers the gRPC health service,
+	// so Services do not route to nodes that are still starting.
+	// Default: false
+	// +optional
+	ReadinessGate bool `json:"readinessGate,omitempty"`
+
+	// (Optional) Automatic updates to new patch releases of the current version
+	// +optional
+	AutoUpdate *AutoUpdatePolicy `json:"autoUpdate,omitempty"`
+
 	// (Optional) Additional custom resource labels that are added to all resources
 	// +optional
 	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
@@ -108,17 +211,173 @@ type StorageSpec struct {
 	// (Optional) Additional custom resource annotations that are added to all resources
 	// +optional
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
+
+	// (Optional) Additional labels of the pods of the nodes, the labels of
+	// the operator take precedence. Services with selectAdditionalPodLabels
+	// only route to the pods carrying them, relabel a pod to take it out.
+	// +optional
+	AdditionalPodLabels map[string]string `json:"additionalPodLabels,omitempty"`
+
+	// (Optional) Storage unit kinds available to databases. When not set, the kinds
+	// are taken from storage_pool_types of the domain in the configuration.
+	// +optional
+	StoragePoolKinds []StoragePoolKind `json:"storagePoolKinds,omitempty"`
+}
+
+type StoragePoolKind struct {
+	// Unit kind as referenced by Database storage units
+	// +required
+	Name string `json:"name"`
+
+	// (Optional) Kind of the storage pool in the domain configuration
+	// Default: same as Name
+	// +optional
+	PoolKind string `json:"poolKind,omitempty"`
 }
 
 // StorageStatus defines the observed state of Storage
 type StorageStatus struct {
 	State      string             `json:"state"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Time of the last State change
+	StateTransitionTime *metav1.Time `json:"stateTransitionTime,omitempty"`
+
+	ResourcesSync *ResourcesSyncStatus `json:"resourcesSync,omitempty"`
+
+	// ConfigMaps and Secrets the pods of the workload use, with the hashes
+	// of their data
+	MountedObjects []MountedObject `json:"mountedObjects,omitempty"`
+
+	// Pods CPU and memory usage, recorded when the metrics API is available
+	ResourceUsage *ResourceUsageStatus `json:"resourceUsage,omitempty"`
+
+	// Most recent actions taken by the operator, oldest first
+	History []HistoryEntry `json:"history,omitempty"`
+
+	// Disk access mode the storage nodes run with, the one detected for
+	// spec.diskAccess.mode Auto. It is kept once the volumes are created.
+	DiskAccessMode DiskAccessMode `json:"diskAccessMode,omitempty"`
+
+	// Formatting progress of the PDisks on the first boot
+	PDisks *PDisksStatus `json:"pdisks,omitempty"`
+
+	// Total resources requested by the pods and volumes
+	Capacity *CapacityEstimate `json:"capacity,omitempty"`
+
+	// Number of the storage nodes the erasure utilizes, spec.nodes rounded
+	// down to a multiple of the nodes it places its groups on
+	UtilizedNodes int32 `json:"utilizedNodes,omitempty"`
+
+	// Links to the embedded UI and the dashboards of the storage
+	Links *ResourceLinks `json:"links,omitempty"`
+
+	// Resource version of the <name>-monitoring Secret whose credentials
+	// are registered as a YDB user, see spec.monitoring.isolatedCredentials
+	MonitoringCredentialsVersion string `json:"monitoringCredentialsVersion,omitempty"`
+
+	// Progress of the disaster recovery requested with the
+	// ydb.tech/disaster-recovery annotation
+	DisasterRecovery *DisasterRecoveryStatus `json:"disasterRecovery,omitempty"`
+
+	// Progress of the node decommission requested with the
+	// ydb.tech/decommission-nodes annotation
+	Decommission *DecommissionStatus `json:"decommission,omitempty"`
+
+	// Drive states of the spare pods, as set by the operator
+	// +optional
+	SpareNodes *SpareNodesStatus `json:"spareNodes,omitempty"`
+
+	// What the operator does or waits for before the reconcile completes,
+	// empty when there is nothing left to do
+	NextAction string `json:"nextAction,omitempty"`
+}
+
+type SpareNodesStatus struct {
+	// Spare pods with their drives decommitted, out of group placement
+	// +optional
+	Standby []string `json:"standby,omitempty"`
+
+	// Spare pods with their drives enabled for the self heal of failed nodes
+	// +optional
+	Active []string `json:"active,omitempty"`
+}
+
+type DecommissionStatus struct {
+	// Current phase, one of Validating, Decommitting, WaitingForVDisks,
+	// Shrinking, Completed or Rejected
+	Phase string `json:"phase"`
+
+	// Pods being removed
+	Pods []string `json:"pods,omitempty"`
+
+	// Time the decommission was requested
+	StartTime metav1.Time `json:"startTime"`
+
+	// VDisks left on the nodes of the pods
+	VDisks int32 `json:"vdisks,omitempty"`
+
+	// Outcome of the last step
+	Message string `json:"message,omitempty"`
+}
+
+type DisasterRecoveryStatus struct {
+	// Current phase, one of Validating, RelocatingStaticGroup,
+	// RestartingNodes, WaitingForNodes, ReinitializingStorage, Verifying,
+	// Completed or Rejected
+	Phase string `json:"phase"`
+
+	// Time the recovery was requested
+	StartTime metav1.Time `json:"startTime"`
+
+	// VDisks of the static group moved off the failed nodes, set once the
+	// loss of the group quorum is confirmed
+	// +optional
+	Relocations []StaticGroupRelocation `json:"relocations,omitempty"`
+
+	// Steps taken so far, oldest first
+	Steps []DisasterRecoveryStep `json:"steps,omitempty"`
+}
+
+type StaticGroupRelocation struct {
+	// Id of the failed node the VDisk is moved off
+	NodeID int32 `json:"nodeId"`
+
+	// Id of the node the VDisk is moved to
+	ReplacementNodeID int32 `json:"replacementNodeId"`
+}
+
+type DisasterRecoveryStep struct {
+	// Phase the step was taken in
+	Phase string `json:"phase"`
+
+	// Time the step was taken
+	Time metav1.Time `json:"time"`
+
+	// Outcome of the step, Succeeded or Failed
+	Outcome string `json:"outcome"`
+
+	// (Optional) Details of the step
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type PDisksStatus struct {
+	// Number of PDisks backed by block devices
+	Total int32 `json:"total"`
+
+	// Number of PDisks formatted so far
+	Formatted int32 `json:"formatted"`
+
+	// Pods with PDisks not formatted yet
+	PendingPods []string `json:"pendingPods,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=ydbs,categories=ydb-all
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this DB"
+//+kubebuilder:printcolumn:name="Next Action",type="string",JSONPath=".status.nextAction",description="What the operator does or waits for"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Storage is the Schema for the Storages API
@@ -132,6 +391,65 @@ type Storage struct {
 	Status StorageStatus `json:"status,omitempty"`
 }
 
+// Domains returns the names of the root domain and the additional ones
+func (r *Storage) Domains() []string {
+	domain := r.Spec.Domain
+	if domain == "" {
+		domain = DefaultDatabaseDomain
+	}
+	return append([]string{domain}, r.Spec.AdditionalDomains...)
+}
+
+// DiskAccessMode returns the disk access mode of the storage nodes. Auto
+// resolves to the mode recorded in status, Unprivileged until it is.
+func (r *Storage) DiskAccessMode() DiskAccessMode {
+	mode := DiskAccessUnprivileged
+	if r.Spec.DiskAccess != nil && r.Spec.DiskAccess.Mode != "" {
+		mode = r.Spec.DiskAccess.Mode
+	}
+	if mode == DiskAccessAuto {
+		if r.Status.DiskAccessMode == "" {
+			return DiskAccessUnprivileged
+		}
+		return r.Status.DiskAccessMode
+	}
+	return mode
+}
+
+// DiskFile returns the file the PDisk of the data store is kept in with
+// the FileBacked disk access mode, on the volume mounted at its directory
+func DiskFile(index int) string {
+	return fmt.Sprintf("%s/%0*d/%s", DiskFilesDir, DiskNumberMaxDigits, index, DiskFileName)
+}
+
+// DiskDevice returns the path the Block data store is attached at
+func DiskDevice(index int) string {
+	return fmt.Sprintf("%s_%0*d", DiskPathPrefix, DiskNumberMaxDigits, index)
+}
+
+// DiskIndex returns the index the volume of the data store is numbered with
+// for the static node with the given index within the pod. The first node
+// keeps the indexes of the data stores, every other node gets its own set
+// after them.
+func (r *Storage) DiskIndex(node, dataStore int) int {
+	return node*len(r.Spec.DataStore) + dataStore
+}
+
+// TotalNodes returns the number of storage pods, spare ones included
+func (r *Storage) TotalNodes() int32 {
+	return r.Spec.Nodes + r.Spec.SpareNodes
+}
+
+// UtilizedNodes returns the number of spec.nodes the erasure utilizes. The
+// remainder adds no capacity the groups could be placed on.
+func (r *Storage) UtilizedNodes() int32 {
+	if r.Spec.Nodes < MinNodesForErasure(r.Spec.Erasure) {
+		return 0
+	}
+	multiple := NodesMultipleForErasure(r.Spec.Erasure)
+	return r.Spec.Nodes - r.Spec.Nodes%multiple
+}
+
 //+kubebuilder:object:root=true
 
 // StorageList contains a list of Storage
@@ -143,7 +461,9 @@ type StorageList struct {
 
 // StorageServices defines parameter overrides for Storage Services
 type StorageServices struct {
-	GRPC         GRPCService         `json:"grpc,omitempty"`
+	// +kubebuilder:default:={}
+	GRPC GRPCService `json:"grpc,omitempty"`
+	// +kubebuilder:default:={}
 	Interconnect InterconnectService `json:"interconnect,omitempty"`
 	Status       StatusService       `json:"status,omitempty"`
 }
@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -13,6 +15,10 @@ import (
 // log is for logging in this package.
 var storagelog = logf.Log.WithName("storage-resource")
 
+// Same as the sysctl name validation of Kubernetes, which also keeps the
+// names usable as /proc/sys paths
+var sysctlNameRegexp = regexp.MustCompile(`^([a-z0-9]([-_a-z0-9]*[a-z0-9])?[./])*[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`)
+
 func (r *Storage) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -28,11 +34,7 @@ func (r *Storage) Default() {
 	storagelog.Info("default", "name", r.Name)
 
 	if r.Spec.Image.Name == "" {
-		if r.Spec.YDBVersion == "" {
-			r.Spec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, DefaultTag)
-		} else {
-			r.Spec.Image.Name = fmt.Sprintf(ImagePathFormat, RegistryPath, r.Spec.YDBVersion)
-		}
+		r.Spec.Image.Name = DefaultImageName(r.Spec.YDBVersion)
 	}
 
 	if r.Spec.Image.PullPolicyName == nil {
@@ -53,11 +55,49 @@ func (r *Storage) Default() {
 		}
 	}
 
+	if r.Spec.UIAuth != nil {
+		r.Spec.UIAuth.SetDefaults()
+	}
+
+	if r.Spec.UnutilizedNodesPolicy == "" {
+		r.Spec.UnutilizedNodesPolicy = UnutilizedNodesWarn
+	}
+
+	if r.Spec.NodesPerPod == 0 {
+		r.Spec.NodesPerPod = 1
+	}
+
 	if r.Spec.Domain == "" {
 		r.Spec.Domain = "root" // FIXME
 	}
 }
 
+// MinNodesForErasure returns the number of storage nodes the erasure needs
+// to place its groups
+func MinNodesForErasure(erasure ErasureType) int32 {
+	minNodesPerErasure := map[ErasureType]int32{
+		ErasureMirror3DC: 9,
+		ErasureBlock42:   8,
+		None:             1,
+	}
+	return minNodesPerErasure[erasure]
+}
+
+// NodesMultipleForErasure returns the number of storage nodes the erasure
+// utilizes at once: the 8 fail domains of a block-4-2 group, and a node in
+// each of the 3 zones for mirror-3-dc, as the nodes are spread over the
+// zones in turn
+func NodesMultipleForErasure(erasure ErasureType) int32 {
+	switch erasure {
+	case ErasureBlock42:
+		return 8
+	case ErasureMirror3DC:
+		return 3
+	default:
+		return 1
+	}
+}
+
 //+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-storage,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=storages,verbs=create;update,versions=v1alpha1,name=validate-storage.ydb.tech,admissionReviewVersions=v1
 
 var _ webhook.Validator = &Storage{}
@@ -66,28 +106,145 @@ var _ webhook.Validator = &Storage{}
 func (r *Storage) ValidateCreate() error {
 	storagelog.Info("validate create", "name", r.Name)
 
-	minNodesPerErasure := map[ErasureType]int32{
-		ErasureMirror3DC: 9,
-		ErasureBlock42:   8,
-		None:             1,
+	if r.Spec.Nodes < MinNodesForErasure(r.Spec.Erasure) {
+		return fmt.Errorf("erasure type %v requires at least %v storage nodes", r.Spec.Erasure, MinNodesForErasure(r.Spec.Erasure))
 	}
 
-	if r.Spec.Nodes < minNodesPerErasure[r.Spec.Erasure] {
-		return fmt.Errorf("erasure type %v requires at least %v storage nodes", r.Spec.Erasure, minNodesPerErasure[r.Spec.Erasure])
+	if err := r.validateUtilizedNodes(); err != nil {
+		return err
 	}
-
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	if err := r.validateDomains(); err != nil {
+		return err
+	}
+	if err := r.validateTuning(); err != nil {
+		return err
+	}
+	if err := r.Spec.Service.GRPC.Server.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.UIAuth.Validate(); err != nil {
+		return err
+	}
+	if err := validateAdditionalArgs(r.Spec.AdditionalArgs); err != nil {
+		return err
+	}
+	return r.validateStoragePoolKinds()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Storage) ValidateUpdate(old runtime.Object) error {
 	storagelog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
+	// Node ids and ports are derived from the number of nodes per pod, so
+	// changing it would renumber the nodes of a running cluster
+	if oldStorage, ok := old.(*Storage); ok && oldStorage.Spec.NodesPerPod != 0 &&
+		oldStorage.Spec.NodesPerPod != r.Spec.NodesPerPod {
+		return errors.New("nodesPerPod cannot be changed")
+	}
+
+	// The data stores of FileBacked storages are claimed as Filesystem
+	// volumes, which existing StatefulSets cannot change
+	if oldStorage, ok := old.(*Storage); ok &&
+		(oldStorage.DiskAccessMode() == DiskAccessFileBacked) != (r.DiskAccessMode() == DiskAccessFileBacked) {
+		return errors.New("diskAccess.mode cannot be changed from or to FileBacked")
+	}
+
+	if err := r.validateUtilizedNodes(); err != nil {
+		return err
+	}
+	if err := r.validateDomains(); err != nil {
+		return err
+	}
+	if err := r.validateTuning(); err != nil {
+		return err
+	}
+	if err := r.Spec.Service.GRPC.Server.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.UIAuth.Validate(); err != nil {
+		return err
+	}
+	if err := validateAdditionalArgs(r.Spec.AdditionalArgs); err != nil {
+		return err
+	}
+	return r.validateStoragePoolKinds()
+}
+
+// validateUtilizedNodes rejects nodes the erasure would leave unutilized
+// when spec.unutilizedNodesPolicy is Reject
+func (r *Storage) validateUtilizedNodes() error {
+	if r.Spec.UnutilizedNodesPolicy != UnutilizedNodesReject {
+		return nil
+	}
+	utilized := r.UtilizedNodes()
+	if utilized == 0 || utilized == r.Spec.Nodes {
+		return nil
+	}
+	multiple := NodesMultipleForErasure(r.Spec.Erasure)
+	return fmt.Errorf(
+		"erasure %s utilizes the storage nodes in multiples of %d, %d of %d nodes would be unutilized: "+
+			"set nodes to %d or %d, or unutilizedNodesPolicy to Warn",
+		r.Spec.Erasure, multiple, r.Spec.Nodes-utilized, r.Spec.Nodes, utilized, utilized+multiple,
+	)
+}
+
+func (r *Storage) validateDomains() error {
+	names := map[string]bool{}
+	for _, domain := range r.Domains() {
+		if names[domain] {
+			return fmt.Errorf("domain %q is defined more than once", domain)
+		}
+		names[domain] = true
+	}
+	return nil
+}
+
+func (r *Storage) validateStoragePoolKinds() error {
+	names := make(map[string]bool, len(r.Spec.StoragePoolKinds))
+	for _, kind := range r.Spec.StoragePoolKinds {
+		if kind.Name == "" {
+			return errors.New("storage pool kind name must not be empty")
+		}
+		if names[kind.Name] {
+			return fmt.Errorf("storage pool kind %q is defined more than once", kind.Name)
+		}
+		names[kind.Name] = true
+	}
+	return nil
+}
+
+// validateTuning rejects what Kubernetes would only reject when creating
+// the pods, leaving the StatefulSet without them
+func (r *Storage) validateTuning() error {
+	if hugePages := r.Spec.HugePages; hugePages != nil {
+		if hugePages.Size.Sign() <= 0 {
+			return errors.New("hugePages.size must be positive")
+		}
+		if !hasComputeResources(r.Spec.Resources) {
+			return errors.New("hugePages require cpu or memory resources to be specified")
+		}
+	}
+	if r.Spec.Sysctls != nil {
+		for _, sysctl := range r.Spec.Sysctls.Values {
+			if !sysctlNameRegexp.MatchString(sysctl.Name) {
+				return fmt.Errorf("invalid sysctl name %q", sysctl.Name)
+			}
+		}
+	}
 	return nil
 }
 
+func hasComputeResources(resources v1.ResourceRequirements) bool {
+	for _, list := range []v1.ResourceList{resources.Requests, resources.Limits} {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			if _, ok := list[name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *Storage) ValidateDelete() error {
 	return nil
 }
@@ -0,0 +1,37 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// ResourcesSyncStatus records the last full sync of the child resources
+type ResourcesSyncStatus struct {
+	// Generation of the spec the child resources were rendered from
+	Generation int64 `json:"generation"`
+
+	// Version of the operator that rendered the child resources
+	OperatorVersion string `json:"operatorVersion"`
+
+	// Hash of the rendered child resources
+	Hash string `json:"hash"`
+
+	// Time of the last full sync
+	LastSyncTime metav1.Time `json:"lastSyncTime"`
+}
+
+const (
+	MountedObjectConfigMap = "ConfigMap"
+	MountedObjectSecret    = "Secret"
+)
+
+// MountedObject is a ConfigMap or Secret the pods of the workload use,
+// mounted as a volume or read into the environment
+type MountedObject struct {
+	// ConfigMap or Secret
+	Kind string `json:"kind"`
+
+	Name string `json:"name"`
+
+	// Hex SHA-256 of the data: the keys in sorted order, each followed by a
+	// NUL byte, its value and another NUL byte. Empty when the object is
+	// missing.
+	Hash string `json:"hash,omitempty"`
+}
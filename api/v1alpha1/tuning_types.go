@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const DefaultHugePageSize = "2Mi"
+
+// HugePages reserves hugepages memory for the storage nodes. The nodes have
+// to offer hugepages of the size, `ydb-kubernetes-operator preflight` lists
+// the ones that do.
+type HugePages struct {
+	// (Optional) Size of a single page
+	// Default: 2Mi
+	// +kubebuilder:validation:Enum=2Mi;1Gi
+	// +kubebuilder:default:="2Mi"
+	// +optional
+	PageSize string `json:"pageSize,omitempty"`
+
+	// Amount of hugepages memory requested by every storage node, a
+	// multiple of the page size. Kubernetes only admits it along with cpu
+	// or memory resources of the container.
+	// +required
+	Size resource.Quantity `json:"size"`
+}
+
+// ResourceName returns the extended resource the pages are requested as
+func (h *HugePages) ResourceName() corev1.ResourceName {
+	pageSize := h.PageSize
+	if pageSize == "" {
+		pageSize = DefaultHugePageSize
+	}
+	return corev1.ResourceName(corev1.ResourceHugePagesPrefix + pageSize)
+}
+
+type SysctlMode string
+
+const (
+	SysctlModePod           SysctlMode = "Pod"
+	SysctlModeInitContainer SysctlMode = "InitContainer"
+)
+
+// Sysctls sets kernel parameters for the storage nodes
+type Sysctls struct {
+	// Kernel parameters to set, e.g. net.core.somaxconn
+	// +required
+	Values []corev1.Sysctl `json:"values"`
+
+	// (Optional) How the parameters are set. Pod puts them into the security
+	// context of the pod, where the kubelet accepts only namespaced
+	// parameters, and unsafe ones only when allowed by its
+	// --allowed-unsafe-sysctls flag. InitContainer writes them to /proc/sys
+	// from a privileged init container, which works for any parameter but
+	// changes it for the whole node.
+	// Default: Pod
+	// +kubebuilder:validation:Enum=Pod;InitContainer
+	// +kubebuilder:default:=Pod
+	// +optional
+	Mode SysctlMode `json:"mode,omitempty"`
+}
+
+type DiskAccessMode string
+
+const (
+	// DiskAccessAuto picks Privileged when the namespace admits privileged
+	// pods and the fallback mode otherwise
+	DiskAccessAuto DiskAccessMode = "Auto"
+	// DiskAccessPrivileged runs the storage containers privileged, which
+	// raw devices of some setups need to be opened
+	DiskAccessPrivileged DiskAccessMode = "Privileged"
+	// DiskAccessUnprivileged attaches the Block data stores to unprivileged
+	// containers
+	DiskAccessUnprivileged DiskAccessMode = "Unprivileged"
+	// DiskAccessFileBacked keeps the PDisks in files on Filesystem volumes,
+	// which needs no privileges and no raw devices
+	DiskAccessFileBacked DiskAccessMode = "FileBacked"
+)
+
+// DiskAccess selects how the storage nodes access the disks of spec.dataStore
+type DiskAccess struct {
+	// (Optional) Access mode of the disks
+	// Default: Unprivileged
+	// +kubebuilder:validation:Enum=Auto;Privileged;Unprivileged;FileBacked
+	// +kubebuilder:default:=Unprivileged
+	// +optional
+	Mode DiskAccessMode `json:"mode,omitempty"`
+
+	// (Optional) Mode Auto falls back to when privileged pods are not
+	// admitted
+	// Default: FileBacked
+	// +kubebuilder:validation:Enum=Unprivileged;FileBacked
+	// +kubebuilder:default:=FileBacked
+	// +optional
+	Fallback DiskAccessMode `json:"fallback,omitempty"`
+}
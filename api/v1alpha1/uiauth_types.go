@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultUIAuthImage = "quay.io/oauth2-proxy/oauth2-proxy:v7.5.1"
+
+	UIAuthPort            = 4180
+	UIAuthServicePortName = "http"
+)
+
+// UIAuth places oauth2-proxy in front of the embedded UI of the nodes, so
+// the UI is only reachable after a login with the OIDC identity provider of
+// the company. The proxy is served by the <name>-ui Service, expose it
+// instead of <name>-status.
+type UIAuth struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// (Optional) oauth2-proxy image
+	// Default: quay.io/oauth2-proxy/oauth2-proxy:v7.5.1
+	// +optional
+	Image PodImage `json:"image,omitempty"`
+
+	// Issuer URL of the OIDC identity provider
+	// +required
+	IssuerURL string `json:"issuerURL"`
+
+	// Client ID of the operator in the identity provider
+	// +required
+	ClientID string `json:"clientID"`
+
+	// Secret key with the client secret
+	// +required
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+
+	// Secret key with the seed of the session cookies, 16, 24 or 32 bytes
+	// +required
+	CookieSecret corev1.SecretKeySelector `json:"cookieSecret"`
+
+	// (Optional) URL the identity provider redirects to after the login,
+	// https://<ingress host>/oauth2/callback
+	// Default: (derived from the request)
+	// +optional
+	RedirectURL string `json:"redirectURL,omitempty"`
+
+	// (Optional) Email domains of the users let in
+	// Default: (any)
+	// +optional
+	EmailDomains []string `json:"emailDomains,omitempty"`
+
+	// (Optional) Groups of the users let in, from the groups claim
+	// Default: (any)
+	// +optional
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+
+	// (Optional) Additional arguments passed to oauth2-proxy
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// (Optional) Number of proxy replicas
+	// Default: 1
+	// +kubebuilder:default:=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// (Optional) Proxy container resource limits
+	// Default: (not specified)
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// (Optional) Proxy service parameter overrides
+	// +optional
+	Service Service `json:"service,omitempty"`
+}
+
+// SetDefaults fills the image and the number of replicas
+func (a *UIAuth) SetDefaults() {
+	if a.Image.Name == "" {
+		a.Image.Name = DefaultUIAuthImage
+	}
+	if a.Image.PullPolicyName == nil {
+		policy := corev1.PullIfNotPresent
+		a.Image.PullPolicyName = &policy
+	}
+	if a.Replicas == nil {
+		replicas := int32(1)
+		a.Replicas = &replicas
+	}
+}
+
+// Validate checks the URLs and the secret references, oauth2-proxy exits
+// on start without them
+func (a *UIAuth) Validate() error {
+	if a == nil || !a.Enabled {
+		return nil
+	}
+	if u, err := url.Parse(a.IssuerURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("uiAuth.issuerURL %q is not an absolute URL", a.IssuerURL)
+	}
+	if a.RedirectURL != "" {
+		if u, err := url.Parse(a.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("uiAuth.redirectURL %q is not an absolute URL", a.RedirectURL)
+		}
+	}
+	if a.ClientSecret.Name == "" || a.ClientSecret.Key == "" {
+		return errors.New("uiAuth.clientSecret must name a secret and a key")
+	}
+	if a.CookieSecret.Name == "" || a.CookieSecret.Key == "" {
+		return errors.New("uiAuth.cookieSecret must name a secret and a key")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceUsageStatus is the CPU and memory usage of the resource pods
+// aggregated from the metrics API
+type ResourceUsageStatus struct {
+	// Number of pods the usage was reported for
+	Pods int32 `json:"pods"`
+
+	// Total usage over all pods
+	CPU    resource.Quantity `json:"cpu"`
+	Memory resource.Quantity `json:"memory"`
+
+	// Usage of the busiest pod
+	MaxPodCPU    resource.Quantity `json:"maxPodCpu"`
+	MaxPodMemory resource.Quantity `json:"maxPodMemory"`
+
+	// Time the usage was collected
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
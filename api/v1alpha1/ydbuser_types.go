@@ -0,0 +1,151 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// YdbUserSpec defines the desired state of YdbUser
+type YdbUserSpec struct {
+	// YDB Database the users, groups and grants are declared in
+	// +required
+	DatabaseRef DatabaseRef `json:"databaseRef"`
+
+	// (Optional) Local users of the database
+	// +optional
+	Users []DatabaseUser `json:"users,omitempty"`
+
+	// (Optional) Groups of the database with their members
+	// +optional
+	Groups []DatabaseGroup `json:"groups,omitempty"`
+
+	// (Optional) Permissions granted to the users and groups
+	// +optional
+	Grants []DatabaseGrant `json:"grants,omitempty"`
+}
+
+type DatabaseRef struct {
+	// +kubebuilder:validation:Pattern:=[a-z0-9]([-a-z0-9]*[a-z0-9])?
+	// +kubebuilder:validation:MaxLength:=63
+	// +required
+	Name string `json:"name"`
+
+	// (Optional) Namespace of the Database, it has to be the namespace of
+	// the YdbUser
+	// +kubebuilder:validation:Pattern:=[a-z0-9]([-a-z0-9]*[a-z0-9])?
+	// +kubebuilder:validation:MaxLength:=63
+	// +optional
+	Namespace string `json:"namespace"`
+}
+
+type DatabaseUser struct {
+	// +kubebuilder:validation:Pattern:=^[a-zA-Z_][a-zA-Z0-9_]*$
+	// +required
+	Name string `json:"name"`
+
+	// Key of a Secret in the namespace of the YdbUser holding the password,
+	// the password is changed when the Secret changes
+	// +required
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+
+	// (Optional) Take over the user when it already exists in the database:
+	// its password is changed and it is dropped along with the YdbUser
+	// Default: false, an existing user is not applied
+	// +optional
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+type DatabaseGroup struct {
+	// +kubebuilder:validation:Pattern:=^[a-zA-Z_][a-zA-Z0-9_]*$
+	// +required
+	Name string `json:"name"`
+
+	// (Optional) Names of the users in the group, they may be declared by
+	// other YdbUser resources
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// (Optional) Take over the group when it already exists in the
+	// database: its members are changed and it is dropped along with the
+	// YdbUser
+	// Default: false, an existing group is not applied
+	// +optional
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+type DatabaseGrant struct {
+	// (Optional) Path of the scheme object relative to the database, the
+	// database itself when empty. It cannot leave the database.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// User or group the permissions are granted to
+	// +required
+	Subject string `json:"subject"`
+
+	// Names of the permissions, e.g. ydb.generic.read
+	// +kubebuilder:validation:MinItems:=1
+	// +required
+	Permissions []string `json:"permissions"`
+}
+
+// YdbUserStatus defines the observed state of YdbUser
+type YdbUserStatus struct {
+	State      string             `json:"state"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Generation of the resource applied last
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Users created in the database, removed when they leave the spec
+	Users []AppliedDatabaseUser `json:"users,omitempty"`
+
+	// Groups created in the database with the members added to them
+	Groups []DatabaseGroup `json:"groups,omitempty"`
+
+	// Permissions granted, revoked when they leave the spec
+	Grants []DatabaseGrant `json:"grants,omitempty"`
+
+	// Error of the last attempt
+	Message string `json:"message,omitempty"`
+}
+
+type AppliedDatabaseUser struct {
+	Name string `json:"name"`
+
+	// Resource version of the password Secret applied last
+	PasswordVersion string `json:"passwordVersion"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:categories=ydb-all
+//+kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.databaseRef.name"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of the users"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// YdbUser is the Schema for the ydbusers API. The users, groups and grants
+// are applied to the database as declared and removed from it when they
+// leave the spec or the resource is deleted.
+type YdbUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec YdbUserSpec `json:"spec,omitempty"`
+
+	// +kubebuilder:default:={state: "Pending"}
+	Status YdbUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// YdbUserList contains a list of YdbUser
+type YdbUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []YdbUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&YdbUser{}, &YdbUserList{})
+}
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var ydbuserlog = logf.Log.WithName("ydbuser-resource")
+
+// subjectNameRegexp matches the names of users and groups, they are put in
+// YQL statements as is
+var subjectNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func (r *YdbUser) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-ydbuser,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=ydbusers,verbs=create;update,versions=v1alpha1,name=validate-ydbuser.ydb.tech,admissionReviewVersions=v1
+
+var _ webhook.Validator = &YdbUser{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *YdbUser) ValidateCreate() error {
+	ydbuserlog.Info("validate create", "name", r.Name)
+
+	return r.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *YdbUser) ValidateUpdate(old runtime.Object) error {
+	ydbuserlog.Info("validate update", "name", r.Name)
+
+	return r.Validate()
+}
+
+func (r *YdbUser) ValidateDelete() error {
+	return nil
+}
+
+// Validate checks the names of the users, groups and members and rejects
+// duplicates, users and groups share the namespace of subjects in YDB. The
+// Database has to be in the namespace of the YdbUser and the grants cannot
+// leave it. The controller repeats the check, the webhook may be disabled.
+func (r *YdbUser) Validate() error {
+	if ns := r.Spec.DatabaseRef.Namespace; ns != "" && ns != r.Namespace {
+		return fmt.Errorf("spec.databaseRef.namespace %s is not the namespace %s of the YdbUser", ns, r.Namespace)
+	}
+	names := map[string]bool{}
+	for _, user := range r.Spec.Users {
+		if !subjectNameRegexp.MatchString(user.Name) {
+			return fmt.Errorf("invalid user name %q", user.Name)
+		}
+		if names[user.Name] {
+			return fmt.Errorf("duplicate user name %q in spec.users", user.Name)
+		}
+		names[user.Name] = true
+	}
+	for _, group := range r.Spec.Groups {
+		if !subjectNameRegexp.MatchString(group.Name) {
+			return fmt.Errorf("invalid group name %q", group.Name)
+		}
+		if names[group.Name] {
+			return fmt.Errorf("duplicate group name %q in spec.groups", group.Name)
+		}
+		names[group.Name] = true
+		for _, member := range group.Members {
+			if !subjectNameRegexp.MatchString(member) {
+				return fmt.Errorf("invalid member %q of group %q", member, group.Name)
+			}
+		}
+	}
+	for _, grant := range r.Spec.Grants {
+		if grant.Subject == "" {
+			return errors.New("spec.grants.subject must be set")
+		}
+		if len(grant.Permissions) == 0 {
+			return fmt.Errorf("no permissions granted to %q", grant.Subject)
+		}
+		for _, segment := range strings.Split(grant.Path, "/") {
+			if segment == "." || segment == ".." {
+				return fmt.Errorf("grant path %q cannot contain . or .. segments", grant.Path)
+			}
+		}
+	}
+	return nil
+}
@@ -12,6 +12,111 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedDatabaseUser) DeepCopyInto(out *AppliedDatabaseUser) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedDatabaseUser.
+func (in *AppliedDatabaseUser) DeepCopy() *AppliedDatabaseUser {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedDatabaseUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoUpdatePolicy) DeepCopyInto(out *AutoUpdatePolicy) {
+	*out = *in
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoUpdatePolicy.
+func (in *AutoUpdatePolicy) DeepCopy() *AutoUpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoUpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityEstimate) DeepCopyInto(out *CapacityEstimate) {
+	*out = *in
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
+	out.Storage = in.Storage.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityEstimate.
+func (in *CapacityEstimate) DeepCopy() *CapacityEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatedRolloutStatus) DeepCopyInto(out *CoordinatedRolloutStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatedRolloutStatus.
+func (in *CoordinatedRolloutStatus) DeepCopy() *CoordinatedRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatedRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dashboards) DeepCopyInto(out *Dashboards) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dashboards.
+func (in *Dashboards) DeepCopy() *Dashboards {
+	if in == nil {
+		return nil
+	}
+	out := new(Dashboards)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSizeQuota) DeepCopyInto(out *DataSizeQuota) {
+	*out = *in
+	out.Hard = in.Hard.DeepCopy()
+	if in.Soft != nil {
+		in, out := &in.Soft, &out.Soft
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSizeQuota.
+func (in *DataSizeQuota) DeepCopy() *DataSizeQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSizeQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Database) DeepCopyInto(out *Database) {
 	*out = *in
@@ -39,6 +144,46 @@ func (in *Database) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseGrant) DeepCopyInto(out *DatabaseGrant) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseGrant.
+func (in *DatabaseGrant) DeepCopy() *DatabaseGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseGroup) DeepCopyInto(out *DatabaseGroup) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseGroup.
+func (in *DatabaseGroup) DeepCopy() *DatabaseGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
 	*out = *in
@@ -71,6 +216,54 @@ func (in *DatabaseList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseProxy) DeepCopyInto(out *DatabaseProxy) {
+	*out = *in
+	in.Image.DeepCopyInto(&out.Image)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Service.DeepCopyInto(&out.Service)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ProxyAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseProxy.
+func (in *DatabaseProxy) DeepCopy() *DatabaseProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRef) DeepCopyInto(out *DatabaseRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRef.
+func (in *DatabaseRef) DeepCopy() *DatabaseRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseResources) DeepCopyInto(out *DatabaseResources) {
 	*out = *in
@@ -115,7 +308,16 @@ func (in *DatabaseServices) DeepCopy() *DatabaseServices {
 func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 	*out = *in
 	in.Service.DeepCopyInto(&out.Service)
-	out.StorageClusterRef = in.StorageClusterRef
+	if in.StorageClusterRef != nil {
+		in, out := &in.StorageClusterRef, &out.StorageClusterRef
+		*out = new(StorageRef)
+		**out = **in
+	}
+	if in.ExternalStorageEndpoint != nil {
+		in, out := &in.ExternalStorageEndpoint, &out.ExternalStorageEndpoint
+		*out = new(ExternalStorageEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Encryption != nil {
 		in, out := &in.Encryption, &out.Encryption
 		*out = new(EncryptionConfig)
@@ -126,6 +328,26 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 		*out = new(DatastreamsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tiering != nil {
+		in, out := &in.Tiering, &out.Tiering
+		*out = new(TieringConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryService != nil {
+		in, out := &in.QueryService, &out.QueryService
+		*out = new(QueryServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSizeQuota != nil {
+		in, out := &in.DataSizeQuota, &out.DataSizeQuota
+		*out = new(DataSizeQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantOptions != nil {
+		in, out := &in.TenantOptions, &out.TenantOptions
+		*out = new(TenantOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(DatabaseResources)
@@ -139,7 +361,7 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 	if in.ServerlessResources != nil {
 		in, out := &in.ServerlessResources, &out.ServerlessResources
 		*out = new(ServerlessDatabaseResources)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
@@ -154,6 +376,11 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Image.DeepCopyInto(&out.Image)
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -173,6 +400,21 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EndpointDrainDelay != nil {
+		in, out := &in.EndpointDrainDelay, &out.EndpointDrainDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AutoUpdate != nil {
+		in, out := &in.AutoUpdate, &out.AutoUpdate
+		*out = new(AutoUpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalLabels != nil {
 		in, out := &in.AdditionalLabels, &out.AdditionalLabels
 		*out = make(map[string]string, len(*in))
@@ -187,6 +429,62 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.AdditionalPodLabels != nil {
+		in, out := &in.AdditionalPodLabels, &out.AdditionalPodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(DatabaseProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UIAuth != nil {
+		in, out := &in.UIAuth, &out.UIAuth
+		*out = new(UIAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = new(StorageAutoscaling)
+		**out = **in
+	}
+	if in.DedicatedNodes != nil {
+		in, out := &in.DedicatedNodes, &out.DedicatedNodes
+		*out = new(DedicatedNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageLocality != nil {
+		in, out := &in.StorageLocality, &out.StorageLocality
+		*out = new(StorageLocality)
+		**out = **in
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TenantAttributes != nil {
+		in, out := &in.TenantAttributes, &out.TenantAttributes
+		*out = new(TenantAttributes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make([]InitScript, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadReplicas != nil {
+		in, out := &in.ReadReplicas, &out.ReadReplicas
+		*out = make([]TableReadReplicas, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -209,6 +507,103 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StateTransitionTime != nil {
+		in, out := &in.StateTransitionTime, &out.StateTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResourcesSync != nil {
+		in, out := &in.ResourcesSync, &out.ResourcesSync
+		*out = new(ResourcesSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MountedObjects != nil {
+		in, out := &in.MountedObjects, &out.MountedObjects
+		*out = make([]MountedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = new(StorageAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(ResourceUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]HistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TenantAttributes != nil {
+		in, out := &in.TenantAttributes, &out.TenantAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make([]InitScriptStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadReplicas != nil {
+		in, out := &in.ReadReplicas, &out.ReadReplicas
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TenantQuotas != nil {
+		in, out := &in.TenantQuotas, &out.TenantQuotas
+		*out = new(TenantQuotas)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSizeQuota != nil {
+		in, out := &in.DataSizeQuota, &out.DataSizeQuota
+		*out = new(DataSizeQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(CapacityEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(ResourceLinks)
+		**out = **in
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(NodeDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(CoordinatedRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(DatabaseUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantOperation != nil {
+		in, out := &in.TenantOperation, &out.TenantOperation
+		*out = new(TenantOperationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -221,6 +616,42 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUpgradeStatus) DeepCopyInto(out *DatabaseUpgradeStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.BatchReadyTime != nil {
+		in, out := &in.BatchReadyTime, &out.BatchReadyTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUpgradeStatus.
+func (in *DatabaseUpgradeStatus) DeepCopy() *DatabaseUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
+func (in *DatabaseUser) DeepCopy() *DatabaseUser {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatastreamsConfig) DeepCopyInto(out *DatastreamsConfig) {
 	*out = *in
@@ -263,230 +694,173 @@ func (in *DatastreamsService) DeepCopy() *DatastreamsService {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EncryptionConfig) DeepCopyInto(out *EncryptionConfig) {
+func (in *DecommissionStatus) DeepCopyInto(out *DecommissionStatus) {
 	*out = *in
-	if in.Key != nil {
-		in, out := &in.Key, &out.Key
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Pin != nil {
-		in, out := &in.Pin, &out.Pin
-		*out = new(string)
-		**out = **in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionConfig.
-func (in *EncryptionConfig) DeepCopy() *EncryptionConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionStatus.
+func (in *DecommissionStatus) DeepCopy() *DecommissionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EncryptionConfig)
+	out := new(DecommissionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GRPCService) DeepCopyInto(out *GRPCService) {
+func (in *DedicatedNodes) DeepCopyInto(out *DedicatedNodes) {
 	*out = *in
-	in.Service.DeepCopyInto(&out.Service)
-	if in.TLSConfiguration != nil {
-		in, out := &in.TLSConfiguration, &out.TLSConfiguration
-		*out = new(TLSConfiguration)
-		(*in).DeepCopyInto(*out)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCService.
-func (in *GRPCService) DeepCopy() *GRPCService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedicatedNodes.
+func (in *DedicatedNodes) DeepCopy() *DedicatedNodes {
 	if in == nil {
 		return nil
 	}
-	out := new(GRPCService)
+	out := new(DedicatedNodes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterconnectService) DeepCopyInto(out *InterconnectService) {
+func (in *DefaultImage) DeepCopyInto(out *DefaultImage) {
 	*out = *in
-	in.Service.DeepCopyInto(&out.Service)
-	if in.TLSConfiguration != nil {
-		in, out := &in.TLSConfiguration, &out.TLSConfiguration
-		*out = new(TLSConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectService.
-func (in *InterconnectService) DeepCopy() *InterconnectService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultImage.
+func (in *DefaultImage) DeepCopy() *DefaultImage {
 	if in == nil {
 		return nil
 	}
-	out := new(InterconnectService)
+	out := new(DefaultImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringOptions) DeepCopyInto(out *MonitoringOptions) {
+func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
 	*out = *in
-	if in.MetricRelabelings != nil {
-		in, out := &in.MetricRelabelings, &out.MetricRelabelings
-		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Relocations != nil {
+		in, out := &in.Relocations, &out.Relocations
+		*out = make([]StaticGroupRelocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DisasterRecoveryStep, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(monitoringv1.RelabelConfig)
-				(*in).DeepCopyInto(*out)
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringOptions.
-func (in *MonitoringOptions) DeepCopy() *MonitoringOptions {
-	if in == nil {
-		return nil
-	}
-	out := new(MonitoringOptions)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodImage) DeepCopyInto(out *PodImage) {
-	*out = *in
-	if in.PullPolicyName != nil {
-		in, out := &in.PullPolicyName, &out.PullPolicyName
-		*out = new(v1.PullPolicy)
-		**out = **in
-	}
-	if in.PullSecret != nil {
-		in, out := &in.PullSecret, &out.PullSecret
-		*out = new(string)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodImage.
-func (in *PodImage) DeepCopy() *PodImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
+func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PodImage)
+	out := new(DisasterRecoveryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServerlessDatabaseResources) DeepCopyInto(out *ServerlessDatabaseResources) {
+func (in *DisasterRecoveryStep) DeepCopyInto(out *DisasterRecoveryStep) {
 	*out = *in
-	out.SharedDatabaseRef = in.SharedDatabaseRef
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessDatabaseResources.
-func (in *ServerlessDatabaseResources) DeepCopy() *ServerlessDatabaseResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStep.
+func (in *DisasterRecoveryStep) DeepCopy() *DisasterRecoveryStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ServerlessDatabaseResources)
+	out := new(DisasterRecoveryStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Service) DeepCopyInto(out *Service) {
+func (in *DiskAccess) DeepCopyInto(out *DiskAccess) {
 	*out = *in
-	if in.AdditionalLabels != nil {
-		in, out := &in.AdditionalLabels, &out.AdditionalLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.AdditionalAnnotations != nil {
-		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.IPFamilies != nil {
-		in, out := &in.IPFamilies, &out.IPFamilies
-		*out = make([]v1.IPFamily, len(*in))
-		copy(*out, *in)
-	}
-	if in.IPFamilyPolicy != nil {
-		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
-		*out = new(v1.IPFamilyPolicyType)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
-func (in *Service) DeepCopy() *Service {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskAccess.
+func (in *DiskAccess) DeepCopy() *DiskAccess {
 	if in == nil {
 		return nil
 	}
-	out := new(Service)
+	out := new(DiskAccess)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SharedDatabaseRef) DeepCopyInto(out *SharedDatabaseRef) {
+func (in *DrainNodeOperation) DeepCopyInto(out *DrainNodeOperation) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedDatabaseRef.
-func (in *SharedDatabaseRef) DeepCopy() *SharedDatabaseRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainNodeOperation.
+func (in *DrainNodeOperation) DeepCopy() *DrainNodeOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(SharedDatabaseRef)
+	out := new(DrainNodeOperation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StatusService) DeepCopyInto(out *StatusService) {
+func (in *DrainedNode) DeepCopyInto(out *DrainedNode) {
 	*out = *in
-	in.Service.DeepCopyInto(&out.Service)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusService.
-func (in *StatusService) DeepCopy() *StatusService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainedNode.
+func (in *DrainedNode) DeepCopy() *DrainedNode {
 	if in == nil {
 		return nil
 	}
-	out := new(StatusService)
+	out := new(DrainedNode)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Storage) DeepCopyInto(out *Storage) {
+func (in *DynamicConfig) DeepCopyInto(out *DynamicConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
-func (in *Storage) DeepCopy() *Storage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfig.
+func (in *DynamicConfig) DeepCopy() *DynamicConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Storage)
+	out := new(DynamicConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Storage) DeepCopyObject() runtime.Object {
+func (in *DynamicConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -494,31 +868,31 @@ func (in *Storage) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageList) DeepCopyInto(out *StorageList) {
+func (in *DynamicConfigList) DeepCopyInto(out *DynamicConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Storage, len(*in))
+		*out = make([]DynamicConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageList.
-func (in *StorageList) DeepCopy() *StorageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigList.
+func (in *DynamicConfigList) DeepCopy() *DynamicConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageList)
+	out := new(DynamicConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StorageList) DeepCopyObject() runtime.Object {
+func (in *DynamicConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -526,159 +900,1966 @@ func (in *StorageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageRef) DeepCopyInto(out *StorageRef) {
+func (in *DynamicConfigMetadata) DeepCopyInto(out *DynamicConfigMetadata) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageRef.
-func (in *StorageRef) DeepCopy() *StorageRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigMetadata.
+func (in *DynamicConfigMetadata) DeepCopy() *DynamicConfigMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageRef)
+	out := new(DynamicConfigMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageServices) DeepCopyInto(out *StorageServices) {
+func (in *DynamicConfigSpec) DeepCopyInto(out *DynamicConfigSpec) {
 	*out = *in
-	in.GRPC.DeepCopyInto(&out.GRPC)
-	in.Interconnect.DeepCopyInto(&out.Interconnect)
-	in.Status.DeepCopyInto(&out.Status)
+	out.StorageRef = in.StorageRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageServices.
-func (in *StorageServices) DeepCopy() *StorageServices {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigSpec.
+func (in *DynamicConfigSpec) DeepCopy() *DynamicConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageServices)
+	out := new(DynamicConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+func (in *DynamicConfigStatus) DeepCopyInto(out *DynamicConfigStatus) {
 	*out = *in
-	if in.DataStore != nil {
-		in, out := &in.DataStore, &out.DataStore
-		*out = make([]v1.PersistentVolumeClaimSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Service.DeepCopyInto(&out.Service)
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.Image.DeepCopyInto(&out.Image)
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Monitoring != nil {
-		in, out := &in.Monitoring, &out.Monitoring
-		*out = new(MonitoringOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigStatus.
+func (in *DynamicConfigStatus) DeepCopy() *DynamicConfigStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+	out := new(DynamicConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionConfig) DeepCopyInto(out *EncryptionConfig) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AdditionalLabels != nil {
-		in, out := &in.AdditionalLabels, &out.AdditionalLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.AdditionalAnnotations != nil {
-		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Pin != nil {
+		in, out := &in.Pin, &out.Pin
+		*out = new(string)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
-func (in *StorageSpec) DeepCopy() *StorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionConfig.
+func (in *EncryptionConfig) DeepCopy() *EncryptionConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSpec)
+	out := new(EncryptionConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+func (in *ExternalStorageEndpoint) DeepCopyInto(out *ExternalStorageEndpoint) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ExternalStorageTLS)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
-func (in *StorageStatus) DeepCopy() *StorageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStorageEndpoint.
+func (in *ExternalStorageEndpoint) DeepCopy() *ExternalStorageEndpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageStatus)
+	out := new(ExternalStorageEndpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageUnit) DeepCopyInto(out *StorageUnit) {
+func (in *ExternalStorageTLS) DeepCopyInto(out *ExternalStorageTLS) {
 	*out = *in
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageUnit.
-func (in *StorageUnit) DeepCopy() *StorageUnit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStorageTLS.
+func (in *ExternalStorageTLS) DeepCopy() *ExternalStorageTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageUnit)
+	out := new(ExternalStorageTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
+func (in *FleetReport) DeepCopyInto(out *FleetReport) {
 	*out = *in
-	in.CertificateAuthority.DeepCopyInto(&out.CertificateAuthority)
-	in.Certificate.DeepCopyInto(&out.Certificate)
-	in.Key.DeepCopyInto(&out.Key)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfiguration.
-func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetReport.
+func (in *FleetReport) DeepCopy() *FleetReport {
 	if in == nil {
 		return nil
 	}
-	out := new(TLSConfiguration)
+	out := new(FleetReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetReportList) DeepCopyInto(out *FleetReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FleetReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetReportList.
+func (in *FleetReportList) DeepCopy() *FleetReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetReportSpec) DeepCopyInto(out *FleetReportSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetReportSpec.
+func (in *FleetReportSpec) DeepCopy() *FleetReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetReportStatus) DeepCopyInto(out *FleetReportStatus) {
+	*out = *in
+	if in.GeneratedAt != nil {
+		in, out := &in.GeneratedAt, &out.GeneratedAt
+		*out = (*in).DeepCopy()
+	}
+	in.Storages.DeepCopyInto(&out.Storages)
+	in.Databases.DeepCopyInto(&out.Databases)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetReportStatus.
+func (in *FleetReportStatus) DeepCopy() *FleetReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetStalledResource) DeepCopyInto(out *FleetStalledResource) {
+	*out = *in
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetStalledResource.
+func (in *FleetStalledResource) DeepCopy() *FleetStalledResource {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetStalledResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetSummary) DeepCopyInto(out *FleetSummary) {
+	*out = *in
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Stalled != nil {
+		in, out := &in.Stalled, &out.Stalled
+		*out = make([]FleetStalledResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetSummary.
+func (in *FleetSummary) DeepCopy() *FleetSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCKeepAlive) DeepCopyInto(out *GRPCKeepAlive) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProbeInterval != nil {
+		in, out := &in.ProbeInterval, &out.ProbeInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxProbeCount != nil {
+		in, out := &in.MaxProbeCount, &out.MaxProbeCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCKeepAlive.
+func (in *GRPCKeepAlive) DeepCopy() *GRPCKeepAlive {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCKeepAlive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCServerConfig) DeepCopyInto(out *GRPCServerConfig) {
+	*out = *in
+	if in.MaxMessageSize != nil {
+		in, out := &in.MaxMessageSize, &out.MaxMessageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.KeepAlive != nil {
+		in, out := &in.KeepAlive, &out.KeepAlive
+		*out = new(GRPCKeepAlive)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCServerConfig.
+func (in *GRPCServerConfig) DeepCopy() *GRPCServerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCServerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCService) DeepCopyInto(out *GRPCService) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	if in.TLSConfiguration != nil {
+		in, out := &in.TLSConfiguration, &out.TLSConfiguration
+		*out = new(TLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(GRPCServerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCService.
+func (in *GRPCService) DeepCopy() *GRPCService {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryEntry) DeepCopyInto(out *HistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryEntry.
+func (in *HistoryEntry) DeepCopy() *HistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePages) DeepCopyInto(out *HugePages) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePages.
+func (in *HugePages) DeepCopy() *HugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitScript) DeepCopyInto(out *InitScript) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitScript.
+func (in *InitScript) DeepCopy() *InitScript {
+	if in == nil {
+		return nil
+	}
+	out := new(InitScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitScriptStatus) DeepCopyInto(out *InitScriptStatus) {
+	*out = *in
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitScriptStatus.
+func (in *InitScriptStatus) DeepCopy() *InitScriptStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InitScriptStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectService) DeepCopyInto(out *InterconnectService) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	if in.TLSConfiguration != nil {
+		in, out := &in.TLSConfiguration, &out.TLSConfiguration
+		*out = new(TLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectService.
+func (in *InterconnectService) DeepCopy() *InterconnectService {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringOptions) DeepCopyInto(out *MonitoringOptions) {
+	*out = *in
+	if in.MetricRelabelings != nil {
+		in, out := &in.MetricRelabelings, &out.MetricRelabelings
+		*out = make([]*monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(monitoringv1.RelabelConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CredentialsRotationInterval != nil {
+		in, out := &in.CredentialsRotationInterval, &out.CredentialsRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringOptions.
+func (in *MonitoringOptions) DeepCopy() *MonitoringOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountedObject) DeepCopyInto(out *MountedObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountedObject.
+func (in *MountedObject) DeepCopy() *MountedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(MountedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainStatus) DeepCopyInto(out *NodeDrainStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]DrainedNode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainStatus.
+func (in *NodeDrainStatus) DeepCopy() *NodeDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operation) DeepCopyInto(out *Operation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operation.
+func (in *Operation) DeepCopy() *Operation {
+	if in == nil {
+		return nil
+	}
+	out := new(Operation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Operation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationList) DeepCopyInto(out *OperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Operation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationList.
+func (in *OperationList) DeepCopy() *OperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationSpec) DeepCopyInto(out *OperationSpec) {
+	*out = *in
+	out.StorageRef = in.StorageRef
+	if in.DrainNode != nil {
+		in, out := &in.DrainNode, &out.DrainNode
+		*out = new(DrainNodeOperation)
+		**out = **in
+	}
+	if in.ReassignGroups != nil {
+		in, out := &in.ReassignGroups, &out.ReassignGroups
+		*out = new(ReassignGroupsOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SetConfig != nil {
+		in, out := &in.SetConfig, &out.SetConfig
+		*out = new(SetConfigOperation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationSpec.
+func (in *OperationSpec) DeepCopy() *OperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationStatus) DeepCopyInto(out *OperationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationStatus.
+func (in *OperationStatus) DeepCopy() *OperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.StalledThreshold != nil {
+		in, out := &in.StalledThreshold, &out.StalledThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ResourcesResyncPeriod != nil {
+		in, out := &in.ResourcesResyncPeriod, &out.ResourcesResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FinishedJobTTL != nil {
+		in, out := &in.FinishedJobTTL, &out.FinishedJobTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ConfigRevisionHistory != nil {
+		in, out := &in.ConfigRevisionHistory, &out.ConfigRevisionHistory
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CMSOperationInterval != nil {
+		in, out := &in.CMSOperationInterval, &out.CMSOperationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentTenantInitializations != nil {
+		in, out := &in.MaxConcurrentTenantInitializations, &out.MaxConcurrentTenantInitializations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PDiskCheckConcurrency != nil {
+		in, out := &in.PDiskCheckConcurrency, &out.PDiskCheckConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultImage != nil {
+		in, out := &in.DefaultImage, &out.DefaultImage
+		*out = new(DefaultImage)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(OperatorMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionManifest != nil {
+		in, out := &in.VersionManifest, &out.VersionManifest
+		*out = new(VersionManifest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = new(Dashboards)
+		**out = **in
+	}
+	if in.MaxDatabasesPerNamespace != nil {
+		in, out := &in.MaxDatabasesPerNamespace, &out.MaxDatabasesPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReconcileBudget != nil {
+		in, out := &in.ReconcileBudget, &out.ReconcileBudget
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.EventDiffs != nil {
+		in, out := &in.EventDiffs, &out.EventDiffs
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorMetrics) DeepCopyInto(out *OperatorMetrics) {
+	*out = *in
+	if in.ResourceUsageInterval != nil {
+		in, out := &in.ResourceUsageInterval, &out.ResourceUsageInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorMetrics.
+func (in *OperatorMetrics) DeepCopy() *OperatorMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDisksStatus) DeepCopyInto(out *PDisksStatus) {
+	*out = *in
+	if in.PendingPods != nil {
+		in, out := &in.PendingPods, &out.PendingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDisksStatus.
+func (in *PDisksStatus) DeepCopy() *PDisksStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PDisksStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodImage) DeepCopyInto(out *PodImage) {
+	*out = *in
+	if in.PullPolicyName != nil {
+		in, out := &in.PullPolicyName, &out.PullPolicyName
+		*out = new(v1.PullPolicy)
+		**out = **in
+	}
+	if in.PullSecret != nil {
+		in, out := &in.PullSecret, &out.PullSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodImage.
+func (in *PodImage) DeepCopy() *PodImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PodImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyAutoscaling) DeepCopyInto(out *ProxyAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyAutoscaling.
+func (in *ProxyAutoscaling) DeepCopy() *ProxyAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryServiceConfig) DeepCopyInto(out *QueryServiceConfig) {
+	*out = *in
+	if in.MaxSessionsPerNode != nil {
+		in, out := &in.MaxSessionsPerNode, &out.MaxSessionsPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryMemoryLimit != nil {
+		in, out := &in.QueryMemoryLimit, &out.QueryMemoryLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxResultRows != nil {
+		in, out := &in.MaxResultRows, &out.MaxResultRows
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryServiceConfig.
+func (in *QueryServiceConfig) DeepCopy() *QueryServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReassignGroupsOperation) DeepCopyInto(out *ReassignGroupsOperation) {
+	*out = *in
+	if in.VDisks != nil {
+		in, out := &in.VDisks, &out.VDisks
+		*out = make([]VDiskID, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReassignGroupsOperation.
+func (in *ReassignGroupsOperation) DeepCopy() *ReassignGroupsOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReassignGroupsOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceLinks) DeepCopyInto(out *ResourceLinks) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceLinks.
+func (in *ResourceLinks) DeepCopy() *ResourceLinks {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceLinks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsageStatus) DeepCopyInto(out *ResourceUsageStatus) {
+	*out = *in
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
+	out.MaxPodCPU = in.MaxPodCPU.DeepCopy()
+	out.MaxPodMemory = in.MaxPodMemory.DeepCopy()
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsageStatus.
+func (in *ResourceUsageStatus) DeepCopy() *ResourceUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcesSyncStatus) DeepCopyInto(out *ResourcesSyncStatus) {
+	*out = *in
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcesSyncStatus.
+func (in *ResourcesSyncStatus) DeepCopy() *ResourcesSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcesSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaOperationsQuota) DeepCopyInto(out *SchemaOperationsQuota) {
+	*out = *in
+	out.Period = in.Period
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaOperationsQuota.
+func (in *SchemaOperationsQuota) DeepCopy() *SchemaOperationsQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaOperationsQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerlessDatabaseResources) DeepCopyInto(out *ServerlessDatabaseResources) {
+	*out = *in
+	out.SharedDatabaseRef = in.SharedDatabaseRef
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(TenantQuotas)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerlessDatabaseResources.
+func (in *ServerlessDatabaseResources) DeepCopy() *ServerlessDatabaseResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerlessDatabaseResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Service) DeepCopyInto(out *Service) {
+	*out = *in
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalAnnotations != nil {
+		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicyType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
+func (in *Service) DeepCopy() *Service {
+	if in == nil {
+		return nil
+	}
+	out := new(Service)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetConfigOperation) DeepCopyInto(out *SetConfigOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetConfigOperation.
+func (in *SetConfigOperation) DeepCopy() *SetConfigOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(SetConfigOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedDatabaseRef) DeepCopyInto(out *SharedDatabaseRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedDatabaseRef.
+func (in *SharedDatabaseRef) DeepCopy() *SharedDatabaseRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedDatabaseRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpareNodesStatus) DeepCopyInto(out *SpareNodesStatus) {
+	*out = *in
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpareNodesStatus.
+func (in *SpareNodesStatus) DeepCopy() *SpareNodesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpareNodesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticGroupRelocation) DeepCopyInto(out *StaticGroupRelocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticGroupRelocation.
+func (in *StaticGroupRelocation) DeepCopy() *StaticGroupRelocation {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticGroupRelocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusService) DeepCopyInto(out *StatusService) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusService.
+func (in *StatusService) DeepCopy() *StatusService {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Storage) DeepCopyInto(out *Storage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
+func (in *Storage) DeepCopy() *Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Storage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscaling) DeepCopyInto(out *StorageAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscaling.
+func (in *StorageAutoscaling) DeepCopy() *StorageAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscalingStatus) DeepCopyInto(out *StorageAutoscalingStatus) {
+	*out = *in
+	in.LastScaleTime.DeepCopyInto(&out.LastScaleTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscalingStatus.
+func (in *StorageAutoscalingStatus) DeepCopy() *StorageAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageList) DeepCopyInto(out *StorageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Storage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageList.
+func (in *StorageList) DeepCopy() *StorageList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageLocality) DeepCopyInto(out *StorageLocality) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageLocality.
+func (in *StorageLocality) DeepCopy() *StorageLocality {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageLocality)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePoolKind) DeepCopyInto(out *StoragePoolKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolKind.
+func (in *StoragePoolKind) DeepCopy() *StoragePoolKind {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePoolKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageRef) DeepCopyInto(out *StorageRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageRef.
+func (in *StorageRef) DeepCopy() *StorageRef {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageServices) DeepCopyInto(out *StorageServices) {
+	*out = *in
+	in.GRPC.DeepCopyInto(&out.GRPC)
+	in.Interconnect.DeepCopyInto(&out.Interconnect)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageServices.
+func (in *StorageServices) DeepCopy() *StorageServices {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.DataStore != nil {
+		in, out := &in.DataStore, &out.DataStore
+		*out = make([]v1.PersistentVolumeClaimSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DiskAccess != nil {
+		in, out := &in.DiskAccess, &out.DiskAccess
+		*out = new(DiskAccess)
+		**out = **in
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	if in.AdditionalDomains != nil {
+		in, out := &in.AdditionalDomains, &out.AdditionalDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = new(Sysctls)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Image.DeepCopyInto(&out.Image)
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UIAuth != nil {
+		in, out := &in.UIAuth, &out.UIAuth
+		*out = new(UIAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoUpdate != nil {
+		in, out := &in.AutoUpdate, &out.AutoUpdate
+		*out = new(AutoUpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalAnnotations != nil {
+		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalPodLabels != nil {
+		in, out := &in.AdditionalPodLabels, &out.AdditionalPodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StoragePoolKinds != nil {
+		in, out := &in.StoragePoolKinds, &out.StoragePoolKinds
+		*out = make([]StoragePoolKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StateTransitionTime != nil {
+		in, out := &in.StateTransitionTime, &out.StateTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResourcesSync != nil {
+		in, out := &in.ResourcesSync, &out.ResourcesSync
+		*out = new(ResourcesSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MountedObjects != nil {
+		in, out := &in.MountedObjects, &out.MountedObjects
+		*out = make([]MountedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(ResourceUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]HistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PDisks != nil {
+		in, out := &in.PDisks, &out.PDisks
+		*out = new(PDisksStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(CapacityEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(ResourceLinks)
+		**out = **in
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(DecommissionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SpareNodes != nil {
+		in, out := &in.SpareNodes, &out.SpareNodes
+		*out = new(SpareNodesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
+func (in *StorageStatus) DeepCopy() *StorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageTier) DeepCopyInto(out *StorageTier) {
+	*out = *in
+	in.AccessKey.DeepCopyInto(&out.AccessKey)
+	in.SecretKey.DeepCopyInto(&out.SecretKey)
+	if in.EvictAfter != nil {
+		in, out := &in.EvictAfter, &out.EvictAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageTier.
+func (in *StorageTier) DeepCopy() *StorageTier {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageUnit) DeepCopyInto(out *StorageUnit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageUnit.
+func (in *StorageUnit) DeepCopy() *StorageUnit {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageUnit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sysctls) DeepCopyInto(out *Sysctls) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]v1.Sysctl, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sysctls.
+func (in *Sysctls) DeepCopy() *Sysctls {
+	if in == nil {
+		return nil
+	}
+	out := new(Sysctls)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
+	*out = *in
+	in.CertificateAuthority.DeepCopyInto(&out.CertificateAuthority)
+	in.Certificate.DeepCopyInto(&out.Certificate)
+	in.Key.DeepCopyInto(&out.Key)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfiguration.
+func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableReadReplicas) DeepCopyInto(out *TableReadReplicas) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableReadReplicas.
+func (in *TableReadReplicas) DeepCopy() *TableReadReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(TableReadReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAttributes) DeepCopyInto(out *TenantAttributes) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAttributes.
+func (in *TenantAttributes) DeepCopy() *TenantAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantOperationStatus) DeepCopyInto(out *TenantOperationStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantOperationStatus.
+func (in *TenantOperationStatus) DeepCopy() *TenantOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantOptions) DeepCopyInto(out *TenantOptions) {
+	*out = *in
+	if in.PlanResolution != nil {
+		in, out := &in.PlanResolution, &out.PlanResolution
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantOptions.
+func (in *TenantOptions) DeepCopy() *TenantOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantQuotas) DeepCopyInto(out *TenantQuotas) {
+	*out = *in
+	if in.SchemaOperations != nil {
+		in, out := &in.SchemaOperations, &out.SchemaOperations
+		*out = make([]SchemaOperationsQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataStreamShards != nil {
+		in, out := &in.DataStreamShards, &out.DataStreamShards
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantQuotas.
+func (in *TenantQuotas) DeepCopy() *TenantQuotas {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantQuotas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieringConfig) DeepCopyInto(out *TieringConfig) {
+	*out = *in
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]StorageTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieringConfig.
+func (in *TieringConfig) DeepCopy() *TieringConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TieringConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIAuth) DeepCopyInto(out *UIAuth) {
+	*out = *in
+	in.Image.DeepCopyInto(&out.Image)
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	in.CookieSecret.DeepCopyInto(&out.CookieSecret)
+	if in.EmailDomains != nil {
+		in, out := &in.EmailDomains, &out.EmailDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIAuth.
+func (in *UIAuth) DeepCopy() *UIAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(UIAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	if in.BatchInterval != nil {
+		in, out := &in.BatchInterval, &out.BatchInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VDiskID) DeepCopyInto(out *VDiskID) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VDiskID.
+func (in *VDiskID) DeepCopy() *VDiskID {
+	if in == nil {
+		return nil
+	}
+	out := new(VDiskID)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionManifest) DeepCopyInto(out *VersionManifest) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionManifest.
+func (in *VersionManifest) DeepCopy() *VersionManifest {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YdbUser) DeepCopyInto(out *YdbUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YdbUser.
+func (in *YdbUser) DeepCopy() *YdbUser {
+	if in == nil {
+		return nil
+	}
+	out := new(YdbUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *YdbUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YdbUserList) DeepCopyInto(out *YdbUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]YdbUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YdbUserList.
+func (in *YdbUserList) DeepCopy() *YdbUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(YdbUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *YdbUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YdbUserSpec) DeepCopyInto(out *YdbUserSpec) {
+	*out = *in
+	out.DatabaseRef = in.DatabaseRef
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]DatabaseUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]DatabaseGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]DatabaseGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YdbUserSpec.
+func (in *YdbUserSpec) DeepCopy() *YdbUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(YdbUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YdbUserStatus) DeepCopyInto(out *YdbUserStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]AppliedDatabaseUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]DatabaseGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]DatabaseGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YdbUserStatus.
+func (in *YdbUserStatus) DeepCopy() *YdbUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(YdbUserStatus)
 	in.DeepCopyInto(out)
 	return out
 }
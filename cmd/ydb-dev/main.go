@@ -0,0 +1,200 @@
+// Command ydb-dev bootstraps a local development environment: a kind
+// cluster running the operator built from the working tree, with the
+// single-node Storage and Database samples applied.
+//
+//	go run ./cmd/ydb-dev up
+//	go run ./cmd/ydb-dev down
+//
+// It needs docker, kind, helm and kubectl in PATH and is meant to be run
+// from the repository root.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	operatorImageRepository = "ydb-kubernetes-operator"
+	operatorImageTag        = "dev"
+	operatorRelease         = "ydb-operator"
+	operatorChart           = "deploy/ydb-operator"
+
+	storageSample  = "samples/minikube/storage.yaml"
+	databaseSample = "samples/minikube/database.yaml"
+	storageName    = "storage-minikube-sample"
+	databaseName   = "database-minikube-sample"
+
+	pollInterval = 5 * time.Second
+)
+
+type options struct {
+	cluster     string
+	namespace   string
+	skipBuild   bool
+	skipSamples bool
+	timeout     time.Duration
+}
+
+func main() {
+	opts := options{}
+	flags := flag.NewFlagSet("ydb-dev", flag.ExitOnError)
+	flags.StringVar(&opts.cluster, "cluster", "ydb-dev", "Name of the kind cluster.")
+	flags.StringVar(&opts.namespace, "namespace", "default", "Namespace of the operator and the samples.")
+	flags.BoolVar(&opts.skipBuild, "skip-build", false, "Deploy the image built by a previous run.")
+	flags.BoolVar(&opts.skipSamples, "skip-samples", false, "Do not apply the Storage and Database samples.")
+	flags.DurationVar(&opts.timeout, "timeout", 15*time.Minute, "How long to wait for the samples to become Ready.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] up|down\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	if len(os.Args) < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+	_ = flags.Parse(os.Args[2:])
+
+	var err error
+	switch command {
+	case "up":
+		err = up(opts)
+	case "down":
+		err = run("kind", "delete", "cluster", "--name", opts.cluster)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ydb-dev: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func up(opts options) error {
+	for _, tool := range []string{"docker", "kind", "helm", "kubectl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found in PATH", tool)
+		}
+	}
+
+	clusters, err := output("kind", "get", "clusters")
+	if err != nil {
+		return err
+	}
+	if !contains(strings.Fields(clusters), opts.cluster) {
+		if err := run("kind", "create", "cluster", "--name", opts.cluster); err != nil {
+			return err
+		}
+	}
+	context := "kind-" + opts.cluster
+
+	image := operatorImageRepository + ":" + operatorImageTag
+	if !opts.skipBuild {
+		if err := run("docker", "build", "--build-arg", "VERSION="+operatorImageTag, "-t", image, "."); err != nil {
+			return err
+		}
+	}
+	if err := run("kind", "load", "docker-image", image, "--name", opts.cluster); err != nil {
+		return err
+	}
+
+	// The tag never changes, restart the operator to pick up a rebuilt image
+	err = run(
+		"helm", "upgrade", "--install", operatorRelease, operatorChart,
+		"--kube-context", context,
+		"--namespace", opts.namespace,
+		"--set", "image.repository="+operatorImageRepository,
+		"--set", "image.tag="+operatorImageTag,
+		"--set", "image.pullPolicy=Never",
+		"--wait",
+	)
+	if err != nil {
+		return err
+	}
+	if !opts.skipBuild {
+		err = run(
+			"kubectl", "--context", context, "--namespace", opts.namespace,
+			"rollout", "restart", "deployment", "--selector", "app.kubernetes.io/instance="+operatorRelease,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.skipSamples {
+		return nil
+	}
+	deadline := time.Now().Add(opts.timeout)
+	if err := applyAndWait(context, opts.namespace, storageSample, "storage", storageName, deadline); err != nil {
+		return err
+	}
+	if err := applyAndWait(context, opts.namespace, databaseSample, "database", databaseName, deadline); err != nil {
+		return err
+	}
+	fmt.Printf("Database %s is Ready, kubectl context: %s\n", databaseName, context)
+	return nil
+}
+
+// applyAndWait applies the sample and polls its status until it is Ready
+func applyAndWait(context, namespace, file, kind, name string, deadline time.Time) error {
+	if err := run("kubectl", "--context", context, "--namespace", namespace, "apply", "-f", file); err != nil {
+		return err
+	}
+	last := ""
+	for {
+		state, err := output(
+			"kubectl", "--context", context, "--namespace", namespace,
+			"get", kind, name, "-o", "jsonpath={.status.state}",
+		)
+		if err != nil {
+			return err
+		}
+		if state != last {
+			fmt.Printf("%s %s: %s\n", kind, name, state)
+			last = state
+		}
+		if state == "Ready" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s %s is not Ready in time, last state: %q", kind, name, state)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func run(name string, args ...string) error {
+	fmt.Printf("+ %s %s\n", name, strings.Join(args, " "))
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+func output(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
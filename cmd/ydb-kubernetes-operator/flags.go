@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"sort"
+)
+
+// deprecatedSettingFlags maps the flags that used to set the operator
+// settings to the OperatorConfig fields replacing them. The flags are still
+// accepted so that existing deployments keep starting, but their values are
+// ignored.
+var deprecatedSettingFlags = map[string]string{
+	"stalled-threshold":                     "spec.stalledThreshold",
+	"finished-job-ttl":                      "spec.finishedJobTTL",
+	"config-revision-history":               "spec.configRevisionHistory",
+	"cms-operation-interval":                "spec.cmsOperationInterval",
+	"max-concurrent-tenant-initializations": "spec.maxConcurrentTenantInitializations",
+	"max-databases-per-namespace":           "spec.maxDatabasesPerNamespace",
+	"reconcile-budget":                      "spec.reconcileBudget",
+	"event-diffs":                           "spec.eventDiffs",
+	"pdisk-check-concurrency":               "spec.pdiskCheckConcurrency",
+	"version-manifest-url":                  "spec.versionManifest.url",
+	"version-manifest-refresh-interval":     "spec.versionManifest.refreshInterval",
+	"ui-url-template":                       "spec.dashboards.uiURLTemplate",
+	"grafana-url-template":                  "spec.dashboards.grafanaURLTemplate",
+	"feature-gates":                         "spec.featureGates",
+}
+
+type deprecatedFlags map[string]bool
+
+// used returns the deprecated flags set on the command line
+func (f deprecatedFlags) used() []string {
+	var names []string
+	for name, set := range f {
+		if set {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deprecatedFlag accepts and drops the value of a deprecated flag
+type deprecatedFlag struct {
+	name  string
+	flags deprecatedFlags
+	bool  bool
+}
+
+func (f *deprecatedFlag) String() string { return "" }
+
+func (f *deprecatedFlag) Set(string) error {
+	f.flags[f.name] = true
+	return nil
+}
+
+func (f *deprecatedFlag) IsBoolFlag() bool { return f.bool }
+
+func registerDeprecatedSettingFlags(fs *flag.FlagSet) deprecatedFlags {
+	flags := deprecatedFlags{}
+	for name, field := range deprecatedSettingFlags {
+		fs.Var(&deprecatedFlag{name: name, flags: flags, bool: name == "event-diffs"}, name,
+			"Deprecated and ignored, set "+field+" of the OperatorConfig instead.")
+	}
+	return flags
+}
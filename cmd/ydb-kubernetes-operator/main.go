@@ -5,17 +5,32 @@ import (
 	"os"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configexport"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/database"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/dynamicconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/fleetreport"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/operation"
+	operatorconfigcontroller "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/operatorconfig"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/storage"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/ydbuser"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/probes"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/rbacaudit"
 )
 
 var (
@@ -25,17 +40,29 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(ydbv1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		os.Exit(runPreflight(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var disableWebhooks bool
 	var enableServiceMonitors bool
+	var enableConfigExport bool
+	var configExportAddr string
+	var configExportCertDir string
+	var enablePreflight bool
+	var enableRBACAudit bool
 	var probeAddr string
+	settings := operatorconfig.DefaultSettings()
+	eventBudgets := events.DefaultBudgets()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -43,6 +70,23 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&disableWebhooks, "disable-webhooks", false, "Disable webhooks registration on start.")
 	flag.BoolVar(&enableServiceMonitors, "with-service-monitors", false, "Enables service monitoring")
+	flag.BoolVar(&enableConfigExport, "enable-config-export", false,
+		"Serve the rendered ydbd configs under /configs to the clients allowed to get the path.")
+	flag.StringVar(&configExportAddr, "config-export-bind-address", ":8444", "The address the config export endpoint binds to.")
+	flag.StringVar(&configExportCertDir, "config-export-cert-dir", "",
+		"Directory with the tls.crt and tls.key of the config export endpoint, it serves plain HTTP when empty.")
+	flag.BoolVar(&enablePreflight, "preflight", false,
+		"Check the cluster prerequisites of YDB on start and log the checks that did not pass.")
+	flag.BoolVar(&enableRBACAudit, "rbac-audit", false,
+		"Record the API requests of the operator and serve the minimal role allowing them under /rbac-audit on the metrics endpoint.")
+	flag.DurationVar(&settings.RequeueBackoffBase, "requeue-backoff-base", settings.RequeueBackoffBase,
+		"Delay of the first retry of a failed or waiting reconcile, doubled on every next one. Read on start.")
+	flag.DurationVar(&settings.RequeueBackoffMax, "requeue-backoff-max", settings.RequeueBackoffMax,
+		"Upper bound of the retry delay of a failed or waiting reconcile. Read on start.")
+	flag.IntVar(&eventBudgets.Default.Events, "event-budget", eventBudgets.Default.Events,
+		"Events of a reason emitted per minute for an object, the ones over it are suppressed. "+
+			"Reasons emitted on every retry have a lower budget of their own. Zero means no limit.")
+	deprecated := registerDeprecatedSettingFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -51,11 +95,31 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	for _, name := range deprecated.used() {
+		setupLog.Info("ignoring deprecated flag, set the field of the OperatorConfig instead",
+			"flag", name, "field", deprecatedSettingFlags[name])
+	}
+	settingsStore := operatorconfig.NewStore(settings)
+
+	if chaos.Enabled {
+		setupLog.Info("fault injection hooks are enabled, do not use this build in production")
+	}
+
 	if enableServiceMonitors {
 		utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	config := ctrl.GetConfigOrDie()
+	if enablePreflight {
+		logPreflight(config)
+	}
+	var rbacRecorder *rbacaudit.Recorder
+	if enableRBACAudit {
+		rbacRecorder = rbacaudit.NewRecorder()
+		config.Wrap(rbacRecorder.Wrap)
+	}
+
+	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
@@ -68,11 +132,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	recorder := events.NewRecorder(mgr.GetEventRecorderFor("ydb-operator"), eventBudgets)
+	versions := autoupdate.NewSource()
 	if err = (&database.Reconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Config:   mgr.GetConfig(),
-		Recorder: mgr.GetEventRecorderFor("ydb-operator"),
+		Recorder: recorder,
+
+		Settings: settingsStore,
+		Versions: versions,
+		CMSQueue: cms.NewOperationQueue(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Database")
 		os.Exit(1)
@@ -81,14 +151,64 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Config:   mgr.GetConfig(),
-		Recorder: mgr.GetEventRecorderFor("ydb-operator"),
+		Recorder: recorder,
 
+		Settings:            settingsStore,
+		Versions:            versions,
 		WithServiceMonitors: enableServiceMonitors,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Storage")
 		os.Exit(1)
 	}
 
+	if err = (&operation.Reconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Config:   mgr.GetConfig(),
+		Recorder: recorder,
+		Settings: settingsStore,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Operation")
+		os.Exit(1)
+	}
+
+	if err = (&dynamicconfig.Reconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: recorder,
+		Settings: settingsStore,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DynamicConfig")
+		os.Exit(1)
+	}
+
+	if err = (&ydbuser.Reconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: recorder,
+		Settings: settingsStore,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "YdbUser")
+		os.Exit(1)
+	}
+
+	if err = (&fleetreport.Reconciler{
+		Client:   mgr.GetClient(),
+		Recorder: recorder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FleetReport")
+		os.Exit(1)
+	}
+
+	if err = (&operatorconfigcontroller.Reconciler{
+		Client:   mgr.GetClient(),
+		Recorder: recorder,
+		Settings: settingsStore,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+
 	if !disableWebhooks {
 		if err = (&ydbv1alpha1.Storage{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Storage")
@@ -98,6 +218,34 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Database")
 			os.Exit(1)
 		}
+		if err = (&ydbv1alpha1.DynamicConfig{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DynamicConfig")
+			os.Exit(1)
+		}
+		if err = (&ydbv1alpha1.YdbUser{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "YdbUser")
+			os.Exit(1)
+		}
+		ydbv1alpha1.SetupCapacityWarningWebhookWithManager(mgr)
+	}
+	if enableConfigExport {
+		if configExportCertDir == "" {
+			setupLog.Info("config export serves plain HTTP, set --config-export-cert-dir to protect the bearer tokens")
+		}
+		if err := mgr.Add(&configexport.Server{
+			Addr:    configExportAddr,
+			CertDir: configExportCertDir,
+			Handler: configexport.Authorize(mgr.GetClient(), &configexport.Handler{Reader: mgr.GetAPIReader()}),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up config export")
+			os.Exit(1)
+		}
+	}
+	if rbacRecorder != nil {
+		if err := mgr.AddMetricsExtraHandler(rbacaudit.Path, rbacRecorder); err != nil {
+			setupLog.Error(err, "unable to set up RBAC audit")
+			os.Exit(1)
+		}
 	}
 	//+kubebuilder:scaffold:builder
 
@@ -105,9 +253,25 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
-		os.Exit(1)
+	// Each controller is ready once its CRD is established and its informer
+	// has synced, served on /readyz/<controller>
+	readyzChecks := map[string]healthz.Checker{
+		"storage":        controllerReady(mgr, &ydbv1alpha1.Storage{}, "storages.ydb.tech"),
+		"database":       controllerReady(mgr, &ydbv1alpha1.Database{}, "databases.ydb.tech"),
+		"operation":      controllerReady(mgr, &ydbv1alpha1.Operation{}, "operations.ydb.tech"),
+		"dynamicconfig":  controllerReady(mgr, &ydbv1alpha1.DynamicConfig{}, "dynamicconfigs.ydb.tech"),
+		"ydbuser":        controllerReady(mgr, &ydbv1alpha1.YdbUser{}, "ydbusers.ydb.tech"),
+		"fleetreport":    controllerReady(mgr, &ydbv1alpha1.FleetReport{}, "fleetreports.ydb.tech"),
+		"operatorconfig": controllerReady(mgr, &ydbv1alpha1.OperatorConfig{}, "operatorconfigs.ydb.tech"),
+	}
+	if !disableWebhooks {
+		readyzChecks["webhooks"] = mgr.GetWebhookServer().StartedChecker()
+	}
+	for name, check := range readyzChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			os.Exit(1)
+		}
 	}
 
 	setupLog.Info("starting manager")
@@ -116,3 +280,10 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func controllerReady(mgr ctrl.Manager, obj client.Object, crd string) healthz.Checker {
+	return probes.All(
+		probes.CRDEstablished(mgr.GetAPIReader(), crd),
+		probes.InformerSynced(mgr.GetCache(), obj),
+	)
+}
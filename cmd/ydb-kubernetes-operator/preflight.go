@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/preflight"
+)
+
+const preflightTimeout = 30 * time.Second
+
+// runPreflight implements the preflight subcommand: it checks the cluster of
+// the current kubeconfig, prints a line per check and exits non-zero when
+// any of them fails.
+//
+//	ydb-kubernetes-operator preflight [--kubeconfig path]
+func runPreflight(args []string) int {
+	flags := flag.NewFlagSet("preflight", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s preflight [--kubeconfig path]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// --kubeconfig is registered on the global flag set by controller-runtime
+	kubeconfig := flag.CommandLine.Lookup("kubeconfig")
+	flags.Var(kubeconfig.Value, kubeconfig.Name, kubeconfig.Usage)
+	_ = flags.Parse(args)
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preflight: %s\n", err)
+		return 1
+	}
+	results, err := checkPrerequisites(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preflight: %s\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tMESSAGE")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Check, result.Status, result.Message)
+	}
+	_ = w.Flush()
+
+	if preflight.Failed(results) {
+		return 1
+	}
+	return 0
+}
+
+// logPreflight runs the checks on startup and logs the ones that did not
+// pass. They don't stop the operator, the cluster may be fixed later.
+func logPreflight(config *rest.Config) {
+	results, err := checkPrerequisites(config)
+	if err != nil {
+		setupLog.Error(err, "unable to run preflight checks")
+		return
+	}
+	for _, result := range results {
+		if result.Status != preflight.StatusPass {
+			setupLog.Info("preflight check did not pass", "check", result.Check, "status", result.Status, "message", result.Message)
+		}
+	}
+}
+
+func checkPrerequisites(config *rest.Config) ([]preflight.Result, error) {
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+	return preflight.Run(ctx, config, c)
+}
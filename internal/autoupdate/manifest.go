@@ -0,0 +1,81 @@
+// Package autoupdate picks the releases rolled by the Storage and Database
+// auto-update policies. The released versions come from a JSON manifest
+// published by the operator administrators:
+//
+//	{"channels": {"stable": ["23.1.19", "23.1.26"], "testing": ["23.2.9"]}}
+package autoupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const DefaultChannel = "stable"
+
+type Manifest struct {
+	Channels map[string][]string `json:"channels"`
+}
+
+// Source downloads the manifest and keeps it for the refresh interval, so
+// reconciles of many resources do not hit the manifest server each time.
+type Source struct {
+	Client *http.Client
+
+	mu        sync.Mutex
+	url       string
+	manifest  *Manifest
+	fetchedAt time.Time
+}
+
+func NewSource() *Source {
+	return &Source{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Versions returns the versions released in the channel
+func (s *Source) Versions(ctx context.Context, url string, refresh time.Duration, channel string) ([]string, error) {
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.manifest == nil || s.url != url || time.Since(s.fetchedAt) >= refresh {
+		manifest, err := s.fetch(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		s.url, s.manifest, s.fetchedAt = url, manifest, time.Now()
+	}
+
+	versions, ok := s.manifest.Channels[channel]
+	if !ok {
+		return nil, fmt.Errorf("channel %q not found in version manifest", channel)
+	}
+	return versions, nil
+}
+
+func (s *Source) fetch(ctx context.Context, url string) (*Manifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download version manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download version manifest: %s", resp.Status)
+	}
+	manifest := &Manifest{}
+	if err := json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse version manifest: %w", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,103 @@
+package autoupdate
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ImageTag returns the tag of the image reference, empty when it has none
+// or is pinned by digest
+func ImageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// WithTag returns the image reference with the tag replaced
+func WithTag(image, tag string) string {
+	if current := ImageTag(image); current != "" {
+		image = strings.TrimSuffix(image, ":"+current)
+	}
+	return image + ":" + tag
+}
+
+// NextPatch returns the latest of versions that shares the major and minor
+// version with current and is newer than it. Versions newer than limit are
+// skipped unless limit is empty.
+func NextPatch(current string, versions []string, limit string) (string, bool) {
+	base, ok := parse(current)
+	if !ok {
+		return "", false
+	}
+	ceiling, hasLimit := parse(limit)
+
+	best, bestVersion := "", base
+	for _, candidate := range versions {
+		version, ok := parse(candidate)
+		if !ok || version[0] != base[0] || version[1] != base[1] {
+			continue
+		}
+		if hasLimit && compare(version, ceiling) > 0 {
+			continue
+		}
+		if compare(version, bestVersion) > 0 {
+			best, bestVersion = candidate, version
+		}
+	}
+	return best, best != ""
+}
+
+// Newer reports whether version a is newer than version b, false when
+// either of them is not a version
+func Newer(a, b string) bool {
+	x, ok := parse(a)
+	if !ok {
+		return false
+	}
+	y, ok := parse(b)
+	if !ok {
+		return false
+	}
+	return compare(x, y) > 0
+}
+
+// parse splits versions like 23.1.26 or 23.1.26.3 into numeric components
+func parse(version string) ([]int, bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 3 {
+		return nil, false
+	}
+	result := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		result = append(result, n)
+	}
+	return result, true
+}
+
+func compare(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
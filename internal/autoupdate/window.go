@@ -0,0 +1,45 @@
+package autoupdate
+
+import (
+	"time"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// InWindow reports whether now falls into the maintenance window. A nil
+// window is always open.
+func InWindow(window *v1alpha1.MaintenanceWindow, now time.Time) bool {
+	if window == nil {
+		return true
+	}
+	start, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return false
+	}
+
+	now = now.UTC()
+	// The window may have opened the day before and still be open
+	for _, days := range []int{0, -1} {
+		day := now.AddDate(0, 0, days)
+		opened := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !dayAllowed(window.Days, opened.Weekday()) {
+			continue
+		}
+		if !now.Before(opened) && now.Before(opened.Add(window.Duration.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func dayAllowed(days []v1alpha1.Weekday, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, allowed := range days {
+		if string(allowed) == day.String() {
+			return true
+		}
+	}
+	return false
+}
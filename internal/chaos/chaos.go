@@ -0,0 +1,25 @@
+// Package chaos provides fault injection hooks for e2e and soak tests.
+//
+// Faults are configured per object with annotations of the form
+//
+//	chaos.ydb.tech/<point>: "fail"
+//	chaos.ydb.tech/<point>: "delay=5s"
+//	chaos.ydb.tech/<point>: "delay=5s,fail"
+//
+// where <point> is a sync step name (e.g. handleTenantCreation) or one of
+// the CMS call points below. Injection is only compiled in when the operator
+// is built with the "chaos" build tag; release builds ignore the annotations.
+package chaos
+
+import "errors"
+
+const AnnotationPrefix = "chaos.ydb.tech/"
+
+const (
+	CMSGetDatabaseStatus = "cms.GetDatabaseStatus"
+	CMSCreateDatabase    = "cms.CreateDatabase"
+	CMSRemoveDatabase    = "cms.RemoveDatabase"
+	BlobstorageInit      = "blobstorage.Init"
+)
+
+var ErrInjected = errors.New("injected fault")
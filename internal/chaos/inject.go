@@ -0,0 +1,51 @@
+//go:build chaos
+// +build chaos
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Enabled reports whether fault injection is compiled into the binary.
+const Enabled = true
+
+// Inject applies the fault configured for point on obj, if any. Delays block
+// until they elapse or ctx is done; "fail" returns an error wrapping ErrInjected.
+func Inject(ctx context.Context, obj client.Object, point string) error {
+	value, ok := obj.GetAnnotations()[AnnotationPrefix+point]
+	if !ok {
+		return nil
+	}
+
+	fail := false
+	for _, action := range strings.Split(value, ",") {
+		action = strings.TrimSpace(action)
+		switch {
+		case action == "fail":
+			fail = true
+		case strings.HasPrefix(action, "delay="):
+			delay, err := time.ParseDuration(strings.TrimPrefix(action, "delay="))
+			if err != nil {
+				return fmt.Errorf("invalid %s%s annotation: %w", AnnotationPrefix, point, err)
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		default:
+			return fmt.Errorf("invalid %s%s annotation: unknown action %q", AnnotationPrefix, point, action)
+		}
+	}
+
+	if fail {
+		return fmt.Errorf("%w at %s", ErrInjected, point)
+	}
+	return nil
+}
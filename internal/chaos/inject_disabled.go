@@ -0,0 +1,18 @@
+//go:build !chaos
+// +build !chaos
+
+package chaos
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Enabled reports whether fault injection is compiled into the binary.
+const Enabled = false
+
+// Inject is a no-op in builds without the "chaos" tag.
+func Inject(context.Context, client.Object, string) error {
+	return nil
+}
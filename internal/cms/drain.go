@@ -0,0 +1,121 @@
+package cms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// Hive balances the tablets of the root domain and of the tenants
+	// without their own Hive
+	rootHiveTabletID = "72057594037968897"
+
+	hiveAppPath    = "/tablets/app"
+	tabletInfoPath = "/viewer/json/tabletinfo"
+	describePath   = "/viewer/json/describe"
+	requestTimeout = 10 * time.Second
+)
+
+type tabletInfoResponse struct {
+	TabletStateInfo []struct {
+		TabletId string `json:"TabletId"`
+		State    string `json:"State"`
+		Leader   bool   `json:"Leader"`
+	} `json:"TabletStateInfo"`
+}
+
+type describeResponse struct {
+	PathDescription struct {
+		DomainDescription struct {
+			ProcessingParams struct {
+				Hive json.Number `json:"Hive"`
+			} `json:"ProcessingParams"`
+		} `json:"DomainDescription"`
+	} `json:"PathDescription"`
+}
+
+// TenantHive returns the id of the Hive tablet balancing the tablets of the
+// tenant at path, the root Hive when the tenant has no Hive of its own
+func TenantHive(ctx context.Context, endpoint, path string) (string, error) {
+	body, err := viewerGet(ctx, endpoint, describePath, url.Values{"path": {path}})
+	if err != nil {
+		return "", err
+	}
+
+	description := describeResponse{}
+	if err = json.Unmarshal(body, &description); err != nil {
+		return "", err
+	}
+	hive := description.PathDescription.DomainDescription.ProcessingParams.Hive.String()
+	if hive == "" || hive == "0" {
+		return rootHiveTabletID, nil
+	}
+	return hive, nil
+}
+
+// DrainNode asks the Hive tablet hiveID, through the status service at
+// endpoint (host:port), to move the tablets off the node and to stop placing
+// new ones there. The drain runs in the background, see NodeTablets for its
+// progress.
+func DrainNode(ctx context.Context, endpoint, hiveID string, nodeID uint32) error {
+	query := url.Values{
+		"TabletID": {hiveID},
+		"page":     {"DrainNode"},
+		"node":     {strconv.FormatUint(uint64(nodeID), 10)},
+	}
+	_, err := viewerGet(ctx, endpoint, hiveAppPath, query)
+	return err
+}
+
+// NodeTablets returns the number of tablet leaders still running on the
+// node, as reported by its whiteboard
+func NodeTablets(ctx context.Context, endpoint string, nodeID uint32) (int32, error) {
+	query := url.Values{"node_id": {strconv.FormatUint(uint64(nodeID), 10)}}
+	body, err := viewerGet(ctx, endpoint, tabletInfoPath, query)
+	if err != nil {
+		return 0, err
+	}
+
+	info := tabletInfoResponse{}
+	if err = json.Unmarshal(body, &info); err != nil {
+		return 0, err
+	}
+	var tablets int32
+	for _, tablet := range info.TabletStateInfo {
+		if tablet.Leader && tablet.State != "Dead" {
+			tablets++
+		}
+	}
+	return tablets, nil
+}
+
+func viewerGet(ctx context.Context, endpoint, path string, query url.Values) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("http://%s%s?%s", endpoint, path, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected viewer response status: %s", response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
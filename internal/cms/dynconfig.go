@@ -0,0 +1,74 @@
+package cms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/grpc"
+)
+
+const (
+	replaceConfigMethod = "/Ydb.DynamicConfig.V1.DynamicConfigService/ReplaceConfig"
+
+	// Fields of ReplaceConfigRequest, the dynamic config service is missing
+	// in the vendored protos
+	replaceConfigFieldNumber      protowire.Number = 2
+	allowUnknownFieldsFieldNumber protowire.Number = 4
+)
+
+type DynamicConfig struct {
+	StorageEndpoint      string
+	UseGrpcSecureChannel bool
+}
+
+// Replace issues ReplaceConfig to the console to apply the dynamic
+// configuration document. The console rejects documents whose metadata
+// doesn't match the cluster name or the current version.
+func (c *DynamicConfig) Replace(ctx context.Context, config string, allowUnknownFields bool) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  c.StorageEndpoint,
+	}
+	request := &emptypb.Empty{}
+	request.ProtoReflect().SetUnknown(encodeReplaceConfig(config, allowUnknownFields))
+	logger.Info(fmt.Sprintf("replacing dynamic config, endpoint: %s, secure: %t", c.StorageEndpoint, c.UseGrpcSecureChannel))
+	// ReplaceConfigResponse carries the operation in field 1, the same as
+	// the CMS responses
+	response := &Ydb_Cms.AlterDatabaseResponse{}
+	err := client.Invoke(
+		replaceConfigMethod,
+		request,
+		response,
+		c.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("replacing dynamic config, response: %s, err: %s", response, err))
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReplyFromStorage
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
+
+func encodeReplaceConfig(config string, allowUnknownFields bool) protoreflect.RawFields {
+	var raw []byte
+	raw = protowire.AppendTag(raw, replaceConfigFieldNumber, protowire.BytesType)
+	raw = protowire.AppendString(raw, config)
+	if allowUnknownFields {
+		raw = protowire.AppendTag(raw, allowUnknownFieldsFieldNumber, protowire.VarintType)
+		raw = protowire.AppendVarint(raw, 1)
+	}
+	return raw
+}
@@ -0,0 +1,137 @@
+package cms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// JSON gateway of the CMS tablet on the status service, taking the
+	// NKikimrCms permission messages
+	requestPermissionPath = "/cms/api/json/permission"
+	managePermissionPath  = "/cms/api/json/managePermission"
+
+	permissionUser     = "ydb-kubernetes-operator"
+	dynamicNodeService = "dynnode"
+
+	permissionStatusAllow = "ALLOW"
+)
+
+type permissionAction struct {
+	Type     string   `json:"Type"`
+	Host     string   `json:"Host"`
+	Services []string `json:"Services"`
+	Duration uint64   `json:"Duration"`
+}
+
+type permissionRequest struct {
+	User    string             `json:"User"`
+	Actions []permissionAction `json:"Actions"`
+	// Microseconds
+	Duration uint64 `json:"Duration"`
+}
+
+type permissionResponse struct {
+	Status struct {
+		Code   string `json:"Code"`
+		Reason string `json:"Reason"`
+	} `json:"Status"`
+	Permissions []struct {
+		Id string `json:"Id"`
+	} `json:"Permissions"`
+}
+
+type managePermissionRequest struct {
+	User        string   `json:"User"`
+	Command     string   `json:"Command"`
+	Permissions []string `json:"Permissions"`
+}
+
+// RestartPermission is the answer of CMS to a request to restart a node
+type RestartPermission struct {
+	Allowed bool
+	// Set when the restart is allowed, release it with DonePermission
+	ID string
+	// Why the restart is not allowed right now
+	Reason string
+}
+
+// RequestRestart asks CMS, through the status service at endpoint
+// (host:port), whether the dynamic node on host may go down for up to
+// duration. CMS refuses while too many nodes are unavailable.
+func RequestRestart(ctx context.Context, endpoint, host string, duration time.Duration) (RestartPermission, error) {
+	micros := uint64(duration / time.Microsecond)
+	request := permissionRequest{
+		User: permissionUser,
+		Actions: []permissionAction{{
+			Type:     "RESTART_SERVICES",
+			Host:     host,
+			Services: []string{dynamicNodeService},
+			Duration: micros,
+		}},
+		Duration: micros,
+	}
+	response := permissionResponse{}
+	if err := cmsPost(ctx, endpoint, requestPermissionPath, request, &response); err != nil {
+		return RestartPermission{}, err
+	}
+
+	if response.Status.Code != permissionStatusAllow {
+		return RestartPermission{Reason: fmt.Sprintf("%s: %s", response.Status.Code, response.Status.Reason)}, nil
+	}
+	if len(response.Permissions) == 0 {
+		return RestartPermission{}, ErrEmptyReplyFromStorage
+	}
+	return RestartPermission{Allowed: true, ID: response.Permissions[0].Id}, nil
+}
+
+// DonePermission tells CMS that the node is back and the permission is no
+// longer needed
+func DonePermission(ctx context.Context, endpoint, id string) error {
+	request := managePermissionRequest{
+		User:        permissionUser,
+		Command:     "DONE",
+		Permissions: []string{id},
+	}
+	return cmsPost(ctx, endpoint, managePermissionPath, request, nil)
+}
+
+func cmsPost(ctx context.Context, endpoint, path string, request, response interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("http://%s%s", endpoint, path),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := http.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected CMS response status: %s", httpResponse.Status)
+	}
+	if response == nil {
+		_, err = io.Copy(io.Discard, httpResponse.Body)
+		return err
+	}
+	return json.NewDecoder(httpResponse.Body).Decode(response)
+}
@@ -0,0 +1,79 @@
+package cms
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// OperationQueue serializes tenant create/alter/delete calls per Storage
+// and keeps a minimum interval between them, so mass provisioning of
+// Databases sharing one Storage does not hit CMS with a burst of requests.
+// The queue is process-local: it relies on a single active operator
+// replica, which leader election guarantees.
+type OperationQueue struct {
+	mu       sync.Mutex
+	storages map[types.NamespacedName]*operationWindow
+}
+
+type operationWindow struct {
+	holder   types.NamespacedName
+	busy     bool
+	released time.Time
+}
+
+func NewOperationQueue() *OperationQueue {
+	return &OperationQueue{
+		storages: map[types.NamespacedName]*operationWindow{},
+	}
+}
+
+// TryAcquire grants holder the right to run a CMS operation against storage.
+// When the window is taken or interval since the previous operation has
+// not passed yet, it returns false and how long to wait before retrying.
+// A nil queue grants every request.
+func (q *OperationQueue) TryAcquire(
+	storage, holder types.NamespacedName,
+	interval time.Duration,
+	now time.Time,
+) (bool, time.Duration) {
+	if q == nil {
+		return true, 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	window, ok := q.storages[storage]
+	if !ok {
+		window = &operationWindow{}
+		q.storages[storage] = window
+	}
+	if window.busy && window.holder != holder {
+		return false, interval
+	}
+	if wait := window.released.Add(interval).Sub(now); !window.busy && wait > 0 {
+		return false, wait
+	}
+
+	window.busy = true
+	window.holder = holder
+	return true, 0
+}
+
+// Release frees the window taken by holder and starts the interval
+// before the next operation against storage.
+func (q *OperationQueue) Release(storage, holder types.NamespacedName, now time.Time) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	window, ok := q.storages[storage]
+	if !ok || !window.busy || window.holder != holder {
+		return
+	}
+	window.busy = false
+	window.released = now
+}
@@ -0,0 +1,35 @@
+package cms
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+const sysInfoPath = "/viewer/json/sysinfo"
+
+type sysInfoResponse struct {
+	SystemStateInfo []struct {
+		Version string `json:"Version"`
+	} `json:"SystemStateInfo"`
+}
+
+// NodeVersion returns the YDB version of the node serving the status
+// service at endpoint (host:port), as reported by its whiteboard
+func NodeVersion(ctx context.Context, endpoint string) (string, error) {
+	body, err := viewerGet(ctx, endpoint, sysInfoPath, url.Values{"node_id": {"."}})
+	if err != nil {
+		return "", err
+	}
+
+	info := sysInfoResponse{}
+	if err = json.Unmarshal(body, &info); err != nil {
+		return "", err
+	}
+	for _, node := range info.SystemStateInfo {
+		if node.Version != "" {
+			return node.Version, nil
+		}
+	}
+	return "", ErrEmptyReplyFromStorage
+}
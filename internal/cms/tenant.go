@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
@@ -14,10 +19,22 @@ import (
 )
 
 const (
-	createDatabaseMethod = "/Ydb.Cms.V1.CmsService/CreateDatabase"
+	// alter_attributes of AlterDatabaseRequest, missing in the vendored protos
+	alterAttributesFieldNumber protowire.Number = 12
+
+	createDatabaseMethod    = "/Ydb.Cms.V1.CmsService/CreateDatabase"
+	alterDatabaseMethod     = "/Ydb.Cms.V1.CmsService/AlterDatabase"
+	removeDatabaseMethod    = "/Ydb.Cms.V1.CmsService/RemoveDatabase"
+	getDatabaseStatusMethod = "/Ydb.Cms.V1.CmsService/GetDatabaseStatus"
+	getOperationMethod      = "/Ydb.Operation.V1.OperationService/GetOperation"
 )
 
-var ErrEmptyReplyFromStorage = errors.New("empty reply from storage")
+var (
+	ErrEmptyReplyFromStorage = errors.New("empty reply from storage")
+	ErrTenantNotFound        = errors.New("tenant not found")
+	ErrOperationNotFound     = errors.New("operation not found")
+	ErrOperationFailed       = errors.New("operation failed")
+)
 
 type Tenant struct {
 	StorageEndpoint      string
@@ -26,13 +43,25 @@ type Tenant struct {
 	Shared               bool
 	SharedDatabasePath   string
 	UseGrpcSecureChannel bool
+	CA                   []byte
+	IdempotencyKey       string
+	Attributes           map[string]string
+	Quotas               *ydbv1alpha1.TenantQuotas
+	DataSizeQuota        *ydbv1alpha1.DataSizeQuota
+	PlanResolution       uint32
 }
 
-func (t *Tenant) Create(ctx context.Context) error {
+// Create issues CreateDatabase to CMS in the async mode. The id of the
+// operation is returned while CMS is still creating the tenant, poll it
+// with CheckOperation; it is empty when the tenant is ready right away. A
+// tenant that already exists is not considered an error, so Create is safe
+// to call repeatedly.
+func (t *Tenant) Create(ctx context.Context) (string, error) {
 	logger := log.FromContext(ctx)
 	client := grpc.Client{
 		Context: ctx,
 		Target:  t.StorageEndpoint,
+		CA:      t.CA,
 	}
 	logger.Info(fmt.Sprintf("creating tenant, endpoint: %s, secure: %t, method: %s", t.StorageEndpoint, t.UseGrpcSecureChannel, createDatabaseMethod))
 	request := t.makeCreateDatabaseRequest()
@@ -44,15 +73,292 @@ func (t *Tenant) Create(ctx context.Context) error {
 		response,
 		t.UseGrpcSecureChannel,
 	)
-	if _, err := processDatabaseCreationResponse(response); err != nil {
+	logger.Info(fmt.Sprintf("creating tenant, response: %s, err: %s", response, grpcCallResult))
+	if grpcCallResult != nil {
+		return "", grpcCallResult
+	}
+	if response.Operation != nil && !response.Operation.Ready && response.Operation.Id != "" {
+		return response.Operation.Id, nil
+	}
+	_, err := processDatabaseCreationResponse(response.Operation)
+	return "", err
+}
+
+// CheckOperation polls the CreateDatabase operation returned by Create. It
+// reports whether the operation is ready, a failed operation is returned
+// as ErrOperationFailed. ErrOperationNotFound is returned when CMS forgot the
+// operation, e.g. after a restart.
+func (t *Tenant) CheckOperation(ctx context.Context, id string) (bool, error) {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	response := &Ydb_Operations.GetOperationResponse{}
+	err := client.Invoke(
+		getOperationMethod,
+		&Ydb_Operations.GetOperationRequest{Id: id},
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("checking tenant operation, response: %s, err: %s", response, err))
+	if err != nil {
+		return false, err
+	}
+	if response.Operation == nil {
+		return false, ErrEmptyReplyFromStorage
+	}
+	if response.Operation.Status == Ydb.StatusIds_NOT_FOUND {
+		return false, ErrOperationNotFound
+	}
+	if !response.Operation.Ready {
+		return false, nil
+	}
+	if _, err = processDatabaseCreationResponse(response.Operation); err != nil {
+		return true, fmt.Errorf("%w: %s", ErrOperationFailed, err)
+	}
+	return true, nil
+}
+
+// GetStatus fetches the tenant state from CMS. ErrTenantNotFound is
+// returned when CMS does not know about the tenant.
+func (t *Tenant) GetStatus(ctx context.Context) (*Ydb_Cms.GetDatabaseStatusResult, error) {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	response := &Ydb_Cms.GetDatabaseStatusResponse{}
+	err := client.Invoke(
+		getDatabaseStatusMethod,
+		&Ydb_Cms.GetDatabaseStatusRequest{Path: t.Path},
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("getting tenant status, response: %s, err: %s", response, err))
+	if err != nil {
+		return nil, err
+	}
+	if response.Operation == nil {
+		return nil, ErrEmptyReplyFromStorage
+	}
+	switch response.Operation.Status {
+	case Ydb.StatusIds_SUCCESS:
+	case Ydb.StatusIds_NOT_FOUND:
+		return nil, ErrTenantNotFound
+	default:
+		return nil, fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+
+	result := &Ydb_Cms.GetDatabaseStatusResult{}
+	if err = proto.Unmarshal(response.Operation.Result.GetValue(), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Remove issues RemoveDatabase to CMS. A tenant that does not exist is
+// not considered an error, so Remove is safe to call repeatedly.
+func (t *Tenant) Remove(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	logger.Info(fmt.Sprintf("removing tenant, path: %s", t.Path))
+	response := &Ydb_Cms.RemoveDatabaseResponse{}
+	err := client.Invoke(
+		removeDatabaseMethod,
+		&Ydb_Cms.RemoveDatabaseRequest{Path: t.Path},
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("removing tenant, response: %s, err: %s", response, err))
+	if err != nil {
 		return err
 	}
-	logger.Info(fmt.Sprintf("creating tenant, response: %s, err: %s", response, grpcCallResult))
-	return grpcCallResult
+	if response.Operation == nil {
+		return ErrEmptyReplyFromStorage
+	}
+	switch response.Operation.Status {
+	case Ydb.StatusIds_SUCCESS, Ydb.StatusIds_NOT_FOUND:
+		return nil
+	default:
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+}
+
+// AddStorageUnits issues AlterDatabase to CMS to allocate additional
+// storage units for the tenant.
+func (t *Tenant) AddStorageUnits(ctx context.Context, units []ydbv1alpha1.StorageUnit) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	request := &Ydb_Cms.AlterDatabaseRequest{Path: t.Path}
+	for _, unit := range units {
+		request.StorageUnitsToAdd = append(
+			request.StorageUnitsToAdd,
+			&Ydb_Cms.StorageUnits{UnitKind: unit.UnitKind, Count: unit.Count},
+		)
+	}
+	logger.Info(fmt.Sprintf("altering tenant, request: %s", request))
+	response := &Ydb_Cms.AlterDatabaseResponse{}
+	err := client.Invoke(
+		alterDatabaseMethod,
+		request,
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("altering tenant, response: %s, err: %s", response, err))
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReplyFromStorage
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
+
+// AlterAttributes issues AlterDatabase to CMS to set the tenant user
+// attributes. Attributes with an empty value are removed.
+func (t *Tenant) AlterAttributes(ctx context.Context, attributes map[string]string) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	request := &Ydb_Cms.AlterDatabaseRequest{Path: t.Path}
+	request.ProtoReflect().SetUnknown(encodeAlterAttributes(attributes))
+	logger.Info(fmt.Sprintf("altering tenant attributes, path: %s, attributes: %v", t.Path, attributes))
+	response := &Ydb_Cms.AlterDatabaseResponse{}
+	err := client.Invoke(
+		alterDatabaseMethod,
+		request,
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("altering tenant attributes, response: %s, err: %s", response, err))
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReplyFromStorage
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
+
+// SetQuotas issues AlterDatabase to CMS to replace the tenant quotas and
+// data size quota. Nil quotas lift the limits.
+func (t *Tenant) SetQuotas(
+	ctx context.Context,
+	quotas *ydbv1alpha1.TenantQuotas,
+	dataSize *ydbv1alpha1.DataSizeQuota,
+) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context: ctx,
+		Target:  t.StorageEndpoint,
+		CA:      t.CA,
+	}
+	request := &Ydb_Cms.AlterDatabaseRequest{Path: t.Path}
+	request.SchemaOperationQuotas, request.DatabaseQuotas = makeQuotas(quotas, dataSize)
+	logger.Info(fmt.Sprintf("altering tenant quotas, request: %s", request))
+	response := &Ydb_Cms.AlterDatabaseResponse{}
+	err := client.Invoke(
+		alterDatabaseMethod,
+		request,
+		response,
+		t.UseGrpcSecureChannel,
+	)
+	logger.Info(fmt.Sprintf("altering tenant quotas, response: %s, err: %s", response, err))
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReplyFromStorage
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
+
+// makeQuotas converts the quotas into the messages of the CMS requests,
+// zero values meaning no limit
+func makeQuotas(
+	quotas *ydbv1alpha1.TenantQuotas,
+	dataSize *ydbv1alpha1.DataSizeQuota,
+) (*Ydb_Cms.SchemaOperationQuotas, *Ydb_Cms.DatabaseQuotas) {
+	schemaOperations := &Ydb_Cms.SchemaOperationQuotas{}
+	database := &Ydb_Cms.DatabaseQuotas{}
+	if dataSize != nil {
+		database.DataSizeHardQuota = uint64(dataSize.Hard.Value())
+		if dataSize.Soft != nil {
+			database.DataSizeSoftQuota = uint64(dataSize.Soft.Value())
+		}
+	}
+	if quotas == nil {
+		return schemaOperations, database
+	}
+	for _, quota := range quotas.SchemaOperations {
+		schemaOperations.LeakyBucketQuotas = append(schemaOperations.LeakyBucketQuotas, &Ydb_Cms.SchemaOperationQuotas_LeakyBucket{
+			BucketSize:    float64(quota.Operations),
+			BucketSeconds: uint64(quota.Period.Seconds()),
+		})
+	}
+	if quotas.DataStreamShards != nil {
+		database.DataStreamShardsQuota = uint64(*quotas.DataStreamShards)
+	}
+	return schemaOperations, database
+}
+
+// encodeAlterAttributes encodes the map<string, string> alter_attributes
+// field by hand, each entry being a message of key (1) and value (2)
+func encodeAlterAttributes(attributes map[string]string) protoreflect.RawFields {
+	var raw []byte
+	for _, key := range sortedKeys(attributes) {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, key)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, attributes[key])
+
+		raw = protowire.AppendTag(raw, alterAttributesFieldNumber, protowire.BytesType)
+		raw = protowire.AppendBytes(raw, entry)
+	}
+	return raw
 }
 
 func (t *Tenant) makeCreateDatabaseRequest() *Ydb_Cms.CreateDatabaseRequest {
-	request := &Ydb_Cms.CreateDatabaseRequest{Path: t.Path}
+	request := &Ydb_Cms.CreateDatabaseRequest{
+		OperationParams: &Ydb_Operations.OperationParams{
+			OperationMode: Ydb_Operations.OperationParams_ASYNC,
+		},
+		Path:           t.Path,
+		IdempotencyKey: t.IdempotencyKey,
+	}
+	for _, name := range sortedKeys(t.Attributes) {
+		request.Attributes = append(request.Attributes, &Ydb_Cms.Attribute{Name: name, Value: t.Attributes[name]})
+	}
+	if t.Quotas != nil || t.DataSizeQuota != nil {
+		request.SchemaOperationQuotas, request.DatabaseQuotas = makeQuotas(t.Quotas, t.DataSizeQuota)
+	}
+	if t.PlanResolution > 0 {
+		request.Options = &Ydb_Cms.DatabaseOptions{PlanResolution: t.PlanResolution}
+	}
 	if t.SharedDatabasePath != "" {
 		request.ResourcesKind = &Ydb_Cms.CreateDatabaseRequest_ServerlessResources{
 			ServerlessResources: &Ydb_Cms.ServerlessResources{
@@ -84,17 +390,53 @@ func (t *Tenant) makeCreateDatabaseRequest() *Ydb_Cms.CreateDatabaseRequest {
 	return request
 }
 
-func processDatabaseCreationResponse(response *Ydb_Cms.CreateDatabaseResponse) (bool, error) {
-	if response.Operation == nil {
+// RequiredStorageUnits returns the storage units the tenant was created or
+// altered with, by unit kind
+func RequiredStorageUnits(status *Ydb_Cms.GetDatabaseStatusResult) map[string]uint64 {
+	resources := status.GetRequiredResources()
+	if resources == nil {
+		resources = status.GetRequiredSharedResources()
+	}
+	units := map[string]uint64{}
+	for _, unit := range resources.GetStorageUnits() {
+		units[unit.UnitKind] += unit.Count
+	}
+	return units
+}
+
+// ResourcesKind returns the kind of the resources the tenant was created
+// with, one of the v1alpha1 ResourcesKind constants
+func ResourcesKind(status *Ydb_Cms.GetDatabaseStatusResult) string {
+	switch {
+	case status.GetServerlessResources() != nil:
+		return ydbv1alpha1.ResourcesKindServerless
+	case status.GetRequiredSharedResources() != nil:
+		return ydbv1alpha1.ResourcesKindShared
+	default:
+		return ydbv1alpha1.ResourcesKindDedicated
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func processDatabaseCreationResponse(operation *Ydb_Operations.Operation) (bool, error) {
+	if operation == nil {
 		return false, ErrEmptyReplyFromStorage
 	}
 
-	if response.Operation.Status == Ydb.StatusIds_ALREADY_EXISTS || response.Operation.Status == Ydb.StatusIds_SUCCESS {
+	if operation.Status == Ydb.StatusIds_ALREADY_EXISTS || operation.Status == Ydb.StatusIds_SUCCESS {
 		return true, nil
 	}
-	if response.Operation.Status == Ydb.StatusIds_STATUS_CODE_UNSPECIFIED && len(response.Operation.Issues) == 0 {
+	if operation.Status == Ydb.StatusIds_STATUS_CODE_UNSPECIFIED && len(operation.Issues) == 0 {
 		return true, nil
 	}
 
-	return false, fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	return false, fmt.Errorf("YDB response error: %v %v", operation.Status, operation.Issues)
 }
@@ -0,0 +1,88 @@
+package cms
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+const vdiskInfoPath = "/viewer/json/vdiskinfo"
+
+// VDiskStateOK is the state of a VDisk serving its group
+const VDiskStateOK = "OK"
+
+type vdiskInfoResponse struct {
+	VDiskStateInfo []struct {
+		VDiskId struct {
+			GroupID uint32 `json:"GroupID"`
+			Ring    uint32 `json:"Ring"`
+			Domain  uint32 `json:"Domain"`
+			VDisk   uint32 `json:"VDisk"`
+		} `json:"VDiskId"`
+		NodeId     uint32 `json:"NodeId"`
+		VDiskState string `json:"VDiskState"`
+		Replicated bool   `json:"Replicated"`
+	} `json:"VDiskStateInfo"`
+}
+
+// GroupVDisk is a VDisk of a group as reported by the whiteboard of the
+// node running it
+type GroupVDisk struct {
+	Ring       int
+	FailDomain int
+	VDisk      int
+	State      string
+	Replicated bool
+}
+
+// NodeVDisks returns the number of VDisks running on the static node, as
+// reported by its whiteboard
+func NodeVDisks(ctx context.Context, endpoint string, nodeID uint32) (int32, error) {
+	query := url.Values{"node_id": {strconv.FormatUint(uint64(nodeID), 10)}}
+	body, err := viewerGet(ctx, endpoint, vdiskInfoPath, query)
+	if err != nil {
+		return 0, err
+	}
+
+	info := vdiskInfoResponse{}
+	if err = json.Unmarshal(body, &info); err != nil {
+		return 0, err
+	}
+	var vdisks int32
+	for _, vdisk := range info.VDiskStateInfo {
+		if vdisk.NodeId == nodeID {
+			vdisks++
+		}
+	}
+	return vdisks, nil
+}
+
+// NodeGroupVDisks returns the VDisks of the group running on the static
+// node, as reported by its whiteboard
+func NodeGroupVDisks(ctx context.Context, endpoint string, nodeID, groupID uint32) ([]GroupVDisk, error) {
+	query := url.Values{"node_id": {strconv.FormatUint(uint64(nodeID), 10)}}
+	body, err := viewerGet(ctx, endpoint, vdiskInfoPath, query)
+	if err != nil {
+		return nil, err
+	}
+
+	info := vdiskInfoResponse{}
+	if err = json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	var vdisks []GroupVDisk
+	for _, vdisk := range info.VDiskStateInfo {
+		if vdisk.NodeId != nodeID || vdisk.VDiskId.GroupID != groupID {
+			continue
+		}
+		vdisks = append(vdisks, GroupVDisk{
+			Ring:       int(vdisk.VDiskId.Ring),
+			FailDomain: int(vdisk.VDiskId.Domain),
+			VDisk:      int(vdisk.VDiskId.VDisk),
+			State:      vdisk.VDiskState,
+			Replicated: vdisk.Replicated,
+		})
+	}
+	return vdisks, nil
+}
@@ -0,0 +1,125 @@
+// Package configexport serves the ydbd configuration rendered for Storage
+// and Database resources, as mounted into their pods, so config-diff tooling
+// can read it without exec'ing into the pods. The configs may contain
+// secrets, so they are served on a listener of their own to the clients
+// allowed by Authorize only.
+//
+//	GET /configs                               lists the available configs
+//	GET /configs/storages/<namespace>/<name>   config of a Storage
+//	GET /configs/databases/<namespace>/<name>  config of a Database
+package configexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+const (
+	Path = "/configs"
+
+	storagesKind  = "storages"
+	databasesKind = "databases"
+)
+
+// Handler serves the configs. The ConfigMaps are read from the API server
+// directly, so the operator does not have to cache every ConfigMap.
+type Handler struct {
+	Reader client.Reader
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, Path), "/")
+	if path == "" {
+		h.serveIndex(req.Context(), w)
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 {
+		http.NotFound(w, req)
+		return
+	}
+	kind, key := parts[0], types.NamespacedName{Namespace: parts[1], Name: parts[2]}
+
+	var owner client.Object
+	switch kind {
+	case storagesKind:
+		owner = &ydbv1alpha1.Storage{}
+	case databasesKind:
+		owner = &ydbv1alpha1.Database{}
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	if err := h.Reader.Get(req.Context(), key, owner); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The rendered config is kept in the ConfigMap named after the resource
+	configMap := &corev1.ConfigMap{}
+	if err := h.Reader.Get(req.Context(), key, configMap); err != nil {
+		writeError(w, err)
+		return
+	}
+	config, ok := configMap.Data[ydbv1alpha1.ConfigFileName]
+	if !ok {
+		http.Error(w, "config is not rendered yet", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("X-Resource-Generation", fmt.Sprintf("%d", owner.GetGeneration()))
+	_, _ = w.Write([]byte(config))
+}
+
+func (h *Handler) serveIndex(ctx context.Context, w http.ResponseWriter) {
+	storages := &ydbv1alpha1.StorageList{}
+	if err := h.Reader.List(ctx, storages); err != nil {
+		writeError(w, err)
+		return
+	}
+	databases := &ydbv1alpha1.DatabaseList{}
+	if err := h.Reader.List(ctx, databases); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	paths := make([]string, 0, len(storages.Items)+len(databases.Items))
+	for _, storage := range storages.Items {
+		paths = append(paths, fmt.Sprintf("%s/%s/%s/%s", Path, storagesKind, storage.Namespace, storage.Name))
+	}
+	for _, database := range databases.Items {
+		// Serverless databases run on the nodes of the shared one
+		if database.Spec.ServerlessResources != nil {
+			continue
+		}
+		paths = append(paths, fmt.Sprintf("%s/%s/%s/%s", Path, databasesKind, database.Namespace, database.Name))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(paths)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if apierrors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
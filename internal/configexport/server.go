@@ -0,0 +1,53 @@
+package configexport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Server serves the handler under Path on a listener of its own, separate
+// from the metrics endpoint. With CertDir set it serves HTTPS with the
+// tls.crt and tls.key found there, so the bearer tokens are not sent in
+// clear text.
+type Server struct {
+	Addr    string
+	CertDir string
+	Handler http.Handler
+}
+
+// Start implements manager.Runnable
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, s.Handler)
+	mux.Handle(Path+"/", s.Handler)
+	server := &http.Server{Addr: s.Addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	var err error
+	if s.CertDir != "" {
+		err = server.ListenAndServeTLS(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	} else {
+		err = server.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the configs
+// are served by every replica
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// interconnectAddressFamily returns the address family of the interconnect,
+// the one of the Database taking precedence over the one of the Storage
+func interconnectAddressFamily(cr *v1alpha1.Storage, crDB *v1alpha1.Database) corev1.IPFamily {
+	if crDB != nil && crDB.Spec.Service.Interconnect.AddressFamily != "" {
+		return crDB.Spec.Service.Interconnect.AddressFamily
+	}
+	return cr.Spec.Service.Interconnect.AddressFamily
+}
+
+// setAddressFamily restricts the DNS resolver of the nodes to the records
+// of the family, so the nodes don't pick an AAAA record on one host and an
+// A record on another when DNS returns both
+func setAddressFamily(config map[string]interface{}, family corev1.IPFamily) {
+	resolver := subsection(config, "dns_resolver_config")
+	resolver["allow_ipv4"] = family == corev1.IPv4Protocol
+	resolver["allow_ipv6"] = family == corev1.IPv6Protocol
+}
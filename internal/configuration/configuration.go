@@ -17,6 +17,11 @@ const (
 	DatabaseEncryptionKeyFile           = "key"
 	DatastreamsIAMServiceAccountKeyPath = "/opt/ydb/secrets/datastreams"
 	DatastreamsIAMServiceAccountKeyFile = "sa_key.json"
+	DatabaseTiersPath                   = "/opt/ydb/secrets/tiers"
+	DatabaseTierAccessKeyFile           = "access_key"
+	DatabaseTierSecretKeyFile           = "secret_key"
+
+	defaultTierScheme = "HTTPS"
 )
 
 func hash(text string) string {
@@ -25,26 +30,43 @@ func hash(text string) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-func generate(cr *v1alpha1.Storage, crDB *v1alpha1.Database) schema.Configuration {
+// NodesPerPod returns the number of static nodes run by every storage pod
+func NodesPerPod(cr *v1alpha1.Storage) int {
+	if cr.Spec.NodesPerPod < 1 {
+		return 1
+	}
+	return int(cr.Spec.NodesPerPod)
+}
+
+func generate(cr *v1alpha1.Storage, crDB *v1alpha1.Database, hostConfigIDs []int) schema.Configuration {
 	var hosts []schema.Host
 
-	for i := 0; i < int(cr.Spec.Nodes); i++ {
+	nodesPerPod := NodesPerPod(cr)
+	for i := 0; i < int(cr.TotalNodes()); i++ {
 		datacenter := "az-1"
 		if cr.Spec.Erasure == v1alpha1.ErasureMirror3DC {
 			datacenter = fmt.Sprintf("az-%d", i%3)
 		}
 
-		hosts = append(hosts, schema.Host{
-			Host:         fmt.Sprintf("%v-%d", cr.GetName(), i),
-			HostConfigID: 1, // TODO
-			NodeID:       i + 1,
-			Port:         v1alpha1.InterconnectPort,
-			WalleLocation: schema.WalleLocation{
-				Body:       12340 + i,
-				DataCenter: datacenter,
-				Rack:       strconv.Itoa(i),
-			},
-		})
+		// Nodes of the same pod share its location, so they end up in the
+		// same fail domain, each with the host config of its own devices
+		for j := 0; j < nodesPerPod; j++ {
+			hostConfigID := 1
+			if j < len(hostConfigIDs) {
+				hostConfigID = hostConfigIDs[j]
+			}
+			hosts = append(hosts, schema.Host{
+				Host:         fmt.Sprintf("%v-%d", cr.GetName(), i),
+				HostConfigID: hostConfigID,
+				NodeID:       i*nodesPerPod + j + 1,
+				Port:         v1alpha1.InterconnectPort + j,
+				WalleLocation: schema.WalleLocation{
+					Body:       12340 + i,
+					DataCenter: datacenter,
+					Rack:       strconv.Itoa(i),
+				},
+			})
+		}
 	}
 
 	var keyConfig *schema.KeyConfig
@@ -61,27 +83,88 @@ func generate(cr *v1alpha1.Storage, crDB *v1alpha1.Database) schema.Configuratio
 		}
 	}
 
+	var tiering *schema.TieringConfig
+	if crDB != nil && crDB.Spec.Tiering != nil {
+		tiering = &schema.TieringConfig{}
+		for _, tier := range crDB.Spec.Tiering.Tiers {
+			scheme := tier.Scheme
+			if scheme == "" {
+				scheme = defaultTierScheme
+			}
+			var evictAfter int64
+			if tier.EvictAfter != nil {
+				evictAfter = int64(tier.EvictAfter.Seconds())
+			}
+			tiering.Tiers = append(tiering.Tiers, schema.Tier{
+				Name: tier.Name,
+				ObjectStorage: schema.ObjectStorage{
+					Endpoint:      tier.Endpoint,
+					Bucket:        tier.Bucket,
+					Scheme:        scheme,
+					Region:        tier.Region,
+					AccessKeyFile: path.Join(DatabaseTiersPath, tier.Name, DatabaseTierAccessKeyFile),
+					SecretKeyFile: path.Join(DatabaseTiersPath, tier.Name, DatabaseTierSecretKeyFile),
+				},
+				EvictAfterSeconds: evictAfter,
+			})
+		}
+	}
+
 	return schema.Configuration{
 		Hosts:     hosts,
 		KeyConfig: keyConfig,
+		Tiering:   tiering,
 	}
 }
 
 func Build(cr *v1alpha1.Storage, crDB *v1alpha1.Database) (map[string]string, error) {
 	crdConfig := make(map[string]interface{})
-	generatedConfig := generate(cr, crDB)
 
 	err := yaml.Unmarshal([]byte(cr.Spec.Configuration), &crdConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	var hostConfigIDs []int
+	if crdConfig["hosts"] == nil {
+		if hostConfigIDs, err = nodeHostConfigs(crdConfig, cr); err != nil {
+			return nil, err
+		}
+	}
+	generatedConfig := generate(cr, crDB, hostConfigIDs)
+
+	if err := addDomains(crdConfig, cr); err != nil {
+		return nil, err
+	}
+	if cr.DiskAccessMode() == v1alpha1.DiskAccessFileBacked {
+		setFileBackedDrives(crdConfig, cr)
+	}
 	if crdConfig["hosts"] == nil {
 		crdConfig["hosts"] = generatedConfig.Hosts
 	}
 	if generatedConfig.KeyConfig != nil {
 		crdConfig["key_config"] = generatedConfig.KeyConfig
 	}
+	if generatedConfig.Tiering != nil {
+		crdConfig["tiering_config"] = generatedConfig.Tiering
+		featureFlags, _ := crdConfig["feature_flags"].(map[string]interface{})
+		if featureFlags == nil {
+			featureFlags = make(map[string]interface{})
+		}
+		if _, ok := featureFlags["enable_tiering_in_column_shard"]; !ok {
+			featureFlags["enable_tiering_in_column_shard"] = true
+		}
+		crdConfig["feature_flags"] = featureFlags
+	}
+	if family := interconnectAddressFamily(cr, crDB); family != "" {
+		setAddressFamily(crdConfig, family)
+	}
+	if crDB != nil && crDB.Spec.QueryService != nil {
+		setQueryServiceLimits(crdConfig, crDB.Spec.QueryService)
+	}
+	if server := grpcServer(cr, crDB); server != nil {
+		setGRPCServer(crdConfig, server)
+	}
 
 	data, err := yaml.Marshal(crdConfig)
 	if err != nil {
@@ -0,0 +1,43 @@
+package configuration
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// setFileBackedDrives points the drives on the Block data stores at their
+// PDisk files with the FileBacked disk access mode. Every path naming the
+// device of a data store of any node of the pod is replaced, the ones of
+// host_configs as well as the static PDisks of blob_storage_config.
+func setFileBackedDrives(config map[string]interface{}, cr *v1alpha1.Storage) {
+	files := make(map[string]string)
+	for node := 0; node < NodesPerPod(cr); node++ {
+		for i, spec := range cr.Spec.DataStore {
+			if spec.VolumeMode != nil && *spec.VolumeMode == corev1.PersistentVolumeBlock {
+				index := cr.DiskIndex(node, i)
+				files[v1alpha1.DiskDevice(index)] = v1alpha1.DiskFile(index)
+			}
+		}
+	}
+	replaceDrivePaths(config, files)
+}
+
+func replaceDrivePaths(node interface{}, files map[string]string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if device, ok := item.(string); ok && key == "path" {
+				if file, found := files[device]; found {
+					value[key] = file
+				}
+				continue
+			}
+			replaceDrivePaths(item, files)
+		}
+	case []interface{}:
+		for _, item := range value {
+			replaceDrivePaths(item, files)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// addDomains appends the additional domains of the Storage missing from
+// domains_config. Each one is a copy of the root domain entry with its own
+// name and the next free domain_id, domains that need other settings are
+// expected to be written in the configuration.
+func addDomains(config map[string]interface{}, cr *v1alpha1.Storage) error {
+	if len(cr.Spec.AdditionalDomains) == 0 {
+		return nil
+	}
+
+	domainsConfig, _ := config["domains_config"].(map[string]interface{})
+	domains, _ := domainsConfig["domain"].([]interface{})
+	rootName := cr.Domains()[0]
+	var root map[string]interface{}
+	existing := map[string]bool{}
+	maxID := 0
+	for _, item := range domains {
+		domain, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := domain["name"].(string)
+		existing[name] = true
+		if name == rootName {
+			root = domain
+		}
+		if id, ok := domain["domain_id"].(int); ok && id > maxID {
+			maxID = id
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("domain %s is not found in domains_config", rootName)
+	}
+
+	for _, name := range cr.Spec.AdditionalDomains {
+		if existing[name] {
+			continue
+		}
+		domain, err := copyMapping(root)
+		if err != nil {
+			return err
+		}
+		domain["name"] = name
+		if _, ok := root["domain_id"]; ok {
+			maxID++
+			domain["domain_id"] = maxID
+		}
+		domains = append(domains, domain)
+	}
+	domainsConfig["domain"] = domains
+	return nil
+}
+
+func copyMapping(domain map[string]interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(domain)
+	if err != nil {
+		return nil, err
+	}
+	copied := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
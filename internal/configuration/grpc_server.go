@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// grpcServer returns the gRPC server settings of the nodes, the ones of the
+// Database replacing the ones of the Storage
+func grpcServer(cr *v1alpha1.Storage, crDB *v1alpha1.Database) *v1alpha1.GRPCServerConfig {
+	if crDB != nil && crDB.Spec.Service.GRPC.Server != nil {
+		return crDB.Spec.Service.GRPC.Server
+	}
+	return cr.Spec.Service.GRPC.Server
+}
+
+// setGRPCServer writes the gRPC server settings into grpc_config, keeping
+// its other settings
+func setGRPCServer(config map[string]interface{}, server *v1alpha1.GRPCServerConfig) {
+	settings := map[string]interface{}{}
+	if server.MaxMessageSize != nil {
+		settings["max_message_size"] = server.MaxMessageSize.Value()
+	}
+	if keepAlive := server.KeepAlive; keepAlive != nil {
+		if keepAlive.Enabled != nil {
+			settings["keep_alive_enable"] = *keepAlive.Enabled
+		}
+		if keepAlive.IdleTimeout != nil {
+			settings["keep_alive_idle_timeout_trigger_sec"] = int64(keepAlive.IdleTimeout.Seconds())
+		}
+		if keepAlive.ProbeInterval != nil {
+			settings["keep_alive_probe_interval_sec"] = int64(keepAlive.ProbeInterval.Seconds())
+		}
+		if keepAlive.MaxProbeCount != nil {
+			settings["keep_alive_max_probe_count"] = *keepAlive.MaxProbeCount
+		}
+	}
+	if len(settings) == 0 {
+		return
+	}
+
+	grpc := subsection(config, "grpc_config")
+	for key, value := range settings {
+		grpc[key] = value
+	}
+}
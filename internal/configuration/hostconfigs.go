@@ -0,0 +1,55 @@
+package configuration
+
+import (
+	"errors"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// nodeHostConfigs derives the host configs of the additional static nodes
+// of a pod from the first host config of the configuration, with the drives
+// pointed at the devices of the node, and returns the host config id of
+// every node within the pod. The derived host configs take the ids after
+// the largest one of the configuration.
+func nodeHostConfigs(config map[string]interface{}, cr *v1alpha1.Storage) ([]int, error) {
+	hostConfigs, _ := config["host_configs"].([]interface{})
+	if len(hostConfigs) == 0 {
+		return nil, nil
+	}
+	first, ok := hostConfigs[0].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid host config")
+	}
+
+	maxID := 0
+	for _, item := range hostConfigs {
+		if hostConfig, ok := item.(map[string]interface{}); ok {
+			if id, ok := hostConfig["host_config_id"].(int); ok && id > maxID {
+				maxID = id
+			}
+		}
+	}
+	firstID, ok := first["host_config_id"].(int)
+	if !ok {
+		return nil, errors.New("host config has no host_config_id")
+	}
+
+	ids := []int{firstID}
+	for node := 1; node < NodesPerPod(cr); node++ {
+		hostConfig, err := copyMapping(first)
+		if err != nil {
+			return nil, err
+		}
+		devices := make(map[string]string)
+		for i := range cr.Spec.DataStore {
+			devices[v1alpha1.DiskDevice(i)] = v1alpha1.DiskDevice(cr.DiskIndex(node, i))
+		}
+		replaceDrivePaths(hostConfig, devices)
+		hostConfig["host_config_id"] = maxID + node
+
+		hostConfigs = append(hostConfigs, hostConfig)
+		ids = append(ids, maxID+node)
+	}
+	config["host_configs"] = hostConfigs
+	return ids, nil
+}
@@ -0,0 +1,59 @@
+package configuration
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+type domainsConfig struct {
+	DomainsConfig struct {
+		Domain []struct {
+			Name             string `yaml:"name"`
+			StoragePoolTypes []struct {
+				Kind string `yaml:"kind"`
+			} `yaml:"storage_pool_types"`
+		} `yaml:"domain"`
+	} `yaml:"domains_config"`
+}
+
+// StoragePoolKinds returns the storage pool kinds configured for the domain
+// in the YDB configuration of the Storage. Additional domains generated by
+// the operator have the kinds of the root domain.
+func StoragePoolKinds(cr *v1alpha1.Storage, domainName string) ([]string, error) {
+	config := domainsConfig{}
+	if err := yaml.Unmarshal([]byte(cr.Spec.Configuration), &config); err != nil {
+		return nil, err
+	}
+
+	domains := cr.Domains()
+	if domainName == "" {
+		domainName = domains[0]
+	}
+
+	for _, name := range []string{domainName, domains[0]} {
+		for _, domain := range config.DomainsConfig.Domain {
+			if domain.Name != name {
+				continue
+			}
+			var kinds []string
+			for _, poolType := range domain.StoragePoolTypes {
+				kinds = append(kinds, poolType.Kind)
+			}
+			return kinds, nil
+		}
+		if !containsDomain(cr.Spec.AdditionalDomains, domainName) {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func containsDomain(domains []string, name string) bool {
+	for _, domain := range domains {
+		if domain == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package configuration
+
+import (
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// setQueryServiceLimits writes the limits set in spec.queryService of the
+// Database into table_service_config, keeping its other settings
+func setQueryServiceLimits(config map[string]interface{}, limits *v1alpha1.QueryServiceConfig) {
+	tableService, _ := config["table_service_config"].(map[string]interface{})
+	if tableService == nil {
+		tableService = make(map[string]interface{})
+	}
+
+	if limits.MaxSessionsPerNode != nil {
+		tableService["sessions_limit_per_node"] = *limits.MaxSessionsPerNode
+	}
+	if limits.QueryMemoryLimit != nil {
+		resourceManager := subsection(tableService, "resource_manager")
+		resourceManager["query_memory_limit"] = limits.QueryMemoryLimit.Value()
+	}
+	if limits.MaxResultRows != nil {
+		queryLimits := subsection(tableService, "query_limits")
+		queryLimits["result_rows_limit"] = *limits.MaxResultRows
+	}
+
+	if len(tableService) > 0 {
+		config["table_service_config"] = tableService
+	}
+}
+
+func subsection(config map[string]interface{}, name string) map[string]interface{} {
+	section, _ := config[name].(map[string]interface{})
+	if section == nil {
+		section = make(map[string]interface{})
+		config[name] = section
+	}
+	return section
+}
@@ -1,6 +1,7 @@
 package schema
 
 type Configuration struct {
-	Hosts     []Host     `yaml:"hosts"`
-	KeyConfig *KeyConfig `yaml:"key_config,omitempty"`
+	Hosts     []Host         `yaml:"hosts"`
+	KeyConfig *KeyConfig     `yaml:"key_config,omitempty"`
+	Tiering   *TieringConfig `yaml:"tiering_config,omitempty"`
 }
@@ -0,0 +1,21 @@
+package schema
+
+type TieringConfig struct {
+	Tiers []Tier `yaml:"tiers"`
+}
+
+type Tier struct {
+	Name          string        `yaml:"name"`
+	ObjectStorage ObjectStorage `yaml:"object_storage"`
+	// Seconds, 0 leaves eviction to the table settings
+	EvictAfterSeconds int64 `yaml:"evict_after_seconds,omitempty"`
+}
+
+type ObjectStorage struct {
+	Endpoint      string `yaml:"endpoint"`
+	Bucket        string `yaml:"bucket"`
+	Scheme        string `yaml:"scheme"`
+	Region        string `yaml:"region,omitempty"`
+	AccessKeyFile string `yaml:"access_key_file"`
+	SecretKeyFile string `yaml:"secret_key_file"`
+}
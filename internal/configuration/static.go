@@ -0,0 +1,194 @@
+package configuration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// StaticGroupID is the id of the static group in the service set
+const StaticGroupID = 0
+
+type staticConfig struct {
+	Hosts             []interface{} `yaml:"hosts"`
+	BlobStorageConfig struct {
+		ServiceSet struct {
+			PDisks []interface{} `yaml:"pdisks"`
+			Groups []struct {
+				GroupID int `yaml:"group_id"`
+				Rings   []struct {
+					FailDomains []struct {
+						VDiskLocations []struct {
+							NodeID int    `yaml:"node_id"`
+							Path   string `yaml:"path"`
+						} `yaml:"vdisk_locations"`
+					} `yaml:"fail_domains"`
+				} `yaml:"rings"`
+			} `yaml:"groups"`
+		} `yaml:"service_set"`
+	} `yaml:"blob_storage_config"`
+}
+
+// StaticGroupLocation is a VDisk location of the static group, the ring,
+// fail domain and VDisk indexes match the VDisk id the nodes report
+type StaticGroupLocation struct {
+	Ring       int
+	FailDomain int
+	VDisk      int
+	NodeID     int
+	Path       string
+}
+
+// StaticGroupNodeIDs returns the ids of the nodes hosting the VDisks of the
+// static group in the YDB configuration of the Storage. Only a disaster
+// recovery moves the static group.
+func StaticGroupNodeIDs(cr *v1alpha1.Storage) ([]int, error) {
+	locations, err := StaticGroupLocations(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, location := range locations {
+		ids = append(ids, location.NodeID)
+	}
+	return ids, nil
+}
+
+// StaticGroupLocations returns the VDisk locations of the static group in
+// the YDB configuration of the Storage
+func StaticGroupLocations(cr *v1alpha1.Storage) ([]StaticGroupLocation, error) {
+	config := staticConfig{}
+	if err := yaml.Unmarshal([]byte(cr.Spec.Configuration), &config); err != nil {
+		return nil, err
+	}
+
+	var locations []StaticGroupLocation
+	for _, group := range config.BlobStorageConfig.ServiceSet.Groups {
+		if group.GroupID != StaticGroupID {
+			continue
+		}
+		for ring := range group.Rings {
+			for domain, failDomain := range group.Rings[ring].FailDomains {
+				for vdisk, location := range failDomain.VDiskLocations {
+					locations = append(locations, StaticGroupLocation{
+						Ring:       ring,
+						FailDomain: domain,
+						VDisk:      vdisk,
+						NodeID:     location.NodeID,
+						Path:       location.Path,
+					})
+				}
+			}
+		}
+	}
+	return locations, nil
+}
+
+// PDisksConfigured reports whether the service set of the YDB configuration
+// lists the static PDisks itself, so the VDisk locations refer to them by id
+// instead of by node and path
+func PDisksConfigured(cr *v1alpha1.Storage) (bool, error) {
+	config := staticConfig{}
+	if err := yaml.Unmarshal([]byte(cr.Spec.Configuration), &config); err != nil {
+		return false, err
+	}
+	return config.BlobStorageConfig.ServiceSet.PDisks != nil, nil
+}
+
+// RelocateStaticGroup moves the VDisks of the static group from the nodes
+// to their replacements in moves and increments the group generation, so
+// the nodes recreate the group at its new locations once restarted with
+// the returned configuration. The rest of the configuration is kept as is.
+func RelocateStaticGroup(configuration string, moves map[int]int) (string, error) {
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal([]byte(configuration), &doc); err != nil {
+		return "", err
+	}
+	if len(doc.Content) == 0 {
+		return "", errors.New("configuration is empty")
+	}
+
+	groups := mappingValue(mappingValue(mappingValue(doc.Content[0], "blob_storage_config"), "service_set"), "groups")
+	if groups == nil {
+		return "", errors.New("configuration has no static group")
+	}
+	for _, group := range groups.Content {
+		id := mappingValue(group, "group_id")
+		if id == nil || id.Value != strconv.Itoa(StaticGroupID) {
+			continue
+		}
+		generation := mappingValue(group, "group_generation")
+		if generation == nil {
+			return "", errors.New("static group has no group_generation")
+		}
+		value, err := strconv.Atoi(generation.Value)
+		if err != nil {
+			return "", fmt.Errorf("invalid static group generation %q", generation.Value)
+		}
+		generation.Value = strconv.Itoa(value + 1)
+
+		for _, ring := range sequence(mappingValue(group, "rings")) {
+			for _, failDomain := range sequence(mappingValue(ring, "fail_domains")) {
+				for _, location := range sequence(mappingValue(failDomain, "vdisk_locations")) {
+					nodeID := mappingValue(location, "node_id")
+					if nodeID == nil {
+						continue
+					}
+					from, err := strconv.Atoi(nodeID.Value)
+					if err != nil {
+						continue
+					}
+					if to, ok := moves[from]; ok {
+						nodeID.Value = strconv.Itoa(to)
+					}
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// HostsConfigured reports whether the YDB configuration of the Storage lists
+// the hosts itself instead of having them generated from spec.nodes
+func HostsConfigured(cr *v1alpha1.Storage) (bool, error) {
+	config := staticConfig{}
+	if err := yaml.Unmarshal([]byte(cr.Spec.Configuration), &config); err != nil {
+		return false, err
+	}
+	return config.Hosts != nil, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func sequence(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	TenantResourcesMatchCondition        = "TenantResourcesMatch"
+	TenantResourcesMatchReasonMatched    = "Matched"
+	TenantResourcesMatchReasonMismatched = "Mismatched"
+)
+
+// adoptTenant takes over a tenant that already exists at the path of the
+// database, e.g. after the operator was reinstalled. The resources of the
+// tenant are compared with the spec and the differences are reported in
+// the TenantResourcesMatch condition. The tenant is initialized either
+// way: missing storage units are added by handleStorageUnits, while extra
+// ones and a different resources kind are kept, as CMS can't change them.
+func (r *Reconciler) adoptTenant(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	tenant *cms.Tenant,
+	status *Ydb_Cms.GetDatabaseStatusResult,
+) (bool, ctrl.Result, error) {
+	var mismatches []string
+	if kind := cms.ResourcesKind(status); kind != database.GetResourcesKind() {
+		mismatches = append(mismatches, fmt.Sprintf("resources kind is %s instead of %s", kind, database.GetResourcesKind()))
+	}
+	if tenant.SharedDatabasePath != "" {
+		if path := status.GetServerlessResources().GetSharedDatabasePath(); path != tenant.SharedDatabasePath {
+			mismatches = append(mismatches, fmt.Sprintf("shared database is %q instead of %q", path, tenant.SharedDatabasePath))
+		}
+	} else {
+		desired := map[string]uint64{}
+		for _, unit := range tenant.StorageUnits {
+			desired[unit.UnitKind] += unit.Count
+		}
+		current := cms.RequiredStorageUnits(status)
+		for _, kind := range sortedUnitKinds(desired) {
+			if current[kind] != desired[kind] {
+				mismatches = append(mismatches, fmt.Sprintf("%s storage units: %d instead of %d", kind, current[kind], desired[kind]))
+			}
+		}
+		for _, kind := range sortedUnitKinds(current) {
+			if _, ok := desired[kind]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s storage units: %d instead of 0", kind, current[kind]))
+			}
+		}
+	}
+
+	message := fmt.Sprintf("Tenant %s already exists", tenant.Path)
+	condition := metav1.Condition{
+		Type:    TenantResourcesMatchCondition,
+		Status:  "True",
+		Reason:  TenantResourcesMatchReasonMatched,
+		Message: "Resources of the adopted tenant match the spec",
+	}
+	eventType := corev1.EventTypeNormal
+	if len(mismatches) > 0 {
+		message = fmt.Sprintf("%s, its %s", message, strings.Join(mismatches, ", "))
+		condition.Status = "False"
+		condition.Reason = TenantResourcesMatchReasonMismatched
+		condition.Message = fmt.Sprintf("Adopted tenant differs from the spec: %s", strings.Join(mismatches, ", "))
+		eventType = corev1.EventTypeWarning
+	}
+	meta.SetStatusCondition(&database.Status.Conditions, condition)
+	r.Recorder.Event(database, eventType, events.ReasonTenantAdopted, message)
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionTenantAdopted,
+		resources.HistoryOutcomeSucceeded,
+		database.Generation,
+		message,
+		time.Now(),
+	)
+	return r.setTenantInitialized(ctx, database)
+}
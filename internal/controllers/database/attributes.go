@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleTenantAttributes pushes spec.attributes and the labels and
+// annotations selected by spec.tenantAttributes into the tenant user
+// attributes. The attributes set
+// last are kept in status, so attributes whose source is gone are removed.
+func (r *Reconciler) handleTenantAttributes(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	desired := database.GetTenantAttributes()
+	changes := map[string]string{}
+	for key, value := range desired {
+		if current, ok := database.Status.TenantAttributes[key]; !ok || current != value {
+			changes[key] = value
+		}
+	}
+	for key := range database.Status.TenantAttributes {
+		if _, ok := desired[key]; !ok {
+			changes[key] = ""
+		}
+	}
+	if len(changes) == 0 {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleTenantAttributes")
+
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+
+	if stop, result := r.acquireCMSWindow(database); stop {
+		return stop, result, nil
+	}
+	err := tenant.AlterAttributes(ctx, changes)
+	r.releaseCMSWindow(database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to update attributes of tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonTenantAttributesSynced,
+		fmt.Sprintf("Updated %d attributes of tenant %s", len(changes), tenant.Path),
+	)
+
+	database.Status.TenantAttributes = desired
+	if len(desired) == 0 {
+		database.Status.TenantAttributes = nil
+	}
+	return r.setState(ctx, database)
+}
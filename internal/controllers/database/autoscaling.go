@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/monitoring"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	StorageAutoscalingCheckInterval = 5 * time.Minute
+	// New storage groups take a while to be allocated and show up in
+	// the reported limit, so don't add units again right away
+	StorageAutoscalingCooldown = 15 * time.Minute
+)
+
+func (r *Reconciler) storageAutoscalingEnabled(database *resources.DatabaseBuilder) bool {
+	// Usage is read from the database nodes, a stopped database has none
+	return database.Spec.StorageAutoscaling != nil && database.Spec.StorageAutoscaling.Enabled &&
+		database.Spec.OperationalState != ydbv1alpha1.OperationalStateStopped &&
+		r.Settings.Get().FeatureEnabled(operatorconfig.FeatureStorageAutoscaling)
+}
+
+func (r *Reconciler) handleStorageAutoscaling(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	if !r.storageAutoscalingEnabled(database) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleStorageAutoscaling")
+
+	autoscaling := database.Spec.StorageAutoscaling
+	status := database.Status.StorageAutoscaling
+	if status != nil && time.Since(status.LastScaleTime.Time) < StorageAutoscalingCooldown {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	var specUnits []ydbv1alpha1.StorageUnit
+	if database.Spec.Resources != nil {
+		specUnits = database.Spec.Resources.StorageUnits
+	} else if database.Spec.SharedResources != nil {
+		specUnits = database.Spec.SharedResources.StorageUnits
+	}
+	mappedUnits, err := database.GetStorageUnits()
+	if err != nil {
+		r.Log.Error(err, "failed to map storage units for autoscaling")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	index := -1
+	for i, unit := range specUnits {
+		if unit.UnitKind == autoscaling.UnitKind {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseStorageAutoscalingFailed,
+			fmt.Sprintf("Storage unit kind %s is not used by the database", autoscaling.UnitKind),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	var addedUnits uint64
+	if status != nil {
+		addedUnits = status.AddedUnits
+	}
+	currentUnits := specUnits[index].Count + addedUnits
+
+	usage, err := monitoring.GetTenantStorageUsage(ctx, database.GetStatusEndpoint(), database.GetPath())
+	if err != nil {
+		r.Log.Error(err, "failed to get tenant storage usage")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if usage.Limit == 0 {
+		r.Log.Info("tenant storage limit is unknown, skipping autoscaling")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if usage.Percent() < autoscaling.UsageThresholdPercent {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	if currentUnits >= autoscaling.MaxUnits {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseStorageAutoscalingLimitReached,
+			fmt.Sprintf(
+				"Storage usage %d%% exceeds %d%%, but %s units are already at the limit of %d",
+				usage.Percent(),
+				autoscaling.UsageThresholdPercent,
+				autoscaling.UnitKind,
+				autoscaling.MaxUnits,
+			),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	step := autoscaling.Step
+	if currentUnits+step > autoscaling.MaxUnits {
+		step = autoscaling.MaxUnits - currentUnits
+	}
+
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+	if stop, result := r.acquireCMSWindow(database); stop {
+		return stop, result, nil
+	}
+	err = tenant.AddStorageUnits(ctx, []ydbv1alpha1.StorageUnit{{
+		UnitKind: mappedUnits[index].UnitKind,
+		Count:    step,
+	}})
+	r.releaseCMSWindow(database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseStorageAutoscalingFailed,
+			fmt.Sprintf("Error adding storage units to tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseStorageAutoscaled,
+		fmt.Sprintf(
+			"Storage usage %d%% exceeds %d%%, added %d %s units, %d in total",
+			usage.Percent(),
+			autoscaling.UsageThresholdPercent,
+			step,
+			autoscaling.UnitKind,
+			currentUnits+step,
+		),
+	)
+	database.Status.StorageAutoscaling = &ydbv1alpha1.StorageAutoscalingStatus{
+		AddedUnits:    addedUnits + step,
+		LastScaleTime: metav1.Now(),
+	}
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionStorageUnitsAdded,
+		resources.HistoryOutcomeSucceeded,
+		database.Generation,
+		fmt.Sprintf("Added %d %s units, %d in total", step, autoscaling.UnitKind, currentUnits+step),
+		time.Now(),
+	)
+	return r.setState(ctx, database)
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleAutoUpdate moves a Ready database to the latest patch release of its
+// version published in the channel of the auto-update policy, but never past
+// the version of the Storage it runs on, so the storage nodes are always
+// updated first. The new image is rolled by the regular StatefulSet update.
+func (r *Reconciler) handleAutoUpdate(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	policy := database.Spec.AutoUpdate
+	settings := r.Settings.Get()
+	if policy == nil || !policy.Enabled || settings.VersionManifestURL == "" || r.Versions == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleAutoUpdate")
+
+	if database.Status.State != string(Ready) || !autoupdate.InWindow(policy.MaintenanceWindow, time.Now()) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	versions, err := r.Versions.Versions(ctx, settings.VersionManifestURL, settings.VersionManifestRefreshInterval, policy.Channel)
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonAutoUpdateFailed, fmt.Sprintf("Failed to get released versions: %s", err))
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	current := autoupdate.ImageTag(database.Spec.Image.Name)
+	next, ok := autoupdate.NextPatch(current, versions, autoupdate.ImageTag(database.Storage.Spec.Image.Name))
+	if !ok {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	databaseCr := &ydbv1alpha1.Database{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(database), databaseCr); err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to get Database: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	patch := client.MergeFrom(databaseCr.DeepCopy())
+	databaseCr.Spec.Image.Name = autoupdate.WithTag(databaseCr.Spec.Image.Name, next)
+	if databaseCr.Spec.YDBVersion != "" {
+		databaseCr.Spec.YDBVersion = next
+	}
+	if err := r.Patch(ctx, databaseCr, patch); err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonAutoUpdateFailed, fmt.Sprintf("Failed to update image to %s: %s", next, err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	msg := fmt.Sprintf("Version %s updated to %s", current, next)
+	r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonAutoUpdated, msg)
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionAutoUpdated,
+		resources.HistoryOutcomeSucceeded,
+		databaseCr.Generation,
+		msg,
+		time.Now(),
+	)
+	return r.setState(ctx, database)
+}
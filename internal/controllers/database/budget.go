@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+type step struct {
+	name string
+	run  func(context.Context, *resources.DatabaseBuilder) (bool, ctrl.Result, error)
+}
+
+// tenantSteps are the steps run against the tenant once it is initialized.
+// Most of them talk to CMS, a reconcile running out of its budget is
+// continued from one of them in a new queue item.
+func (r *Reconciler) tenantSteps() []step {
+	return []step{
+		{"handleResourcesMigration", r.handleResourcesMigration},
+		{"handleTenantAttributes", r.handleTenantAttributes},
+		{"handleTenantQuotas", r.handleTenantQuotas},
+		{"handleStorageUnits", r.handleStorageUnits},
+		{"handleStorageAutoscaling", r.handleStorageAutoscaling},
+		{"handleInitScripts", r.handleInitScripts},
+		{"handleReadReplicas", r.handleReadReplicas},
+		{"handleMonitoringUser", r.handleMonitoringUser},
+		{"handleHealthCheck", r.handleHealthCheck},
+		{"handleUpgrade", r.handleUpgrade},
+		{"handleResourceUsage", r.handleResourceUsage},
+		{"handleAutoUpdate", r.handleAutoUpdate},
+	}
+}
+
+// runTenantSteps runs the tenant steps, starting at the one deferred by the
+// previous reconcile. When the reconcile has been running for longer than
+// the reconcile budget the remaining steps are deferred: the database is
+// requeued and the worker is freed for the others, so a few databases with
+// slow CMS endpoints do not starve the fleet. At least one step runs on
+// every reconcile, the steps always move forward.
+func (r *Reconciler) runTenantSteps(ctx context.Context, database *resources.DatabaseBuilder, start time.Time) (bool, ctrl.Result, error) {
+	key := types.NamespacedName{Namespace: database.Namespace, Name: database.Name}
+	steps := r.tenantSteps()
+	first := 0
+	if deferred, ok := r.deferredSteps.LoadAndDelete(key); ok {
+		for i, s := range steps {
+			if s.name == deferred.(string) {
+				first = i
+			}
+		}
+	}
+	budget := r.Settings.Get().ReconcileBudget
+
+	for i, s := range steps[first:] {
+		if i > 0 && budget > 0 && time.Since(start) > budget {
+			r.Log.Info("reconcile budget exhausted, deferring the remaining steps", "budget", budget, "step", s.name)
+			r.deferredSteps.Store(key, s.name)
+			result, err := r.checkStalled(ctx, database, "", ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil)
+			return Stop, result, err
+		}
+		stop, result, err := s.run(ctx, database)
+		if stop {
+			result, err = r.checkStalled(ctx, database, s.name, result, err)
+			return Stop, result, err
+		}
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// Conditions of the stages of a reconcile, so it can be told at which one a
+// Database is stuck. TenantInitialized and PodsReady are set by their steps.
+const (
+	StorageReadyCondition             = "StorageReady"
+	StorageReadyReasonReady           = "Ready"
+	StorageReadyReasonNotFound        = "NotFound"
+	StorageReadyReasonNotReady        = "NotReady"
+	StorageReadyReasonDomainNotServed = "DomainNotServed"
+	StorageReadyReasonExternal        = "External"
+	StorageReadyReasonCANotFound      = "CANotFound"
+
+	ResourcesSyncedCondition        = "ResourcesSynced"
+	ResourcesSyncedReasonSynced     = "Synced"
+	ResourcesSyncedReasonSyncFailed = "SyncFailed"
+
+	StatefulSetReadyCondition         = "StatefulSetReady"
+	StatefulSetReadyReasonReady       = "Ready"
+	StatefulSetReadyReasonNotFound    = "NotFound"
+	StatefulSetReadyReasonProgressing = "Progressing"
+)
+
+// setCondition sets the condition and reports whether its status, reason or
+// message changed
+func setCondition(database *resources.DatabaseBuilder, condition metav1.Condition) bool {
+	current := meta.FindStatusCondition(database.Status.Conditions, condition.Type)
+	if current != nil && current.Status == condition.Status &&
+		current.Reason == condition.Reason && current.Message == condition.Message {
+		return false
+	}
+	condition.ObservedGeneration = database.Generation
+	meta.SetStatusCondition(&database.Status.Conditions, condition)
+	return true
+}
+
+// recordFailedCondition saves the condition of a failing step, the step
+// returns its own result
+func (r *Reconciler) recordFailedCondition(ctx context.Context, database *resources.DatabaseBuilder, condition metav1.Condition) {
+	condition.Status = metav1.ConditionFalse
+	if !setCondition(database, condition) {
+		return
+	}
+	if _, _, err := r.setState(ctx, database); err != nil {
+		r.Log.Error(err, "failed to update condition", "condition", condition.Type)
+	}
+}
+
+// recordObservedGeneration marks the generation as observed once a
+// reconcile has gone through every step
+func (r *Reconciler) recordObservedGeneration(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Status.ObservedGeneration == database.Generation {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step recordObservedGeneration")
+
+	database.Status.ObservedGeneration = database.Generation
+	return r.setState(ctx, database)
+}
+
+func resourcesSyncedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ResourcesSyncedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  ResourcesSyncedReasonSynced,
+		Message: "Child resources are in sync with the spec",
+	}
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// How often the version of a Ready database is asked again, it changes
+// when the pods are updated to a new image
+const VersionCheckInterval = 10 * time.Minute
+
+// handleConnectionInfo publishes the endpoints, the tenant path and the
+// version of the database in status, so clients don't have to derive them
+// from the naming conventions of the operator
+func (r *Reconciler) handleConnectionInfo(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleConnectionInfo")
+
+	changed := false
+	if endpoints := database.GetEndpoints(); !reflect.DeepEqual(database.Status.Endpoints, endpoints) {
+		database.Status.Endpoints = endpoints
+		changed = true
+	}
+	if path := database.GetPath(); database.Status.DatabasePath != path {
+		database.Status.DatabasePath = path
+		changed = true
+	}
+
+	key := client.ObjectKeyFromObject(database)
+	lastCheck, checked := r.versionChecks.Load(key)
+	if database.Status.State == string(Ready) &&
+		(database.Status.Version == "" || !checked || time.Since(lastCheck.(time.Time)) > VersionCheckInterval) {
+		r.versionChecks.Store(key, time.Now())
+		version, err := cms.NodeVersion(ctx, database.GetStatusEndpoint())
+		if err != nil {
+			// The version is informational, it is asked again on the next check
+			r.Log.Error(err, "failed to get database version")
+		} else if version != database.Status.Version {
+			database.Status.Version = version
+			changed = true
+		}
+	}
+
+	if !changed {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	return r.setState(ctx, database)
+}
@@ -2,21 +2,34 @@ package database
 
 import (
 	"context"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
 // Reconciler reconciles a Database object
@@ -26,6 +39,28 @@ type Reconciler struct {
 	Config   *rest.Config
 	Recorder record.EventRecorder
 	Log      logr.Logger
+
+	// Settings are the operator-wide settings, reloaded from OperatorConfig
+	Settings *operatorconfig.Store
+
+	// Versions provides the releases rolled by auto-update policies. Nil
+	// disables auto-updates.
+	Versions *autoupdate.Source
+
+	// CMSQueue rate-limits tenant operations per Storage. Nil disables it.
+	CMSQueue *cms.OperationQueue
+
+	childDeletions *resources.ChildDeletions
+
+	// Time the version of each database was last asked
+	versionChecks sync.Map
+
+	// Time the self-check of each database was last asked
+	healthChecks sync.Map
+
+	// Tenant step each database resumes at after running out of the
+	// reconcile budget
+	deferredSteps sync.Map
 }
 
 //+kubebuilder:rbac:groups=ydb.tech,resources=databases,verbs=get;list;watch;create;update;patch;delete
@@ -34,9 +69,17 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=secrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=secrets/finalizers,verbs=get;list;watch
@@ -51,12 +94,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("database resources not found")
+			operatormetrics.Forget(operatormetrics.KindDatabase, req.Namespace, req.Name)
+			r.childDeletions.Forget(req.NamespacedName)
+			r.versionChecks.Delete(req.NamespacedName)
+			r.healthChecks.Delete(req.NamespacedName)
+			r.deferredSteps.Delete(req.NamespacedName)
 			return ctrl.Result{Requeue: false}, nil
 		}
 		r.Log.Error(err, "unexpected Get error")
-		return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		return ctrl.Result{Requeue: true}, err
 	}
+	start := time.Now()
 	result, err := r.Sync(ctx, database)
+	operatormetrics.ObserveReconcile(operatormetrics.KindDatabase, req.Namespace, req.Name, time.Since(start), err)
 	if err != nil {
 		r.Log.Error(err, "unexpected Sync error")
 	}
@@ -69,7 +119,31 @@ func ignoreDeletionPredicate() predicate.Predicate {
 			// Ignore updates to CR status in which case metadata.Generation does not change
 			_, isService := e.ObjectOld.(*corev1.Service)
 
-			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || isService
+			// Labels and annotations may be copied into the tenant attributes
+			_, isDatabase := e.ObjectOld.(*ydbv1alpha1.Database)
+			metadataChanged := isDatabase &&
+				(!reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+					!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()))
+
+			// Deleting a Database with a finalizer is an update
+			deleted := e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+
+			// Databases wait for their Storage, and serverless ones for their
+			// shared Database, to become Ready
+			stateChanged := false
+			if oldStorage, ok := e.ObjectOld.(*ydbv1alpha1.Storage); ok {
+				if newStorage, ok := e.ObjectNew.(*ydbv1alpha1.Storage); ok {
+					stateChanged = oldStorage.Status.State != newStorage.Status.State
+				}
+			}
+			if oldDatabase, ok := e.ObjectOld.(*ydbv1alpha1.Database); ok {
+				if newDatabase, ok := e.ObjectNew.(*ydbv1alpha1.Database); ok {
+					stateChanged = oldDatabase.Status.State != newDatabase.Status.State
+				}
+			}
+
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				isService || metadataChanged || deleted || stateChanged
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// Evaluates to false if the object has been confirmed deleted.
@@ -80,10 +154,35 @@ func ignoreDeletionPredicate() predicate.Predicate {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.childDeletions = resources.NewChildDeletions()
+	deleted := builder.WithPredicates(r.childDeletions.Predicate("Database"))
+
+	indexer := mgr.GetFieldIndexer()
+	err := indexer.IndexField(context.Background(), &ydbv1alpha1.Database{}, storageRefIndex, indexStorageRef)
+	if err != nil {
+		return err
+	}
+	err = indexer.IndexField(context.Background(), &ydbv1alpha1.Database{}, sharedDatabaseRefIndex, indexSharedDatabaseRef)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ydbv1alpha1.Database{}).
-		Owns(&corev1.Service{}).
-		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}, deleted).
+		Owns(&corev1.ConfigMap{}, deleted).
+		Owns(&appsv1.StatefulSet{}, deleted).
+		Owns(&appsv1.Deployment{}, deleted).
+		Owns(&autoscalingv1.HorizontalPodAutoscaler{}, deleted).
+		Watches(
+			&source.Kind{Type: &ydbv1alpha1.Storage{}},
+			handler.EnqueueRequestsFromMapFunc(r.databasesForStorage),
+		).
+		Watches(
+			&source.Kind{Type: &ydbv1alpha1.Database{}},
+			handler.EnqueueRequestsFromMapFunc(r.serverlessDatabasesForShared),
+		).
 		WithEventFilter(ignoreDeletionPredicate()).
+		WithOptions(controller.Options{RateLimiter: r.Settings.Get().RequeueRateLimiter()}).
 		Complete(r)
 }
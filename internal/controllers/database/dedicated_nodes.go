@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleDedicatedNodes labels and taints the nodes matched by
+// spec.dedicatedNodes.nodeSelector, and releases the nodes that were
+// dedicated to the database earlier but no longer match, all of them when
+// the feature is turned off. Nodes of a deleted Database are released by
+// handleDeletion.
+func (r *Reconciler) handleDedicatedNodes(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if !r.Settings.Get().FeatureEnabled(operatorconfig.FeatureDedicatedNodes) {
+		if err := r.releaseDedicatedNodes(ctx, database, nil); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDedicatedNodes")
+
+	dedicatedValue := labels.DedicatedDatabaseValue(database.Unwrap())
+
+	selected := map[string]bool{}
+	if database.Spec.DedicatedNodes != nil {
+		nodes := &corev1.NodeList{}
+		err := r.List(ctx, nodes, client.MatchingLabels(database.Spec.DedicatedNodes.NodeSelector))
+		if err != nil {
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list nodes: %s", err))
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			selected[node.Name] = true
+
+			if owner, ok := node.Labels[labels.DedicatedDatabaseKey]; ok && owner != dedicatedValue {
+				r.Recorder.Event(
+					database,
+					corev1.EventTypeWarning,
+					events.ReasonDatabaseNodeConflict,
+					fmt.Sprintf("Node %s is already dedicated to database %s", node.Name, owner),
+				)
+				continue
+			}
+
+			if err := r.dedicateNode(ctx, node, dedicatedValue, database.Spec.DedicatedNodes.TaintEffect); err != nil {
+				r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to dedicate node %s: %s", node.Name, err))
+				return Stop, ctrl.Result{Requeue: true}, err
+			}
+		}
+	}
+
+	if err := r.releaseDedicatedNodes(ctx, database, selected); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// releaseDedicatedNodes releases the nodes dedicated to the database except
+// the kept ones
+func (r *Reconciler) releaseDedicatedNodes(ctx context.Context, database *resources.DatabaseBuilder, keep map[string]bool) error {
+	dedicated := &corev1.NodeList{}
+	err := r.List(ctx, dedicated, client.MatchingLabels{labels.DedicatedDatabaseKey: labels.DedicatedDatabaseValue(database.Unwrap())})
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list nodes: %s", err))
+		return err
+	}
+	for i := range dedicated.Items {
+		node := &dedicated.Items[i]
+		if keep[node.Name] {
+			continue
+		}
+		if err := r.releaseNode(ctx, node); err != nil {
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to release node %s: %s", node.Name, err))
+			return err
+		}
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonDatabaseNodeReleased, fmt.Sprintf("Node %s is no longer dedicated", node.Name))
+	}
+	return nil
+}
+
+func (r *Reconciler) dedicateNode(ctx context.Context, node *corev1.Node, value string, effect corev1.TaintEffect) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	changed := false
+
+	if node.Labels[labels.DedicatedDatabaseKey] != value {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[labels.DedicatedDatabaseKey] = value
+		changed = true
+	}
+
+	taint := corev1.Taint{Key: labels.DedicatedDatabaseKey, Value: value, Effect: effect}
+	found := false
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints)+1)
+	for _, t := range node.Spec.Taints {
+		if t.Key != labels.DedicatedDatabaseKey {
+			taints = append(taints, t)
+			continue
+		}
+		if t.Value == taint.Value && t.Effect == taint.Effect && !found {
+			taints = append(taints, t)
+			found = true
+			continue
+		}
+		changed = true
+	}
+	if !found {
+		taints = append(taints, taint)
+		changed = true
+	}
+	node.Spec.Taints = taints
+
+	if !changed {
+		return nil
+	}
+	return r.Patch(ctx, node, patch)
+}
+
+func (r *Reconciler) releaseNode(ctx context.Context, node *corev1.Node) error {
+	patch := client.MergeFrom(node.DeepCopy())
+
+	delete(node.Labels, labels.DedicatedDatabaseKey)
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+	for _, t := range node.Spec.Taints {
+		if t.Key != labels.DedicatedDatabaseKey {
+			taints = append(taints, t)
+		}
+	}
+	node.Spec.Taints = taints
+
+	return r.Patch(ctx, node, patch)
+}
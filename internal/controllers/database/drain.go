@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	NodeDrainRequeueDelay = 15 * time.Second
+	// Tablets may have nowhere to move, e.g. when the remaining nodes are
+	// out of resources, the pods are removed anyway after the timeout
+	NodeDrainTimeout = 30 * time.Minute
+)
+
+// handleNodeDrain moves the tablets off the nodes of the pods removed by a
+// decreased spec.nodes before the StatefulSet is scaled down. While the drain
+// runs, Replicas keeps the pods and status.drain tracks the nodes. Stopping
+// the database and Deployment workloads don't drain, as there is no
+// predictable set of pods to keep.
+func (r *Reconciler) handleNodeDrain(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Spec.Workload == ydbv1alpha1.WorkloadDeployment ||
+		database.Spec.OperationalState == ydbv1alpha1.OperationalStateStopped ||
+		!meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		if database.Status.Drain != nil {
+			database.Status.Drain = nil
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleNodeDrain")
+
+	drain := database.Status.Drain
+	if drain == nil {
+		return r.startNodeDrain(ctx, database)
+	}
+	if database.Spec.Nodes >= drain.Replicas {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonDatabaseNodesDrained,
+			fmt.Sprintf("Drain cancelled, spec.nodes is back to %d", database.Spec.Nodes),
+		)
+		database.Status.Drain = nil
+		return r.setState(ctx, database)
+	}
+	if database.Spec.Nodes != drain.TargetReplicas {
+		// Start over with the new set of pods, nodes drained so far are
+		// requested again
+		database.Status.Drain = nil
+		return r.setState(ctx, database)
+	}
+
+	if time.Since(drain.StartTime.Time) > NodeDrainTimeout {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseNodeDrainFailed,
+			fmt.Sprintf("Nodes are not drained in %s, scaling down to %d anyway", NodeDrainTimeout, drain.TargetReplicas),
+		)
+		return r.finishNodeDrain(ctx, database, resources.HistoryOutcomeFailed)
+	}
+
+	storageSecure := database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled
+	hive := ""
+	done := true
+	changed := false
+	for i := range drain.Nodes {
+		node := &drain.Nodes[i]
+		if node.Drained {
+			continue
+		}
+
+		if node.NodeID == 0 {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: node.Pod, Namespace: database.Namespace}}
+			id, err := healthcheck.GetNodeID(
+				ctx,
+				database.GetStorageEndpoint(),
+				storageSecure,
+				database.StorageCA,
+				database.GetPath(),
+				database.GetNodePublicHost(pod),
+			)
+			if errors.Is(err, healthcheck.ErrNodeNotRegistered) {
+				// The node is down, it has no tablets to move
+				node.Drained = true
+				changed = true
+				continue
+			}
+			if err != nil {
+				r.Log.Error(err, "failed to get node id", "pod", node.Pod)
+				done = false
+				continue
+			}
+			node.NodeID = id
+			changed = true
+		}
+
+		if !node.Requested {
+			if hive == "" {
+				// Tablets of a tenant with its own Hive are not moved by
+				// the root one
+				id, err := cms.TenantHive(ctx, database.GetStatusEndpoint(), database.GetPath())
+				if err != nil {
+					r.Log.Error(err, "failed to get tenant hive")
+					done = false
+					continue
+				}
+				hive = id
+			}
+			if err := cms.DrainNode(ctx, database.GetStatusEndpoint(), hive, node.NodeID); err != nil {
+				r.Recorder.Event(
+					database,
+					corev1.EventTypeWarning,
+					events.ReasonDatabaseNodeDrainFailed,
+					fmt.Sprintf("Failed to drain node %d of pod %s: %s", node.NodeID, node.Pod, err),
+				)
+				done = false
+				continue
+			}
+			node.Requested = true
+			changed = true
+		}
+
+		tablets, err := cms.NodeTablets(ctx, database.GetStatusEndpoint(), node.NodeID)
+		if err != nil {
+			r.Log.Error(err, "failed to get node tablets", "pod", node.Pod)
+			done = false
+			continue
+		}
+		if tablets != node.Tablets {
+			node.Tablets = tablets
+			changed = true
+		}
+		if tablets > 0 {
+			done = false
+			continue
+		}
+		node.Drained = true
+		changed = true
+	}
+
+	if done {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonDatabaseNodesDrained,
+			fmt.Sprintf("Nodes are drained, scaling down to %d", drain.TargetReplicas),
+		)
+		return r.finishNodeDrain(ctx, database, resources.HistoryOutcomeSucceeded)
+	}
+	if changed {
+		if _, _, err := r.setState(ctx, database); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+	return Stop, ctrl.Result{RequeueAfter: NodeDrainRequeueDelay}, nil
+}
+
+// startNodeDrain records the pods above spec.nodes in status.drain when the
+// StatefulSet runs more of them
+func (r *Reconciler) startNodeDrain(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKey{Name: database.Name, Namespace: database.Namespace}, statefulSet)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return Continue, ctrl.Result{Requeue: false}, nil
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get StatefulSet: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	current := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		current = *statefulSet.Spec.Replicas
+	}
+	if current <= database.Spec.Nodes {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	drain := &ydbv1alpha1.NodeDrainStatus{
+		Replicas:       current,
+		TargetReplicas: database.Spec.Nodes,
+		StartTime:      metav1.Now(),
+	}
+	var pods []string
+	for i := database.Spec.Nodes; i < current; i++ {
+		pod := fmt.Sprintf("%s-%d", database.Name, i)
+		drain.Nodes = append(drain.Nodes, ydbv1alpha1.DrainedNode{Pod: pod})
+		pods = append(pods, pod)
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseNodesDraining,
+		fmt.Sprintf("Draining nodes of pods %s before scaling down to %d", strings.Join(pods, ", "), database.Spec.Nodes),
+	)
+	database.Status.Drain = drain
+	return r.setState(ctx, database)
+}
+
+// finishNodeDrain scales the StatefulSet down to the target replicas and
+// clears status.drain. The StatefulSet is patched here, as the resources
+// sync may be skipped until the resync period, and startNodeDrain would
+// find the old replicas and start over. The sync is forced anyway to
+// render the rest of the objects for the new spec.nodes.
+func (r *Reconciler) finishNodeDrain(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	outcome string,
+) (bool, ctrl.Result, error) {
+	drain := database.Status.Drain
+
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKey{Name: database.Name, Namespace: database.Namespace}, statefulSet)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get StatefulSet: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if err == nil && (statefulSet.Spec.Replicas == nil || *statefulSet.Spec.Replicas != drain.TargetReplicas) {
+		patch := client.MergeFrom(statefulSet.DeepCopy())
+		replicas := drain.TargetReplicas
+		statefulSet.Spec.Replicas = &replicas
+		if err := r.Patch(ctx, statefulSet, patch); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonControllerError,
+				fmt.Sprintf("Failed to scale StatefulSet down to %d: %s", drain.TargetReplicas, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionNodesDrained,
+		outcome,
+		database.Generation,
+		fmt.Sprintf("Drained %d nodes, %d -> %d pods", len(drain.Nodes), drain.Replicas, drain.TargetReplicas),
+		time.Now(),
+	)
+	database.Status.Drain = nil
+	return r.setState(ctx, database)
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// waitForExternalStorage is waitForClusterResources of a database running
+// on an external storage cluster. The cluster is not watched, its state is
+// only known from the CMS calls, so it is considered ready once its CA is
+// read and it serves the domain of the database.
+func (r *Reconciler) waitForExternalStorage(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	endpoint := database.Spec.ExternalStorageEndpoint.Endpoint
+	if err := r.useExternalStorage(ctx, database); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseWaitingForStorage,
+			fmt.Sprintf("Failed to get the CA of external storage %s: %s", endpoint, err),
+		)
+		r.recordFailedCondition(ctx, database, metav1.Condition{
+			Type:    StorageReadyCondition,
+			Reason:  StorageReadyReasonCANotFound,
+			Message: fmt.Sprintf("CA of external storage %s is not available: %s", endpoint, err),
+		})
+		return Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	domain := database.Spec.Domain
+	if domain == "" {
+		domain = ydbv1alpha1.DefaultDatabaseDomain
+	}
+	if !containsString(database.Storage.Domains(), domain) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseSpecInvalid,
+			fmt.Sprintf("Domain %s is not the domain %s of external storage %s", domain, database.Storage.Domains()[0], endpoint),
+		)
+		r.recordFailedCondition(ctx, database, metav1.Condition{
+			Type:    StorageReadyCondition,
+			Reason:  StorageReadyReasonDomainNotServed,
+			Message: fmt.Sprintf("Domain %s is not served by external storage %s", domain, endpoint),
+		})
+		return Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	if setCondition(database, metav1.Condition{
+		Type:    StorageReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  StorageReadyReasonExternal,
+		Message: fmt.Sprintf("External storage %s is used", endpoint),
+	}) {
+		return r.setState(ctx, database)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// useExternalStorage stands the external storage cluster in for the
+// Storage of the database, with the CA of its endpoint read from the Secret
+func (r *Reconciler) useExternalStorage(ctx context.Context, database *resources.DatabaseBuilder) error {
+	database.Storage = resources.ExternalStorage(database.Database)
+
+	ca := database.Spec.ExternalStorageEndpoint.CA()
+	if ca == nil {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ca.Name, Namespace: database.Namespace}, secret); err != nil {
+		return err
+	}
+	data, ok := secret.Data[ca.Key]
+	if !ok {
+		return fmt.Errorf("key %s is not found in secret %s", ca.Key, ca.Name)
+	}
+	database.StorageCA = data
+	return nil
+}
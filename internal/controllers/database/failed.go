@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	SpecValidCondition                = "SpecValid"
+	SpecValidReasonValid              = "Valid"
+	SpecValidReasonIncorrectResources = "IncorrectResourcesConfiguration"
+)
+
+// validateSpec catches misconfigurations no amount of retrying can fix.
+// The database is put into the terminal Failed state and is not requeued
+// until its spec changes, then the state is reset and the reconcile goes on.
+func (r *Reconciler) validateSpec(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step validateSpec")
+
+	current := meta.FindStatusCondition(database.Status.Conditions, SpecValidCondition)
+	if database.Status.State == string(Failed) && current != nil &&
+		current.Status == metav1.ConditionFalse && current.ObservedGeneration == database.Generation {
+		r.Log.Info("spec is invalid, waiting for it to change")
+		return Stop, ctrl.Result{Requeue: false}, nil
+	}
+
+	if reason, err := checkSpec(database); err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseSpecInvalid, err.Error())
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               SpecValidCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            err.Error(),
+			ObservedGeneration: database.Generation,
+		})
+		database.Status.State = string(Failed)
+		if _, _, err := r.setState(ctx, database); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return Stop, ctrl.Result{Requeue: false}, nil
+	}
+
+	if current == nil || current.Status != metav1.ConditionTrue {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               SpecValidCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             SpecValidReasonValid,
+			Message:            "Spec is valid",
+			ObservedGeneration: database.Generation,
+		})
+		if database.Status.State == string(Failed) {
+			database.Status.State = string(Pending)
+		}
+		return r.setState(ctx, database)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// checkSpec returns the condition reason and the error for an unrecoverable
+// misconfiguration of the spec
+func checkSpec(database *resources.DatabaseBuilder) (string, error) {
+	configured := 0
+	if database.Spec.Resources != nil {
+		configured++
+	}
+	if database.Spec.SharedResources != nil {
+		configured++
+	}
+	if database.Spec.ServerlessResources != nil {
+		configured++
+	}
+	if configured != 1 {
+		return SpecValidReasonIncorrectResources, ErrIncorrectDatabaseResourcesConfiguration
+	}
+	return "", nil
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	TenantRemovedCondition        = "TenantRemoved"
+	TenantRemovedReasonInProgress = "InProgress"
+	TenantRemovedReasonFailed     = "Failed"
+
+	TenantRemovalRequeueDelay = 30 * time.Second
+)
+
+// handleFinalizer adds the finalizer that removes the tenant from CMS once
+// the Database is deleted
+func (r *Reconciler) handleFinalizer(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if controllerutil.ContainsFinalizer(database, ydbv1alpha1.TenantRemovalFinalizer) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleFinalizer")
+
+	controllerutil.AddFinalizer(database.Unwrap(), ydbv1alpha1.TenantRemovalFinalizer)
+	if err := r.Update(ctx, database.Unwrap()); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to add finalizer: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
+
+// handleDeletion removes the tenant of a deleted Database from CMS, retrying
+// until it succeeds, and then releases the finalizer. Databases whose tenant
+// was never initialized and Databases whose Storage is gone have nothing to
+// remove. The Retain deletion policy keeps the tenant in CMS. The nodes
+// dedicated to the database are released before the finalizer.
+func (r *Reconciler) handleDeletion(ctx context.Context, database *resources.DatabaseBuilder) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(database, ydbv1alpha1.TenantRemovalFinalizer) {
+		return ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDeletion")
+
+	initialized := meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition)
+	if initialized && database.Spec.DeletionPolicy == ydbv1alpha1.DeletionPolicyRetain {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonTenantRetained,
+			fmt.Sprintf("Tenant %s is kept in CMS by the Retain deletion policy", database.GetPath()),
+		)
+	} else if initialized {
+		stop, result, err := r.removeTenant(ctx, database)
+		if stop {
+			return result, err
+		}
+	}
+
+	if err := r.releaseDedicatedNodes(ctx, database, nil); err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	controllerutil.RemoveFinalizer(database.Unwrap(), ydbv1alpha1.TenantRemovalFinalizer)
+	if err := r.Update(ctx, database.Unwrap()); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to remove finalizer: %s", err),
+		)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) removeTenant(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Spec.ExternalStorageEndpoint != nil {
+		if err := r.useExternalStorage(ctx, database); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonTenantRemovalFailed,
+				fmt.Sprintf("Failed to get the CA of external storage %s: %s", database.Spec.ExternalStorageEndpoint.Endpoint, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return r.removeTenantFromCMS(ctx, database)
+	}
+	if database.Spec.StorageClusterRef == nil {
+		// The tenant was never created without a storage cluster
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	storage := &ydbv1alpha1.Storage{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      database.Spec.StorageClusterRef.Name,
+		Namespace: database.Spec.StorageClusterRef.Namespace,
+	}, storage)
+	if apierrors.IsNotFound(err) || (err == nil && storage.DeletionTimestamp != nil) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonTenantRemoved,
+			fmt.Sprintf(
+				"Storage %s/%s is deleted, the tenant is removed with it",
+				database.Spec.StorageClusterRef.Namespace,
+				database.Spec.StorageClusterRef.Name,
+			),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantRemovalFailed,
+			fmt.Sprintf("Failed to get Storage %s/%s: %s", database.Spec.StorageClusterRef.Namespace, database.Spec.StorageClusterRef.Name, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	database.Storage = storage
+	return r.removeTenantFromCMS(ctx, database)
+}
+
+func (r *Reconciler) removeTenantFromCMS(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if meta.FindStatusCondition(database.Status.Conditions, TenantRemovedCondition) == nil {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    TenantRemovedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  TenantRemovedReasonInProgress,
+			Message: "Removing the tenant from CMS",
+		})
+		return r.setState(ctx, database)
+	}
+
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+	if stop, result := r.acquireCMSWindow(database); stop {
+		return stop, result, nil
+	}
+	err := chaos.Inject(ctx, database, chaos.CMSRemoveDatabase)
+	if err == nil {
+		err = tenant.Remove(ctx)
+	}
+	r.releaseCMSWindow(database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantRemovalFailed,
+			fmt.Sprintf("Error removing tenant %s: %s", tenant.Path, err),
+		)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    TenantRemovedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  TenantRemovedReasonFailed,
+			Message: fmt.Sprintf("Failed to remove the tenant from CMS, retrying: %s", err),
+		})
+		if _, _, statusErr := r.setState(ctx, database); statusErr != nil {
+			return Stop, ctrl.Result{Requeue: true}, statusErr
+		}
+		return Stop, ctrl.Result{RequeueAfter: TenantRemovalRequeueDelay}, nil
+	}
+
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonTenantRemoved,
+		fmt.Sprintf("Tenant %s removed", tenant.Path),
+	)
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleGarbageCollection deletes the child ConfigMaps, Deployments, Services
+// and HorizontalPodAutoscalers the database no longer uses, e.g. the ones of
+// the disabled proxy or spec.uiAuth, and the Jobs that finished longer than
+// the configured TTL ago
+func (r *Reconciler) handleGarbageCollection(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	garbage, err := resources.Garbage(
+		ctx,
+		r.Client,
+		database,
+		database.GetResourceBuilders(),
+		[]client.ObjectList{
+			&appsv1.DeploymentList{},
+			&corev1.ServiceList{},
+			&autoscalingv1.HorizontalPodAutoscalerList{},
+		},
+		r.Settings.Get().ConfigRevisionHistory,
+		r.Settings.Get().FinishedJobTTL,
+		time.Now(),
+	)
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list child resources: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if len(garbage) == 0 {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleGarbageCollection")
+
+	for _, obj := range garbage {
+		err := r.Delete(ctx, obj, client.PropagationPolicy("Background"))
+		if err != nil && !apierrors.IsNotFound(err) {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonControllerError,
+				fmt.Sprintf("Failed to delete %T %s: %s", obj, obj.GetName(), err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonResourceDeleted,
+			fmt.Sprintf("Deleted unused %T %s", obj, obj.GetName()),
+		)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
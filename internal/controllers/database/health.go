@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	// How often the self-check of a running database is asked
+	HealthCheckInterval = 1 * time.Minute
+
+	DegradedCondition                 = "Degraded"
+	DegradedReasonGood                = "Good"
+	DegradedReasonDegraded            = "Degraded"
+	DegradedReasonMaintenanceRequired = "MaintenanceRequired"
+	DegradedReasonEmergency           = "Emergency"
+)
+
+var degradedReasons = map[Ydb_Monitoring.SelfCheck_Result]string{
+	Ydb_Monitoring.SelfCheck_GOOD:                 DegradedReasonGood,
+	Ydb_Monitoring.SelfCheck_DEGRADED:             DegradedReasonDegraded,
+	Ydb_Monitoring.SelfCheck_MAINTENANCE_REQUIRED: DegradedReasonMaintenanceRequired,
+	Ydb_Monitoring.SelfCheck_EMERGENCY:            DegradedReasonEmergency,
+}
+
+// handleHealthCheck polls the YDB self-check of a running database and
+// keeps the Degraded condition in sync with it. Pods being ready is not
+// enough for the Ready state: a database with any self-check result other
+// than GOOD is Degraded until YDB reports it GOOD again.
+func (r *Reconciler) handleHealthCheck(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Status.State != string(Ready) && database.Status.State != string(Degraded) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	key := client.ObjectKeyFromObject(database)
+	if lastCheck, checked := r.healthChecks.Load(key); checked && time.Since(lastCheck.(time.Time)) < HealthCheckInterval {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleHealthCheck")
+	r.healthChecks.Store(key, time.Now())
+
+	result, err := healthcheck.GetDatabaseSelfCheckResult(ctx, database)
+	if err != nil {
+		// The condition keeps the last known result, it is asked again on
+		// the next check
+		r.Log.Error(err, "failed to get database self-check result")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	reason, known := degradedReasons[result]
+	if !known {
+		r.Log.Info("unknown database self-check result", "result", result.String())
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:    DegradedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: fmt.Sprintf("YDB self-check reports %s", result),
+	}
+	if result == Ydb_Monitoring.SelfCheck_GOOD {
+		condition.Status = metav1.ConditionFalse
+	}
+	current := meta.FindStatusCondition(database.Status.Conditions, DegradedCondition)
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	meta.SetStatusCondition(&database.Status.Conditions, condition)
+
+	state := readyState(database)
+	if database.Status.State != string(state) {
+		eventType, eventReason := corev1.EventTypeWarning, events.ReasonDatabaseDegraded
+		if state == Ready {
+			eventType, eventReason = corev1.EventTypeNormal, events.ReasonDatabaseRecovered
+		}
+		r.Recorder.Event(database, eventType, eventReason, condition.Message)
+		database.Status.State = string(state)
+	}
+	return r.setState(ctx, database)
+}
+
+// readyState returns the state of a database whose pods are ready and whose
+// tenant is initialized, Degraded while its self-check is not GOOD
+func readyState(database *resources.DatabaseBuilder) ClusterState {
+	if meta.IsStatusConditionTrue(database.Status.Conditions, DegradedCondition) {
+		return Degraded
+	}
+	return Ready
+}
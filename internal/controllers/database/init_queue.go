@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	QueuedForInitializationCondition     = "QueuedForInitialization"
+	QueuedForInitializationReasonQueued  = "LimitReached"
+	QueuedForInitializationReasonStarted = "Started"
+)
+
+// waitForInitializationSlot keeps the database out of Initializing while
+// MaxConcurrentTenantInitializations other tenants of the same Storage are being
+// initialized. A database that is already Initializing keeps its slot.
+func (r *Reconciler) waitForInitializationSlot(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step waitForInitializationSlot")
+
+	limit := r.Settings.Get().MaxConcurrentTenantInitializations
+	if limit <= 0 || database.Status.State == string(Initializing) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	databases := &ydbv1alpha1.DatabaseList{}
+	if err := r.List(ctx, databases); err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list databases: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	initializing := 0
+	for _, other := range databases.Items {
+		if other.Namespace == database.Namespace && other.Name == database.Name {
+			continue
+		}
+		if storageKey(&other) != storageKey(database.Database) {
+			continue
+		}
+		if other.Status.State == string(Initializing) {
+			initializing++
+		}
+	}
+
+	current := meta.FindStatusCondition(database.Status.Conditions, QueuedForInitializationCondition)
+	if initializing >= limit {
+		msg := fmt.Sprintf(
+			"%d tenants of %s are initializing, limit is %d",
+			initializing,
+			storageName(database.Database),
+			limit,
+		)
+		if current == nil || current.Status != metav1.ConditionTrue || current.Message != msg {
+			r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonTenantQueued, msg)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    QueuedForInitializationCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  QueuedForInitializationReasonQueued,
+				Message: msg,
+			})
+			if _, _, err := r.setState(ctx, database); err != nil {
+				return Stop, ctrl.Result{Requeue: true}, err
+			}
+		}
+		return Stop, ctrl.Result{RequeueAfter: TenantCreationRequeueDelay}, nil
+	}
+
+	if current != nil && current.Status == metav1.ConditionTrue {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    QueuedForInitializationCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  QueuedForInitializationReasonStarted,
+			Message: "Initialization slot acquired",
+		})
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
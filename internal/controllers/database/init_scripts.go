@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// InitScriptRetryDelay is the least time between runs of a failed script
+// with the same text
+const InitScriptRetryDelay = 1 * time.Minute
+
+// handleInitScripts runs the scripts of spec.initScripts whose text differs
+// from the one last run successfully, one script per reconcile and in the
+// order of the list. A failed script holds back the ones after it and is
+// retried after InitScriptRetryDelay, or at once when its text changes.
+// ConfigMap sources are read on every reconcile, so edits of them are
+// picked up with the periodic requeue.
+func (r *Reconciler) handleInitScripts(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	if statuses, removed := pruneInitScriptStatuses(database); removed {
+		database.Status.InitScripts = statuses
+		return r.setState(ctx, database)
+	}
+
+	for _, script := range database.Spec.InitScripts {
+		text, err := r.getInitScriptText(ctx, database, script)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonDatabaseInitScriptFailed,
+				fmt.Sprintf("Failed to read init script %s: %s", script.Name, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		checksum := initScriptChecksum(text)
+
+		current := findInitScriptStatus(database.Status.InitScripts, script.Name)
+		if current != nil && current.Checksum == checksum {
+			if current.State == ydbv1alpha1.InitScriptSucceeded {
+				continue
+			}
+			if time.Since(current.LastRunTime.Time) < InitScriptRetryDelay {
+				return Continue, ctrl.Result{Requeue: false}, nil
+			}
+		}
+		r.Log.Info("running step handleInitScripts")
+
+		endpoint, secure := database.GetQueryEndpoint()
+		client := scripting.Client{
+			Endpoint:             endpoint,
+			UseGrpcSecureChannel: secure,
+			Database:             database.GetPath(),
+		}
+		status := ydbv1alpha1.InitScriptStatus{
+			Name:        script.Name,
+			Checksum:    checksum,
+			State:       ydbv1alpha1.InitScriptSucceeded,
+			LastRunTime: metav1.Now(),
+		}
+		if err := client.Execute(ctx, text); err != nil {
+			status.State = ydbv1alpha1.InitScriptFailed
+			status.Message = err.Error()
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonDatabaseInitScriptFailed,
+				fmt.Sprintf("Init script %s failed: %s", script.Name, err),
+			)
+		} else {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeNormal,
+				events.ReasonDatabaseInitScriptApplied,
+				fmt.Sprintf("Init script %s applied", script.Name),
+			)
+		}
+		database.Status.InitScripts = setInitScriptStatus(database.Status.InitScripts, status)
+		return r.setState(ctx, database)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) getInitScriptText(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	script ydbv1alpha1.InitScript,
+) (string, error) {
+	if script.ConfigMapRef == nil {
+		return script.Script, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      script.ConfigMapRef.Name,
+		Namespace: database.Namespace,
+	}, configMap)
+	if err != nil {
+		return "", err
+	}
+	text, ok := configMap.Data[script.ConfigMapRef.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in ConfigMap %s", script.ConfigMapRef.Key, configMap.Name)
+	}
+	return text, nil
+}
+
+// pruneInitScriptStatuses drops the statuses of scripts removed from spec
+func pruneInitScriptStatuses(database *resources.DatabaseBuilder) ([]ydbv1alpha1.InitScriptStatus, bool) {
+	names := map[string]bool{}
+	for _, script := range database.Spec.InitScripts {
+		names[script.Name] = true
+	}
+	var statuses []ydbv1alpha1.InitScriptStatus
+	for _, status := range database.Status.InitScripts {
+		if names[status.Name] {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, len(statuses) != len(database.Status.InitScripts)
+}
+
+func findInitScriptStatus(statuses []ydbv1alpha1.InitScriptStatus, name string) *ydbv1alpha1.InitScriptStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func setInitScriptStatus(
+	statuses []ydbv1alpha1.InitScriptStatus,
+	status ydbv1alpha1.InitScriptStatus,
+) []ydbv1alpha1.InitScriptStatus {
+	if current := findInitScriptStatus(statuses, status.Name); current != nil {
+		*current = status
+		return statuses
+	}
+	return append(statuses, status)
+}
+
+func initScriptChecksum(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
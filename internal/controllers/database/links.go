@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/dashboards"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleLinks publishes the links to the embedded UI and the dashboards of
+// the database in status and annotations, so kubectl describe shows them
+func (r *Reconciler) handleLinks(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleLinks")
+
+	settings := r.Settings.Get()
+	links, err := dashboards.Render(settings.UIURLTemplate, settings.GrafanaURLTemplate, dashboards.Target{
+		Kind:      "Database",
+		Namespace: database.Namespace,
+		Name:      database.Name,
+		Path:      database.GetPath(),
+	})
+	if err != nil {
+		// A broken template must not block the reconcile
+		r.Log.Error(err, "failed to render links")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	if dashboards.Annotate(resources.CopyDict(database.Annotations), links) {
+		databaseCr := &ydbv1alpha1.Database{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(database), databaseCr); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		patch := client.MergeFrom(databaseCr.DeepCopy())
+		if databaseCr.Annotations == nil {
+			databaseCr.Annotations = map[string]string{}
+		}
+		dashboards.Annotate(databaseCr.Annotations, links)
+		if err := r.Patch(ctx, databaseCr, patch); err != nil {
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to update link annotations: %s", err))
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	if dashboards.Equal(database.Status.Links, links) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	database.Status.Links = links
+	return r.setState(ctx, database)
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	MigrationInProgressCondition = "MigrationInProgress"
+	MigrationReasonInProgress    = "InProgress"
+	MigrationReasonBlocked       = "Blocked"
+	MigrationReasonCompleted     = "Completed"
+)
+
+// handleResourcesMigration completes a switch of the database between
+// dedicated and shared resources. By the time it runs the compute nodes
+// already use the new resources, what is left is to alter the tenant in CMS
+// to allocate the storage units of the new spec it does not have yet. CMS
+// keeps the resources kind the tenant was created with, it is recorded in
+// status.tenantResourcesKind and the serverless databases check it before
+// running on a shared one. A shared database cannot move to dedicated
+// resources while serverless databases run on it.
+func (r *Reconciler) handleResourcesMigration(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	kind := database.GetResourcesKind()
+	from := database.Status.ResourcesKind
+	if from == kind {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if from == "" {
+		// Databases initialized before the kind was recorded
+		database.Status.ResourcesKind = kind
+		return r.setState(ctx, database)
+	}
+	r.Log.Info("running step handleResourcesMigration")
+
+	if from == ydbv1alpha1.ResourcesKindServerless || kind == ydbv1alpha1.ResourcesKindServerless {
+		return r.setMigrationBlocked(ctx, database, fmt.Sprintf("Migration from %s to %s is not supported", from, kind))
+	}
+	if from == ydbv1alpha1.ResourcesKindShared {
+		dependents, err := r.listServerlessDependents(ctx, database)
+		if err != nil {
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list databases: %s", err))
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		if len(dependents) > 0 {
+			return r.setMigrationBlocked(ctx, database, fmt.Sprintf(
+				"Serverless databases still use the shared resources: %s",
+				strings.Join(dependents, ", "),
+			))
+		}
+	}
+
+	message := fmt.Sprintf("Migrating from %s to %s resources", from, kind)
+	condition := meta.FindStatusCondition(database.Status.Conditions, MigrationInProgressCondition)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != MigrationReasonInProgress {
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonDatabaseResourcesMigrating, message)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    MigrationInProgressCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  MigrationReasonInProgress,
+			Message: message,
+		})
+		return r.setState(ctx, database)
+	}
+
+	units, err := database.GetStorageUnits()
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseResourcesMigrationFailed, fmt.Sprintf("Invalid storage units: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+	status, err := tenant.GetStatus(ctx)
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseResourcesMigrationFailed, fmt.Sprintf("Error checking tenant %s: %s", tenant.Path, err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if missing := missingStorageUnits(units, status.GetAllocatedResources().GetStorageUnits()); len(missing) > 0 {
+		if stop, result := r.acquireCMSWindow(database); stop {
+			return stop, result, nil
+		}
+		err = tenant.AddStorageUnits(ctx, missing)
+		r.releaseCMSWindow(database)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonDatabaseResourcesMigrationFailed,
+				fmt.Sprintf("Error adding storage units to tenant %s: %s", tenant.Path, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	tenantKind := cms.ResourcesKind(status)
+	message = fmt.Sprintf("Migrated from %s to %s resources", from, kind)
+	if tenantKind != kind {
+		message = fmt.Sprintf("%s, the tenant stays registered with %s resources in CMS", message, tenantKind)
+	}
+	r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonDatabaseResourcesMigrated, message)
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:    MigrationInProgressCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  MigrationReasonCompleted,
+		Message: message,
+	})
+	database.Status.ResourcesKind = kind
+	database.Status.TenantResourcesKind = tenantKind
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionResourcesMigrated,
+		resources.HistoryOutcomeSucceeded,
+		database.Generation,
+		message,
+		time.Now(),
+	)
+	return r.setState(ctx, database)
+}
+
+func (r *Reconciler) setMigrationBlocked(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	message string,
+) (bool, ctrl.Result, error) {
+	condition := meta.FindStatusCondition(database.Status.Conditions, MigrationInProgressCondition)
+	if condition != nil && condition.Reason == MigrationReasonBlocked && condition.Message == message {
+		return Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseResourcesMigrationBlocked, message)
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:    MigrationInProgressCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  MigrationReasonBlocked,
+		Message: message,
+	})
+	stop, _, err := r.setState(ctx, database)
+	return stop, ctrl.Result{Requeue: true}, err
+}
+
+// listServerlessDependents returns the serverless databases referencing
+// the database as their shared one
+func (r *Reconciler) listServerlessDependents(ctx context.Context, database *resources.DatabaseBuilder) ([]string, error) {
+	databases := &ydbv1alpha1.DatabaseList{}
+	if err := r.List(ctx, databases); err != nil {
+		return nil, err
+	}
+
+	var dependents []string
+	for _, item := range databases.Items {
+		if item.Spec.ServerlessResources == nil {
+			continue
+		}
+		ref := item.Spec.ServerlessResources.SharedDatabaseRef
+		if ref.Name == database.Name && ref.Namespace == database.Namespace {
+			dependents = append(dependents, fmt.Sprintf("%s/%s", item.Namespace, item.Name))
+		}
+	}
+	return dependents, nil
+}
+
+// missingStorageUnits returns the units of spec not allocated to the tenant
+func missingStorageUnits(spec []ydbv1alpha1.StorageUnit, allocated []*Ydb_Cms.StorageUnits) []ydbv1alpha1.StorageUnit {
+	counts := make(map[string]uint64, len(allocated))
+	for _, unit := range allocated {
+		counts[unit.GetUnitKind()] += unit.GetCount()
+	}
+
+	var missing []ydbv1alpha1.StorageUnit
+	for _, unit := range spec {
+		have := counts[unit.UnitKind]
+		if unit.Count <= have {
+			counts[unit.UnitKind] = have - unit.Count
+			continue
+		}
+		missing = append(missing, ydbv1alpha1.StorageUnit{
+			UnitKind: unit.UnitKind,
+			Count:    unit.Count - have,
+		})
+		counts[unit.UnitKind] = 0
+	}
+	return missing
+}
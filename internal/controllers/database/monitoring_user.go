@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// handleMonitoringUser registers the isolated monitoring credentials as a
+// user of the database, and again whenever the Secret changes, e.g. on a
+// rotation. The user is dropped once the credentials are no longer
+// isolated. A failure is reported and retried on the next reconcile, the
+// steps after it are not held back.
+func (r *Reconciler) handleMonitoringUser(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	isolated := database.Spec.Monitoring != nil && database.Spec.Monitoring.IsolatedCredentials
+	if !isolated && database.Status.MonitoringCredentialsVersion == "" {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	username := resources.MonitoringUsername(database.Name)
+	if !isolated {
+		r.Log.Info("running step handleMonitoringUser")
+		err := monitoringUserClient(database).Execute(ctx, fmt.Sprintf("DROP USER IF EXISTS %s;", username))
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonMonitoringUserFailed,
+				fmt.Sprintf("Failed to drop monitoring user %s: %s", username, err),
+			)
+			return Continue, ctrl.Result{Requeue: false}, nil
+		}
+		database.Status.MonitoringCredentialsVersion = ""
+		return r.setState(ctx, database)
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      resources.MonitoringSecretName(database.Name),
+		Namespace: database.Namespace,
+	}, secret)
+	if apierrors.IsNotFound(err) {
+		// Created by the next resources sync
+		return Continue, ctrl.Result{Requeue: false}, nil
+	} else if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get monitoring Secret: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if secret.ResourceVersion == database.Status.MonitoringCredentialsVersion {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleMonitoringUser")
+
+	username, password, err := resources.MonitoringCredentials(secret)
+	if err == nil {
+		err = monitoringUserClient(database).SetUserPassword(ctx, username, password)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonMonitoringUserFailed,
+			fmt.Sprintf("Failed to register monitoring user %s: %s", username, err),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonMonitoringUserApplied,
+		fmt.Sprintf("Monitoring user %s registered in database %s", username, database.GetPath()),
+	)
+	database.Status.MonitoringCredentialsVersion = secret.ResourceVersion
+	return r.setState(ctx, database)
+}
+
+func monitoringUserClient(database *resources.DatabaseBuilder) *scripting.Client {
+	endpoint, secure := database.GetQueryEndpoint()
+	return &scripting.Client{
+		Endpoint:             endpoint,
+		UseGrpcSecureChannel: secure,
+		Database:             database.GetPath(),
+	}
+}
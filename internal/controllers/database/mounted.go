@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleMountedObjects records in status the ConfigMaps and Secrets the
+// pods of the workload use and the hashes of their data. Secrets are not
+// watched, their changes are picked up with the periodic requeue.
+func (r *Reconciler) handleMountedObjects(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleMountedObjects")
+
+	spec, err := r.getWorkloadPodSpec(ctx, database)
+	if apierrors.IsNotFound(err) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	var mounted []ydbv1alpha1.MountedObject
+	if err == nil {
+		mounted, err = resources.MountedObjects(ctx, r, database.Namespace, spec)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to read the objects mounted into the pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if equality.Semantic.DeepEqual(mounted, database.Status.MountedObjects) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	database.Status.MountedObjects = mounted
+	return r.setState(ctx, database)
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	ActionPendingCondition     = "ActionPending"
+	ActionPendingReasonWaiting = "Waiting"
+	ActionPendingReasonIdle    = "Idle"
+)
+
+// nextActions describe the steps that keep the reconcile waiting. Other
+// steps only stop it to update the status and leave the action as it is.
+var nextActions = map[string]func(database *resources.DatabaseBuilder) string{
+	"handleFinalizer":           func(*resources.DatabaseBuilder) string { return "adding finalizer" },
+	"validateSpec":              func(*resources.DatabaseBuilder) string { return "waiting for a valid spec" },
+	"waitForClusterResources":   func(*resources.DatabaseBuilder) string { return "waiting for storage" },
+	"checkQuotas":               func(*resources.DatabaseBuilder) string { return "waiting for storage quota" },
+	"validateStoragePoolKinds":  func(*resources.DatabaseBuilder) string { return "waiting for valid storage pool kinds" },
+	"handleDedicatedNodes":      func(*resources.DatabaseBuilder) string { return "preparing dedicated nodes" },
+	"handleNodeDrain":           describeNodeDrain,
+	"checkUpgrade":              func(*resources.DatabaseBuilder) string { return "waiting for the storage to be upgraded" },
+	"handleResourcesSync":       func(*resources.DatabaseBuilder) string { return "syncing resources" },
+	"handleCoordinatedRollout":  describeRollout,
+	"waitForStatefulSetToScale": func(*resources.DatabaseBuilder) string { return "waiting for pods to become ready" },
+	"waitForInitializationSlot": func(*resources.DatabaseBuilder) string { return "waiting for an initialization slot" },
+	"handleTenantCreation":      describeTenantCreation,
+	"handleResourcesMigration":  func(*resources.DatabaseBuilder) string { return "migrating resources" },
+	"handleStorageUnits":        func(*resources.DatabaseBuilder) string { return "adding storage units" },
+	"handleUpgrade":             describeUpgrade,
+}
+
+// setNextAction describes what the controller does or waits for after the
+// step in status.nextAction and the ActionPending condition. A completed
+// reconcile has no next action. It reports whether the status changed.
+func (r *Reconciler) setNextAction(database *resources.DatabaseBuilder, step string) bool {
+	action := database.Status.NextAction
+	switch {
+	case step == "":
+		action = ""
+	case nextActions[step] != nil:
+		action = nextActions[step](database)
+	}
+
+	condition := metav1.Condition{
+		Type:    ActionPendingCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  ActionPendingReasonWaiting,
+		Message: action,
+	}
+	if action == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ActionPendingReasonIdle
+		condition.Message = "Nothing to do"
+	}
+	current := meta.FindStatusCondition(database.Status.Conditions, ActionPendingCondition)
+	if action == database.Status.NextAction && current != nil && current.Message == condition.Message {
+		return false
+	}
+	database.Status.NextAction = action
+	meta.SetStatusCondition(&database.Status.Conditions, condition)
+	return true
+}
+
+func describeNodeDrain(database *resources.DatabaseBuilder) string {
+	drain := database.Status.Drain
+	if drain == nil {
+		return "preparing node drain"
+	}
+	drained := 0
+	for _, node := range drain.Nodes {
+		if node.Drained {
+			drained++
+		}
+	}
+	return fmt.Sprintf("draining nodes %d/%d", drained, len(drain.Nodes))
+}
+
+func describeRollout(database *resources.DatabaseBuilder) string {
+	if rollout := database.Status.Rollout; rollout != nil {
+		return fmt.Sprintf("restarting pod %s", rollout.Pod)
+	}
+	return "waiting to restart outdated pods"
+}
+
+func describeUpgrade(database *resources.DatabaseBuilder) string {
+	if upgrade := database.Status.Upgrade; upgrade != nil {
+		return fmt.Sprintf("upgrading to %s, %d/%d pods updated", upgrade.ToImage, upgrade.UpdatedReplicas, upgrade.Replicas)
+	}
+	return "finishing upgrade"
+}
+
+func describeTenantCreation(database *resources.DatabaseBuilder) string {
+	if operation := database.Status.TenantOperation; operation != nil {
+		return fmt.Sprintf("waiting for tenant operation %s", operation.ID)
+	}
+	return "creating tenant"
+}
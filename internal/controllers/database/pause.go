@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	PausedCondition     = "Paused"
+	PausedReasonPaused  = "Paused"
+	PausedReasonResumed = "Resumed"
+)
+
+// handlePause stops the reconcile of a database with spec.pause set, so
+// manual changes to its child resources and tenant are not reverted. The
+// Paused condition tells whether the operator currently holds off.
+func (r *Reconciler) handlePause(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	paused := database.Spec.Pause
+	if paused == meta.IsStatusConditionTrue(database.Status.Conditions, PausedCondition) {
+		if paused {
+			// Unpausing changes the generation and triggers a reconcile
+			return Stop, ctrl.Result{Requeue: false}, nil
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handlePause")
+
+	condition := metav1.Condition{
+		Type:    PausedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  PausedReasonPaused,
+		Message: "Reconciliation is suspended by spec.pause",
+	}
+	reason := events.ReasonDatabasePaused
+	if !paused {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = PausedReasonResumed
+		condition.Message = "Reconciliation is resumed"
+		reason = events.ReasonDatabaseResumed
+	}
+	r.Recorder.Event(database, corev1.EventTypeNormal, reason, condition.Message)
+	meta.SetStatusCondition(&database.Status.Conditions, condition)
+	return r.setState(ctx, database)
+}
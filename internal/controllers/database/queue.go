@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// acquireCMSWindow takes the operation window of the referenced Storage
+// before a CMS call that changes tenants. On success the caller must
+// releaseCMSWindow once the call is done, otherwise the reconcile is
+// requeued for when the window may be free.
+func (r *Reconciler) acquireCMSWindow(database *resources.DatabaseBuilder) (bool, ctrl.Result) {
+	ok, wait := r.CMSQueue.TryAcquire(
+		storageKey(database.Database),
+		databaseKey(database),
+		r.Settings.Get().CMSOperationInterval,
+		time.Now(),
+	)
+	if !ok {
+		r.Log.Info("waiting for CMS operation window", "storage", storageKey(database.Database), "retryAfter", wait)
+		return Stop, ctrl.Result{RequeueAfter: wait}
+	}
+	return Continue, ctrl.Result{Requeue: false}
+}
+
+func (r *Reconciler) releaseCMSWindow(database *resources.DatabaseBuilder) {
+	r.CMSQueue.Release(storageKey(database.Database), databaseKey(database), time.Now())
+}
+
+// storageKey identifies the storage cluster of the database: its Storage,
+// or the endpoint of an external cluster, which has no namespace
+func storageKey(database *ydbv1alpha1.Database) types.NamespacedName {
+	if external := database.Spec.ExternalStorageEndpoint; external != nil {
+		return types.NamespacedName{Name: external.Endpoint}
+	}
+	if database.Spec.StorageClusterRef == nil {
+		return types.NamespacedName{}
+	}
+	namespace := database.Spec.StorageClusterRef.Namespace
+	if namespace == "" {
+		namespace = database.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: database.Spec.StorageClusterRef.Name}
+}
+
+// storageName names the storage cluster of the database in messages
+func storageName(database *ydbv1alpha1.Database) string {
+	if external := database.Spec.ExternalStorageEndpoint; external != nil {
+		return fmt.Sprintf("external storage %s", external.Endpoint)
+	}
+	key := storageKey(database)
+	return fmt.Sprintf("Storage %s/%s", key.Namespace, key.Name)
+}
+
+func databaseKey(database *resources.DatabaseBuilder) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      database.Name,
+		Namespace: database.Namespace,
+	}
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	QuotaExceededCondition          = "QuotaExceeded"
+	QuotaExceededReasonStorage      = "StorageLimitReached"
+	QuotaExceededReasonNamespace    = "NamespaceLimitReached"
+	QuotaExceededReasonWithinLimits = "WithinLimits"
+
+	QuotaExceededRequeueDelay = 60 * time.Second
+)
+
+// checkQuotas holds the database while it is over spec.maxDatabases of its
+// Storage or over the operator limit of databases per namespace. Databases
+// with an initialized tenant always stay within the limits, the others are
+// admitted oldest first, so lowering a limit never stops running databases.
+func (r *Reconciler) checkQuotas(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step checkQuotas")
+
+	storageLimit := int(database.Storage.Spec.MaxDatabases)
+	namespaceLimit := r.Settings.Get().MaxDatabasesPerNamespace
+	current := meta.FindStatusCondition(database.Status.Conditions, QuotaExceededCondition)
+	held := current != nil && current.Status == metav1.ConditionTrue
+	if storageLimit <= 0 && namespaceLimit <= 0 && !held {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		return r.releaseQuotaHold(ctx, database, held)
+	}
+
+	databases := &ydbv1alpha1.DatabaseList{}
+	if err := r.List(ctx, databases); err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list databases: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	sort.Slice(databases.Items, func(i, j int) bool {
+		return admittedBefore(&databases.Items[i], &databases.Items[j])
+	})
+
+	var reason, msg string
+	storagePosition, namespacePosition := 0, 0
+	for i := range databases.Items {
+		other := &databases.Items[i]
+		if other.Namespace == database.Namespace && other.Name == database.Name {
+			break
+		}
+		if storageKey(other) == storageKey(database.Database) {
+			storagePosition++
+		}
+		if other.Namespace == database.Namespace {
+			namespacePosition++
+		}
+	}
+	if storageLimit > 0 && storagePosition >= storageLimit {
+		reason = QuotaExceededReasonStorage
+		msg = fmt.Sprintf(
+			"%s already has %d databases, limit is %d",
+			storageName(database.Database),
+			storagePosition,
+			storageLimit,
+		)
+	} else if namespaceLimit > 0 && namespacePosition >= namespaceLimit {
+		reason = QuotaExceededReasonNamespace
+		msg = fmt.Sprintf(
+			"Namespace %s already has %d databases, limit is %d",
+			database.Namespace,
+			namespacePosition,
+			namespaceLimit,
+		)
+	}
+
+	if reason != "" {
+		if !held || current.Message != msg {
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseQuotaExceeded, msg)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    QuotaExceededCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  reason,
+				Message: msg,
+			})
+			database.Status.State = string(Pending)
+			if _, _, err := r.setState(ctx, database); err != nil {
+				return Stop, ctrl.Result{Requeue: true}, err
+			}
+		}
+		return Stop, ctrl.Result{RequeueAfter: QuotaExceededRequeueDelay}, nil
+	}
+
+	return r.releaseQuotaHold(ctx, database, held)
+}
+
+func (r *Reconciler) releaseQuotaHold(ctx context.Context, database *resources.DatabaseBuilder, held bool) (bool, ctrl.Result, error) {
+	if !held {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:    QuotaExceededCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  QuotaExceededReasonWithinLimits,
+		Message: "Database is within the limits",
+	})
+	return r.setState(ctx, database)
+}
+
+// admittedBefore orders databases by their claim on the quotas: initialized
+// ones first, then by age
+func admittedBefore(a, b *ydbv1alpha1.Database) bool {
+	aInitialized := meta.IsStatusConditionTrue(a.Status.Conditions, TenantInitializedCondition)
+	bInitialized := meta.IsStatusConditionTrue(b.Status.Conditions, TenantInitializedCondition)
+	if aInitialized != bInitialized {
+		return aInitialized
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
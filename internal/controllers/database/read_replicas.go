@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// readReplicasOff is set on the tables removed from spec.readReplicas
+const readReplicasOff = "ANY_AZ:0"
+
+// handleReadReplicas sets READ_REPLICAS_SETTINGS of the tables of
+// spec.readReplicas whose settings differ from the ones set last, in a
+// single script. The settings set last are kept in status, so the
+// followers of tables removed from the spec are turned off. It runs after
+// the init scripts, which usually create the tables.
+func (r *Reconciler) handleReadReplicas(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	desired := map[string]string{}
+	for _, replicas := range database.Spec.ReadReplicas {
+		desired[replicas.Table] = replicas.Setting()
+	}
+	changes := map[string]string{}
+	for table, setting := range desired {
+		if current, ok := database.Status.ReadReplicas[table]; !ok || current != setting {
+			changes[table] = setting
+		}
+	}
+	for table := range database.Status.ReadReplicas {
+		if _, ok := desired[table]; !ok {
+			changes[table] = readReplicasOff
+		}
+	}
+	if len(changes) == 0 {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleReadReplicas")
+
+	endpoint, secure := database.GetQueryEndpoint()
+	client := scripting.Client{
+		Endpoint:             endpoint,
+		UseGrpcSecureChannel: secure,
+		Database:             database.GetPath(),
+	}
+	if err := client.Execute(ctx, readReplicasScript(changes)); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to set read replicas of %d tables: %s", len(changes), err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonTenantReadReplicasSynced,
+		fmt.Sprintf("Set read replicas of %d tables", len(changes)),
+	)
+
+	database.Status.ReadReplicas = desired
+	if len(desired) == 0 {
+		database.Status.ReadReplicas = nil
+	}
+	return r.setState(ctx, database)
+}
+
+// readReplicasScript alters the tables in the order of their paths
+func readReplicasScript(changes map[string]string) string {
+	tables := make([]string, 0, len(changes))
+	for table := range changes {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var script strings.Builder
+	for _, table := range tables {
+		fmt.Fprintf(&script, "ALTER TABLE `%s` SET (READ_REPLICAS_SETTINGS = %s);\n",
+			table, scripting.Quote(changes[table]))
+	}
+	return script.String()
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	NodeReadyReasonContainersNotReady = "ContainersNotReady"
+	NodeReadyReasonNotServing         = "NotServing"
+	NodeReadyReasonNotRegistered      = "NotRegistered"
+	NodeReadyReasonServing            = "Serving"
+	NodeReadyReasonRestarting         = "Restarting"
+
+	NodeCheckTimeout = 5 * time.Second
+)
+
+// handleReadinessGates sets the ydb.tech/node-ready condition of the
+// database pods. A pod passes once its node answers the gRPC health service
+// and is registered in the node broker for the database. Registration is
+// checked only after the tenant is initialized.
+func (r *Reconciler) handleReadinessGates(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if !database.Spec.ReadinessGate {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleReadinessGates")
+
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList,
+		client.InNamespace(database.Namespace),
+		client.MatchingLabels{
+			labels.InstanceKey:  database.Name,
+			labels.ComponentKey: labels.DynamicComponent,
+		},
+	)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to list database pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	secure := database.Spec.Service.GRPC.TLSConfiguration != nil && database.Spec.Service.GRPC.TLSConfiguration.Enabled
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		ready, reason, message := r.checkNode(ctx, database, pod, secure)
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if !resources.SetNodeReadyGate(pod, ready, reason, message) {
+			continue
+		}
+		if err := r.Status().Patch(ctx, pod, patch); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonControllerError,
+				fmt.Sprintf("Failed to update readiness gate of pod %s: %s", pod.Name, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) checkNode(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	pod *corev1.Pod,
+	secure bool,
+) (bool, string, string) {
+	if !resources.ContainersReady(pod) {
+		return false, NodeReadyReasonContainersNotReady, "Containers are not ready"
+	}
+	// The pod is out of the endpoints until the coordinated rollout
+	// deletes it
+	if rollout := database.Status.Rollout; rollout != nil && rollout.PodUID != "" && rollout.PodUID == pod.UID {
+		return false, NodeReadyReasonRestarting, restartingMessage
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, NodeCheckTimeout)
+	defer cancel()
+
+	endpoint := database.GetNodeGRPCEndpoint(pod)
+	if err := healthcheck.CheckNodeHealth(ctx, endpoint, secure); err != nil {
+		return false, NodeReadyReasonNotServing, err.Error()
+	}
+	// Nodes can only register once the tenant exists, which in turn
+	// waits for the pods to become ready
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		return true, NodeReadyReasonServing, "Node is serving, tenant is not initialized yet"
+	}
+	host := database.GetNodePublicHost(pod)
+	if err := healthcheck.CheckNodeRegistered(ctx, endpoint, secure, database.GetPath(), host); err != nil {
+		return false, NodeReadyReasonNotRegistered, err.Error()
+	}
+	return true, NodeReadyReasonServing, "Node is serving and registered in the node broker"
+}
@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	RolloutRequeueDelay = 15 * time.Second
+	// How long CMS expects a restarted node to be down
+	NodeRestartDuration = 10 * time.Minute
+
+	DefaultEndpointDrainDelay = 5 * time.Second
+
+	restartingMessage = "Node is about to restart, it is out of the Service endpoints"
+)
+
+// handleCoordinatedRollout restarts the outdated pods of a StatefulSet with
+// the OnDelete update strategy one at a time, highest ordinal first. Each
+// pod is deleted only after CMS permits the restart of its node, and the
+// next one waits until all the pods are ready again. Before the tenant is
+// initialized the nodes serve nothing and are restarted without asking.
+// With the readiness gate a pod is taken out of the Service endpoints for
+// the endpoint drain delay before it is deleted, and the gate keeps the new
+// pod out until its node is registered, so clients see no errors.
+func (r *Reconciler) handleCoordinatedRollout(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if !database.Spec.CoordinatedRollout || database.Spec.Workload == ydbv1alpha1.WorkloadDeployment {
+		if database.Status.Rollout != nil {
+			database.Status.Rollout = nil
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleCoordinatedRollout")
+
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKey{Name: database.Name, Namespace: database.Namespace}, statefulSet)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return Continue, ctrl.Result{Requeue: false}, nil
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get StatefulSet: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	podList := &corev1.PodList{}
+	err = r.List(ctx, podList,
+		client.InNamespace(database.Namespace),
+		client.MatchingLabels{
+			labels.InstanceKey:  database.Name,
+			labels.ComponentKey: labels.DynamicComponent,
+		},
+	)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to list database pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	revision := statefulSet.Status.UpdateRevision
+	notReady := resources.NotReadyPods(database.Name, database.Replicas(), podList.Items)
+	if rollout := database.Status.Rollout; rollout != nil {
+		if rollout.PodUID != "" {
+			return r.deleteUnpublishedPod(ctx, database, podList.Items)
+		}
+		if len(notReady) > 0 {
+			return Stop, ctrl.Result{RequeueAfter: RolloutRequeueDelay}, nil
+		}
+		if rollout.PermissionID != "" {
+			if err := cms.DonePermission(ctx, database.GetStatusEndpoint(), rollout.PermissionID); err != nil {
+				// The permission expires by itself
+				r.Log.Error(err, "failed to release CMS permission", "pod", rollout.Pod)
+			}
+		}
+		database.Status.Rollout = nil
+		return r.setState(ctx, database)
+	}
+
+	outdated := outdatedPods(podList.Items, revision)
+	if revision == "" || len(outdated) == 0 {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if len(notReady) > 0 {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonDatabaseNodeRestartPostponed,
+			fmt.Sprintf("Waiting for pods to become ready before restarting %s: %s", outdated[0].Name, strings.Join(notReady, ", ")),
+		)
+		return Stop, ctrl.Result{RequeueAfter: RolloutRequeueDelay}, nil
+	}
+
+	pod := outdated[0]
+	permission := cms.RestartPermission{Allowed: true}
+	if meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		permission, err = cms.RequestRestart(ctx, database.GetStatusEndpoint(), database.GetNodePublicHost(pod), NodeRestartDuration)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseNodeRestartFailed,
+			fmt.Sprintf("Failed to request CMS permission to restart pod %s: %s", pod.Name, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: RolloutRequeueDelay}, nil
+	}
+	if !permission.Allowed {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonDatabaseNodeRestartPostponed,
+			fmt.Sprintf("CMS does not allow restarting pod %s yet: %s", pod.Name, permission.Reason),
+		)
+		return Stop, ctrl.Result{RequeueAfter: RolloutRequeueDelay}, nil
+	}
+
+	if database.Spec.ReadinessGate && endpointDrainDelay(database) > 0 {
+		return r.unpublishPod(ctx, database, pod, permission.ID)
+	}
+
+	if err := r.deletePod(ctx, database, pod); err != nil {
+		if permission.ID != "" {
+			if doneErr := cms.DonePermission(ctx, database.GetStatusEndpoint(), permission.ID); doneErr != nil {
+				r.Log.Error(doneErr, "failed to release CMS permission", "pod", pod.Name)
+			}
+		}
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseNodeRestarted,
+		fmt.Sprintf("Restarting pod %s to update it, %d outdated pods left", pod.Name, len(outdated)-1),
+	)
+	database.Status.Rollout = &ydbv1alpha1.CoordinatedRolloutStatus{
+		Pod:          pod.Name,
+		PermissionID: permission.ID,
+		StartTime:    metav1.Now(),
+	}
+	return r.setState(ctx, database)
+}
+
+// unpublishPod sets the readiness gate of the pod to false, which takes it
+// out of the Service endpoints. The pod is deleted by
+// deleteUnpublishedPod once the endpoint drain delay has passed.
+func (r *Reconciler) unpublishPod(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	pod *corev1.Pod,
+	permissionID string,
+) (bool, ctrl.Result, error) {
+	patch := client.MergeFrom(pod.DeepCopy())
+	if resources.SetNodeReadyGate(pod, false, NodeReadyReasonRestarting, restartingMessage) {
+		if err := r.Status().Patch(ctx, pod, patch); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonDatabaseNodeRestartFailed,
+				fmt.Sprintf("Failed to take pod %s out of the Service endpoints: %s", pod.Name, err),
+			)
+			if permissionID != "" {
+				if doneErr := cms.DonePermission(ctx, database.GetStatusEndpoint(), permissionID); doneErr != nil {
+					r.Log.Error(doneErr, "failed to release CMS permission", "pod", pod.Name)
+				}
+			}
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseNodeUnpublished,
+		fmt.Sprintf("Took pod %s out of the Service endpoints, restarting it in %s", pod.Name, endpointDrainDelay(database)),
+	)
+	database.Status.Rollout = &ydbv1alpha1.CoordinatedRolloutStatus{
+		Pod:          pod.Name,
+		PodUID:       pod.UID,
+		PermissionID: permissionID,
+		StartTime:    metav1.Now(),
+	}
+	return r.setState(ctx, database)
+}
+
+// deleteUnpublishedPod deletes the pod taken out of the Service endpoints
+// once the endpoint drain delay has passed. A pod deleted by someone else
+// meanwhile is restarting anyway.
+func (r *Reconciler) deleteUnpublishedPod(ctx context.Context, database *resources.DatabaseBuilder, pods []corev1.Pod) (bool, ctrl.Result, error) {
+	rollout := database.Status.Rollout
+	var pod *corev1.Pod
+	for i := range pods {
+		if pods[i].UID == rollout.PodUID {
+			pod = &pods[i]
+		}
+	}
+
+	if pod != nil && pod.DeletionTimestamp == nil {
+		if wait := endpointDrainDelay(database) - time.Since(rollout.StartTime.Time); wait > 0 {
+			return Stop, ctrl.Result{RequeueAfter: wait}, nil
+		}
+		if err := r.deletePod(ctx, database, pod); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonDatabaseNodeRestarted,
+			fmt.Sprintf("Restarting pod %s to update it", pod.Name),
+		)
+	}
+
+	rollout.PodUID = ""
+	return r.setState(ctx, database)
+}
+
+func (r *Reconciler) deletePod(ctx context.Context, database *resources.DatabaseBuilder, pod *corev1.Pod) error {
+	if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseNodeRestartFailed,
+			fmt.Sprintf("Failed to delete pod %s: %s", pod.Name, err),
+		)
+		return err
+	}
+	return nil
+}
+
+func endpointDrainDelay(database *resources.DatabaseBuilder) time.Duration {
+	if database.Spec.EndpointDrainDelay == nil {
+		return DefaultEndpointDrainDelay
+	}
+	return database.Spec.EndpointDrainDelay.Duration
+}
+
+// outdatedPods returns the pods not running the revision, highest ordinal
+// first
+func outdatedPods(pods []corev1.Pod, revision string) []*corev1.Pod {
+	var outdated []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp == nil && pod.Labels[appsv1.StatefulSetRevisionLabel] != revision {
+			outdated = append(outdated, pod)
+		}
+	}
+	sort.Slice(outdated, func(i, j int) bool {
+		return podOrdinal(outdated[i]) > podOrdinal(outdated[j])
+	})
+	return outdated
+}
+
+func podOrdinal(pod *corev1.Pod) int {
+	ordinal, err := strconv.Atoi(pod.Name[strings.LastIndex(pod.Name, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
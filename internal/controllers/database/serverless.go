@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	SharedDatabaseReadyCondition       = "SharedDatabaseReady"
+	SharedDatabaseReadyReasonNotFound  = "NotFound"
+	SharedDatabaseReadyReasonNotReady  = "NotReady"
+	SharedDatabaseReadyReasonNotShared = "NotShared"
+	SharedDatabaseReadyReasonAvailable = "Available"
+)
+
+// syncServerless reconciles a serverless Database. Serverless databases run
+// on the compute nodes of the shared Database they reference, so no
+// StatefulSet or Services are built and the reconcile goes straight to
+// tenant management once the storage and shared database are ready.
+func (r *Reconciler) syncServerless(ctx context.Context, database *resources.DatabaseBuilder) (ctrl.Result, error) {
+	var stop bool
+	var result ctrl.Result
+	var err error
+
+	stop, result, err = r.waitForClusterResources(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "waitForClusterResources", result, err)
+	}
+	stop, result, err = r.checkQuotas(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "checkQuotas", result, err)
+	}
+	stop, result, err = r.waitForSharedDatabase(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "waitForSharedDatabase", result, err)
+	}
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		stop, result, err = r.waitForInitializationSlot(ctx, database)
+		if stop {
+			return r.checkStalled(ctx, database, "waitForInitializationSlot", result, err)
+		}
+		stop, result, err = r.setInitialStatus(ctx, database)
+		if stop {
+			return r.checkStalled(ctx, database, "setInitialStatus", result, err)
+		}
+		stop, result, err = r.handleTenantCreation(ctx, database)
+		if stop {
+			return r.checkStalled(ctx, database, "handleTenantCreation", result, err)
+		}
+	}
+	stop, result, err = r.handleTenantAttributes(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "handleTenantAttributes", result, err)
+	}
+	stop, result, err = r.handleTenantQuotas(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "handleTenantQuotas", result, err)
+	}
+	stop, result, err = r.handleInitScripts(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "handleInitScripts", result, err)
+	}
+	stop, result, err = r.handleLinks(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "handleLinks", result, err)
+	}
+	stop, result, err = r.setServerlessReady(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "setServerlessReady", result, err)
+	}
+	stop, result, err = r.handleHealthCheck(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "handleHealthCheck", result, err)
+	}
+	stop, result, err = r.recordObservedGeneration(ctx, database)
+	if stop {
+		return r.checkStalled(ctx, database, "recordObservedGeneration", result, err)
+	}
+	return r.checkStalled(ctx, database, "", ctrl.Result{RequeueAfter: HealthCheckInterval}, nil)
+}
+
+func (r *Reconciler) waitForSharedDatabase(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step waitForSharedDatabase")
+	if err := chaos.Inject(ctx, database, "waitForSharedDatabase"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	ref := database.Spec.ServerlessResources.SharedDatabaseRef
+	sharedDatabaseCr := &ydbv1alpha1.Database{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+	}, sharedDatabaseCr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			msg := fmt.Sprintf("Database (%s/%s) not found.", ref.Name, ref.Namespace)
+			r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseWaitingForSharedDatabase, msg)
+			return r.setSharedDatabaseCondition(ctx, database, metav1.ConditionFalse, SharedDatabaseReadyReasonNotFound, msg)
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseWaitingForSharedDatabase,
+			fmt.Sprintf("Failed to get Database (%s, %s) resource, error: %s", ref.Name, ref.Namespace, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	// A degraded shared database still serves its serverless databases
+	if sharedDatabaseCr.Status.State != string(Ready) && sharedDatabaseCr.Status.State != string(Degraded) {
+		msg := fmt.Sprintf(
+			"Referenced shared Database (%s, %s) in a bad state: %s != Ready",
+			ref.Name,
+			ref.Namespace,
+			sharedDatabaseCr.Status.State,
+		)
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseWaitingForSharedDatabase, msg)
+		return r.setSharedDatabaseCondition(ctx, database, metav1.ConditionFalse, SharedDatabaseReadyReasonNotReady, msg)
+	}
+
+	// CMS only runs serverless tenants on a tenant created as a shared one,
+	// a database migrated to shared resources keeps its dedicated tenant
+	if kind := sharedDatabaseCr.Status.TenantResourcesKind; kind != "" && kind != ydbv1alpha1.ResourcesKindShared {
+		msg := fmt.Sprintf(
+			"Referenced shared Database (%s, %s) is registered with %s resources in CMS",
+			ref.Name,
+			ref.Namespace,
+			kind,
+		)
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseWaitingForSharedDatabase, msg)
+		return r.setSharedDatabaseCondition(ctx, database, metav1.ConditionFalse, SharedDatabaseReadyReasonNotShared, msg)
+	}
+
+	database.SharedDatabase = sharedDatabaseCr
+
+	return r.setSharedDatabaseCondition(
+		ctx,
+		database,
+		metav1.ConditionTrue,
+		SharedDatabaseReadyReasonAvailable,
+		fmt.Sprintf("Shared Database (%s, %s) is ready", ref.Name, ref.Namespace),
+	)
+}
+
+// setSharedDatabaseCondition persists the SharedDatabaseReady condition if it
+// changed. A False condition always stops the reconcile until the shared
+// database becomes available.
+func (r *Reconciler) setSharedDatabaseCondition(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) (bool, ctrl.Result, error) {
+	current := meta.FindStatusCondition(database.Status.Conditions, SharedDatabaseReadyCondition)
+	changed := current == nil || current.Status != status || current.Reason != reason
+	if changed {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    SharedDatabaseReadyCondition,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	if status != metav1.ConditionTrue {
+		if database.Status.State != string(Provisioning) {
+			database.Status.State = string(Provisioning)
+			changed = true
+		}
+		if changed {
+			stop, _, err := r.setState(ctx, database)
+			return stop, ctrl.Result{Requeue: true}, err
+		}
+		return Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	if changed {
+		return r.setState(ctx, database)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) setServerlessReady(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step setServerlessReady")
+
+	if state := readyState(database); database.Status.State != string(state) &&
+		meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonResourcesReady, "Shared database is ready and DB is initialized")
+		database.Status.State = string(state)
+		return r.setState(ctx, database)
+	}
+
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
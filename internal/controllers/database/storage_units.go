@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleStorageUnits applies the storage units of a changed spec to the
+// tenant. The units the tenant currently requires are read from CMS and the
+// missing ones are added with AlterDatabase. Storage groups cannot be taken
+// away from a tenant, so lowered counts are only reported.
+func (r *Reconciler) handleStorageUnits(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Status.StorageUnitsGeneration == database.Generation {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if database.Spec.Resources == nil && database.Spec.SharedResources == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	// Changing the resources kind is handled by handleResourcesMigration
+	if database.Status.ResourcesKind != "" && database.Status.ResourcesKind != database.GetResourcesKind() {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleStorageUnits")
+
+	desired, err := r.desiredStorageUnits(database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantStorageUnitsFailed,
+			fmt.Sprintf("Invalid storage units: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+	status, err := tenant.GetStatus(ctx)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantStorageUnitsFailed,
+			fmt.Sprintf("Error checking tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	current := cms.RequiredStorageUnits(status)
+
+	var missing []ydbv1alpha1.StorageUnit
+	var kept []string
+	for _, kind := range sortedUnitKinds(desired) {
+		switch {
+		case desired[kind] > current[kind]:
+			missing = append(missing, ydbv1alpha1.StorageUnit{UnitKind: kind, Count: desired[kind] - current[kind]})
+		case desired[kind] < current[kind]:
+			kept = append(kept, fmt.Sprintf("%s: %d instead of %d", kind, current[kind], desired[kind]))
+		}
+	}
+	for _, kind := range sortedUnitKinds(current) {
+		if _, ok := desired[kind]; !ok {
+			kept = append(kept, fmt.Sprintf("%s: %d instead of 0", kind, current[kind]))
+		}
+	}
+	if len(kept) > 0 {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantStorageUnitsFailed,
+			fmt.Sprintf("Storage units cannot be removed from tenant %s, keeping %s", tenant.Path, strings.Join(kept, ", ")),
+		)
+	}
+
+	if len(missing) > 0 {
+		if stop, result := r.acquireCMSWindow(database); stop {
+			return stop, result, nil
+		}
+		err = tenant.AddStorageUnits(ctx, missing)
+		r.releaseCMSWindow(database)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonTenantStorageUnitsFailed,
+				fmt.Sprintf("Error adding storage units to tenant %s: %s", tenant.Path, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+
+		added := make([]string, 0, len(missing))
+		for _, unit := range missing {
+			added = append(added, fmt.Sprintf("%d %s", unit.Count, unit.UnitKind))
+		}
+		msg := fmt.Sprintf("Added %s units to tenant %s", strings.Join(added, ", "), tenant.Path)
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonTenantStorageUnitsAdded, msg)
+		database.Status.History = resources.AppendHistory(
+			database.Status.History,
+			resources.HistoryActionStorageUnitsAdded,
+			resources.HistoryOutcomeSucceeded,
+			database.Generation,
+			msg,
+			time.Now(),
+		)
+	}
+
+	database.Status.StorageUnitsGeneration = database.Generation
+	return r.setState(ctx, database)
+}
+
+// desiredStorageUnits returns the units from spec with the pool kinds of
+// the storage, including the ones added by the storage autoscaling
+func (r *Reconciler) desiredStorageUnits(database *resources.DatabaseBuilder) (map[string]uint64, error) {
+	var specUnits []ydbv1alpha1.StorageUnit
+	if database.Spec.Resources != nil {
+		specUnits = database.Spec.Resources.StorageUnits
+	} else {
+		specUnits = database.Spec.SharedResources.StorageUnits
+	}
+	mappedUnits, err := database.GetStorageUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	desired := map[string]uint64{}
+	for i, unit := range mappedUnits {
+		count := unit.Count
+		autoscaling := database.Spec.StorageAutoscaling
+		if autoscaling != nil && database.Status.StorageAutoscaling != nil && specUnits[i].UnitKind == autoscaling.UnitKind {
+			count += database.Status.StorageAutoscaling.AddedUnits
+		}
+		desired[unit.UnitKind] += count
+	}
+	return desired, nil
+}
+
+func sortedUnitKinds(units map[string]uint64) []string {
+	kinds := make([]string, 0, len(units))
+	for kind := range units {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
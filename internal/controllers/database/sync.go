@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -18,25 +19,48 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
 const (
+	Pending      ClusterState = "Pending"
 	Provisioning ClusterState = "Provisioning"
 	Initializing ClusterState = "Initializing"
 	Ready        ClusterState = "Ready"
+	Degraded     ClusterState = "Degraded"
+	Stopped      ClusterState = "Stopped"
+	Failed       ClusterState = "Failed"
 
-	DefaultRequeueDelay             = 10 * time.Second
-	StatusUpdateRequeueDelay        = 1 * time.Second
-	TenantCreationRequeueDelay      = 30 * time.Second
-	StorageAwaitRequeueDelay        = 60 * time.Second
-	SharedDatabaseAwaitRequeueDelay = 60 * time.Second
+	// Failed steps and the ones waiting for another resource return
+	// Requeue, the queue delays them with an exponential backoff
+	StatusUpdateRequeueDelay    = 1 * time.Second
+	TenantCreationRequeueDelay  = 30 * time.Second
+	TenantOperationRequeueDelay = 5 * time.Second
+
+	StoragePoolKindsValidCondition         = "StoragePoolKindsValid"
+	StoragePoolKindsValidReasonValid       = "Valid"
+	StoragePoolKindsValidReasonUnknownKind = "UnknownKind"
+
+	PodsReadyCondition      = "PodsReady"
+	PodsReadyReasonReady    = "Ready"
+	PodsReadyReasonNotReady = "NotReady"
 
 	TenantInitializedCondition        = "TenantInitialized"
 	TenantInitializedReasonInProgress = "InProgres"
 	TenantInitializedReasonCompleted  = "Completed"
 
+	TenantCreationRequestedCondition      = "TenantCreationRequested"
+	TenantCreationRequestedReasonAccepted = "Accepted"
+
+	TenantOperationReadyCondition       = "TenantOperationReady"
+	TenantOperationReadyReasonRunning   = "Running"
+	TenantOperationReadyReasonSucceeded = "Succeeded"
+	TenantOperationReadyReasonFailed    = "Failed"
+
 	Stop     = true
 	Continue = false
 )
@@ -51,36 +75,141 @@ func (r *Reconciler) Sync(ctx context.Context, ydbCr *ydbv1alpha1.Database) (ctr
 	var result ctrl.Result
 	var err error
 
+	start := time.Now()
 	database := resources.NewDatabase(ydbCr)
 	database.SetStatusOnFirstReconcile()
+	database.ConfigRevisionHistory = r.Settings.Get().ConfigRevisionHistory
+
+	if database.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &database)
+	}
+	stop, result, err = r.handlePause(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "", result, err)
+	}
+	stop, result, err = r.handleFinalizer(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleFinalizer", result, err)
+	}
+	stop, result, err = r.validateSpec(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "validateSpec", result, err)
+	}
+
+	if database.Spec.ServerlessResources != nil {
+		return r.syncServerless(ctx, &database)
+	}
 
 	stop, result, err = r.waitForClusterResources(ctx, &database)
 	if stop {
-		return result, err
+		return r.checkStalled(ctx, &database, "waitForClusterResources", result, err)
+	}
+	stop, result, err = r.checkQuotas(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "checkQuotas", result, err)
+	}
+	stop, result, err = r.validateStoragePoolKinds(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "validateStoragePoolKinds", result, err)
+	}
+	stop, result, err = r.handleDedicatedNodes(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleDedicatedNodes", result, err)
+	}
+	stop, result, err = r.handleNodeDrain(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleNodeDrain", result, err)
+	}
+	stop, result, err = r.checkUpgrade(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "checkUpgrade", result, err)
 	}
 	stop, result, err = r.handleResourcesSync(ctx, &database)
 	if stop {
-		return result, err
+		return r.checkStalled(ctx, &database, "handleResourcesSync", result, err)
+	}
+	stop, result, err = r.handleGarbageCollection(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleGarbageCollection", result, err)
+	}
+	stop, result, err = r.handleCapacityEstimate(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleCapacityEstimate", result, err)
+	}
+	stop, result, err = r.handleMountedObjects(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleMountedObjects", result, err)
+	}
+	stop, result, err = r.handleLinks(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleLinks", result, err)
+	}
+	stop, result, err = r.handleConnectionInfo(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleConnectionInfo", result, err)
+	}
+	stop, result, err = r.handleReadinessGates(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleReadinessGates", result, err)
+	}
+	stop, result, err = r.handleCoordinatedRollout(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "handleCoordinatedRollout", result, err)
 	}
 	stop, result, err = r.waitForStatefulSetToScale(ctx, &database)
 	if stop {
-		return result, err
+		return r.checkStalled(ctx, &database, "waitForStatefulSetToScale", result, err)
 	}
 	if !meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
+		stop, result, err = r.waitForInitializationSlot(ctx, &database)
+		if stop {
+			return r.checkStalled(ctx, &database, "waitForInitializationSlot", result, err)
+		}
 		stop, result, err = r.setInitialStatus(ctx, &database)
 		if stop {
-			return result, err
+			return r.checkStalled(ctx, &database, "setInitialStatus", result, err)
 		}
 		stop, result, err = r.handleTenantCreation(ctx, &database)
 		if stop {
-			return result, err
+			return r.checkStalled(ctx, &database, "handleTenantCreation", result, err)
 		}
 	}
-	return ctrl.Result{Requeue: false}, nil
+	stop, result, err = r.runTenantSteps(ctx, &database, start)
+	if stop {
+		return result, err
+	}
+	stop, result, err = r.recordObservedGeneration(ctx, &database)
+	if stop {
+		return r.checkStalled(ctx, &database, "recordObservedGeneration", result, err)
+	}
+
+	result = ctrl.Result{RequeueAfter: r.Settings.Get().ResourceUsageInterval}
+	if r.storageAutoscalingEnabled(&database) && StorageAutoscalingCheckInterval < result.RequeueAfter {
+		result.RequeueAfter = StorageAutoscalingCheckInterval
+	}
+	if HealthCheckInterval < result.RequeueAfter {
+		result.RequeueAfter = HealthCheckInterval
+	}
+	return r.checkStalled(ctx, &database, "", result, nil)
 }
 
 func (r *Reconciler) waitForClusterResources(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
 	r.Log.Info("running step waitForClusterResources")
+	if err := chaos.Inject(ctx, database, "waitForClusterResources"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if database.Spec.ExternalStorageEndpoint != nil {
+		return r.waitForExternalStorage(ctx, database)
+	}
+	if database.Spec.StorageClusterRef == nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseSpecInvalid,
+			"One of spec.storageClusterRef and spec.externalStorageEndpoint must be set",
+		)
+		return Stop, ctrl.Result{Requeue: false}, nil
+	}
 	storage := &ydbv1alpha1.Storage{}
 	err := r.Get(ctx, types.NamespacedName{
 		Name:      database.Spec.StorageClusterRef.Name,
@@ -91,19 +220,28 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 			r.Recorder.Event(
 				database,
 				corev1.EventTypeWarning,
-				"Pending",
+				events.ReasonDatabaseWaitingForStorage,
 				fmt.Sprintf(
 					"Storage (%s/%s) not found.",
 					database.Spec.StorageClusterRef.Name,
 					database.Spec.StorageClusterRef.Namespace,
 				),
 			)
-			return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, nil
+			r.recordFailedCondition(ctx, database, metav1.Condition{
+				Type:   StorageReadyCondition,
+				Reason: StorageReadyReasonNotFound,
+				Message: fmt.Sprintf(
+					"Storage %s/%s not found",
+					database.Spec.StorageClusterRef.Namespace,
+					database.Spec.StorageClusterRef.Name,
+				),
+			})
+			return Stop, ctrl.Result{Requeue: true}, nil
 		}
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
-			"Pending",
+			events.ReasonDatabaseWaitingForStorage,
 			fmt.Sprintf(
 				"Failed to get Database (%s, %s) resource, error: %s",
 				database.Spec.StorageClusterRef.Name,
@@ -111,14 +249,14 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 				err,
 			),
 		)
-		return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, err
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
 	if storage.Status.State != string(Ready) {
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
-			"Pending",
+			events.ReasonDatabaseWaitingForStorage,
 			fmt.Sprintf(
 				"Referenced storage cluster (%s, %s) in a bad state: %s != Ready",
 				database.Spec.StorageClusterRef.Name,
@@ -126,54 +264,229 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 				storage.Status.State,
 			),
 		)
-		return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, err
+		r.recordFailedCondition(ctx, database, metav1.Condition{
+			Type:    StorageReadyCondition,
+			Reason:  StorageReadyReasonNotReady,
+			Message: fmt.Sprintf("Storage %s/%s is %s", storage.Namespace, storage.Name, storage.Status.State),
+		})
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	domain := database.Spec.Domain
+	if domain == "" {
+		domain = ydbv1alpha1.DefaultDatabaseDomain
+	}
+	if !containsString(storage.Domains(), domain) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseSpecInvalid,
+			fmt.Sprintf(
+				"Domain %s is not served by the referenced storage cluster (%s, %s), available domains: %s",
+				domain,
+				database.Spec.StorageClusterRef.Name,
+				database.Spec.StorageClusterRef.Namespace,
+				strings.Join(storage.Domains(), ", "),
+			),
+		)
+		r.recordFailedCondition(ctx, database, metav1.Condition{
+			Type:    StorageReadyCondition,
+			Reason:  StorageReadyReasonDomainNotServed,
+			Message: fmt.Sprintf("Domain %s is not served by Storage %s/%s", domain, storage.Namespace, storage.Name),
+		})
+		return Stop, ctrl.Result{Requeue: true}, nil
 	}
 
 	database.Storage = storage
 
+	if resources.DatabaseTLSManaged(database.Unwrap()) && resources.StorageTLSManaged(storage) {
+		// Database certificates are issued from the CA of the storage, so
+		// the nodes trust each other over interconnect
+		issuer := &corev1.Secret{}
+		err = r.Get(ctx, types.NamespacedName{
+			Name:      resources.TLSSecretName(storage.Name),
+			Namespace: storage.Namespace,
+		}, issuer)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonDatabaseWaitingForStorage,
+				fmt.Sprintf("Failed to get the certificate authority of storage (%s, %s): %s", storage.Name, storage.Namespace, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, nil
+		}
+		database.CertificateIssuer = issuer
+	}
+
+	if setCondition(database, metav1.Condition{
+		Type:    StorageReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  StorageReadyReasonReady,
+		Message: fmt.Sprintf("Storage %s/%s is Ready", storage.Namespace, storage.Name),
+	}) {
+		return r.setState(ctx, database)
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) validateStoragePoolKinds(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	r.Log.Info("running step validateStoragePoolKinds")
+
+	condition := metav1.Condition{
+		Type:    StoragePoolKindsValidCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  StoragePoolKindsValidReasonValid,
+		Message: "Storage unit kinds are available in the referenced Storage",
+	}
+	_, err := database.GetStorageUnits()
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = StoragePoolKindsValidReasonUnknownKind
+		condition.Message = err.Error()
+	}
+
+	current := meta.FindStatusCondition(database.Status.Conditions, StoragePoolKindsValidCondition)
+	changed := current == nil || current.Status != condition.Status || current.Message != condition.Message
+	if changed {
+		meta.SetStatusCondition(&database.Status.Conditions, condition)
+	}
+
+	if err != nil {
+		r.Recorder.Event(database, corev1.EventTypeWarning, events.ReasonDatabaseSpecInvalid, condition.Message)
+		if changed {
+			_, _, updateErr := r.setState(ctx, database)
+			if updateErr != nil {
+				return Stop, ctrl.Result{Requeue: true}, updateErr
+			}
+		}
+		return Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	if changed {
+		return r.setState(ctx, database)
+	}
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
 func (r *Reconciler) waitForStatefulSetToScale(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
 	r.Log.Info("running step waitForStatefulSetToScale")
+	if err := chaos.Inject(ctx, database, "waitForStatefulSetToScale"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	readyReplicas, updatedReplicas, err := r.getWorkloadReplicas(ctx, database)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.recordFailedCondition(ctx, database, metav1.Condition{
+				Type:    StatefulSetReadyCondition,
+				Reason:  StatefulSetReadyReasonNotFound,
+				Message: fmt.Sprintf("%s %s is not created yet", database.Spec.Workload, database.Name),
+			})
+			return Stop, ctrl.Result{Requeue: true}, nil
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get %s: %s", database.Spec.Workload, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
 
-	if database.Spec.ServerlessResources == nil {
-		found := &appsv1.StatefulSet{}
-		err := r.Get(ctx, types.NamespacedName{
-			Name:      database.Name,
-			Namespace: database.Namespace,
-		}, found)
+	// Kept up to date for the scale subresource, the replicas may also be
+	// changed through it by an autoscaler
+	scaleChanged := database.Status.ReadyNodes != readyReplicas || database.Status.Selector != database.GetPodSelector()
+	database.Status.ReadyNodes = readyReplicas
+	database.Status.Selector = database.GetPodSelector()
+
+	replicas := database.Replicas()
+	if readyReplicas != replicas || updatedReplicas != replicas {
+		podList := &corev1.PodList{}
+		err = r.List(ctx, podList,
+			client.InNamespace(database.Namespace),
+			client.MatchingLabels{
+				labels.InstanceKey:  database.Name,
+				labels.ComponentKey: labels.DynamicComponent,
+			},
+		)
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
-			}
 			r.Recorder.Event(
 				database,
-				corev1.EventTypeNormal,
-				"Syncing",
-				fmt.Sprintf("Failed to get StatefulSets: %s", err),
+				corev1.EventTypeWarning,
+				events.ReasonControllerError,
+				fmt.Sprintf("Failed to list database pods: %s", err),
 			)
-			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			return Stop, ctrl.Result{Requeue: true}, err
 		}
 
-		if found.Status.Replicas != database.Spec.Nodes {
-			msg := fmt.Sprintf("Waiting for number of running pods to match expected: %d != %d",
-				found.Status.Replicas,
-				database.Spec.Nodes,
-			)
-			r.Recorder.Event(database, corev1.EventTypeNormal, "Provisioning", msg)
-			database.Status.State = string(Provisioning)
-			return r.setState(ctx, database)
+		msg := fmt.Sprintf("Waiting for pods to become ready: ready %d/%d, updated %d/%d",
+			readyReplicas,
+			replicas,
+			updatedReplicas,
+			replicas,
+		)
+		notReady := resources.NotReadyPods(database.Name, replicas, podList.Items)
+		if database.Spec.Workload == ydbv1alpha1.WorkloadDeployment {
+			notReady = resources.NotReadyPodNames(podList.Items)
 		}
+		if len(notReady) > 0 {
+			msg += fmt.Sprintf(", not ready: %s", strings.Join(notReady, ", "))
+		}
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonPodsNotReady, msg)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    PodsReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  PodsReadyReasonNotReady,
+			Message: msg,
+		})
+		setCondition(database, metav1.Condition{
+			Type:   StatefulSetReadyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: StatefulSetReadyReasonProgressing,
+			Message: fmt.Sprintf("%s %s has %d/%d ready and %d/%d updated replicas",
+				database.Spec.Workload, database.Name, readyReplicas, replicas, updatedReplicas, replicas),
+		})
+		database.Status.State = string(Provisioning)
+		return r.setState(ctx, database)
+	}
+
+	changed := scaleChanged
+	if setCondition(database, metav1.Condition{
+		Type:    StatefulSetReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  StatefulSetReadyReasonReady,
+		Message: fmt.Sprintf("%s %s has all %d replicas ready and updated", database.Spec.Workload, database.Name, replicas),
+	}) {
+		changed = true
+	}
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, PodsReadyCondition) {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    PodsReadyCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  PodsReadyReasonReady,
+			Message: "All pods are ready and up to date",
+		})
+		changed = true
 	}
 
-	if database.Status.State != string(Ready) &&
+	state, reason, msg := readyState(database), events.ReasonResourcesReady, "Resource are ready and DB is initialized"
+	if database.Spec.OperationalState == ydbv1alpha1.OperationalStateStopped {
+		state, reason, msg = Stopped, events.ReasonDatabaseStopped, "Dynamic nodes are scaled to zero, the tenant is kept"
+	}
+	if database.Status.State != string(state) &&
 		meta.IsStatusConditionTrue(database.Status.Conditions, TenantInitializedCondition) {
-		r.Recorder.Event(database, corev1.EventTypeNormal, "ResourcesReady", "Resource are ready and DB is initialized")
-		database.Status.State = string(Ready)
-		return r.setState(ctx, database)
+		r.Recorder.Event(database, corev1.EventTypeNormal, reason, msg)
+		database.Status.State = string(state)
+		changed = true
 	}
 
+	if changed {
+		return r.setState(ctx, database)
+	}
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -182,19 +495,43 @@ func (r *Reconciler) handleResourcesSync(
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step handleResourcesSync")
+	if err := chaos.Inject(ctx, database, "handleResourcesSync"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	key := types.NamespacedName{Namespace: database.Namespace, Name: database.Name}
+	builders := database.GetResourceBuilders()
+	hash, hashErr := resources.RenderedHash(database, builders)
+	if hashErr == nil && !r.childDeletions.SyncRequired(key, database.Status.ResourcesSync) && !resources.ResourcesSyncRequired(
+		database.Status.ResourcesSync,
+		hash,
+		r.Settings.Get().ResourcesResyncPeriod,
+		time.Now(),
+	) {
+		r.Log.Info("rendered resources are unchanged, skipping")
+		if setCondition(database, resourcesSyncedCondition()) {
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
 
-	for _, builder := range database.GetResourceBuilders() {
+	var changed []string
+	for _, builder := range builders {
 		newResource := builder.Placeholder(database)
 
-		result, err := resources.CreateOrUpdateIgnoreStatus(ctx, r.Client, newResource, func() error {
+		var replicasBefore int32
+		var existed bool
+		result, diff, err := resources.CreateOrUpdateIgnoreStatusWithDiff(ctx, r.Client, newResource, func() error {
 			var err error
 
+			replicasBefore, existed = nodesReplicas(newResource, database)
+
 			err = builder.Build(newResource)
 			if err != nil {
 				r.Recorder.Event(
 					database,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					events.ReasonResourcesSyncFailed,
 					fmt.Sprintf("Failed building resources: %s", err),
 				)
 				return err
@@ -205,7 +542,7 @@ func (r *Reconciler) handleResourcesSync(
 				r.Recorder.Event(
 					database,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					events.ReasonResourcesSyncFailed,
 					fmt.Sprintf("Error setting controller reference for resource: %s", err),
 				)
 				return err
@@ -220,25 +557,99 @@ func (r *Reconciler) handleResourcesSync(
 			newResource.GetNamespace(),
 			newResource.GetName(),
 		)
-		if err != nil {
+		if errors.Is(err, resources.ErrRecreating) {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeNormal,
+				events.ReasonResourceRecreated,
+				eventMessage+", recreating to change immutable fields",
+			)
+			return Stop, ctrl.Result{Requeue: true}, nil
+		} else if err != nil {
 			r.Recorder.Event(
 				database,
 				corev1.EventTypeWarning,
-				"ProvisioningFailed",
+				events.ReasonResourcesSyncFailed,
 				eventMessage+fmt.Sprintf(", failed to sync, error: %s", err),
 			)
-			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			r.recordFailedCondition(ctx, database, metav1.Condition{
+				Type:    ResourcesSyncedCondition,
+				Reason:  ResourcesSyncedReasonSyncFailed,
+				Message: fmt.Sprintf("Failed to sync %s %s: %s", reflect.TypeOf(newResource).Elem().Name(), newResource.GetName(), err),
+			})
+			return Stop, ctrl.Result{Requeue: true}, err
 		} else if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated {
+			eventMessage += fmt.Sprintf(", changed, result: %s", result)
+			if len(diff) > 0 {
+				r.Log.Info("resource updated",
+					"kind", reflect.TypeOf(newResource).Elem().Name(),
+					"name", newResource.GetName(),
+					"diff", diff,
+				)
+				if r.Settings.Get().EventDiffs {
+					eventMessage += ", fields: " + resources.FormatDiff(diff)
+				}
+			}
 			r.Recorder.Event(
 				database,
 				corev1.EventTypeNormal,
-				"Provisioning",
-				eventMessage+fmt.Sprintf(", changed, result: %s", result),
+				events.ReasonResourceUpdated,
+				eventMessage,
 			)
+			changed = append(changed, fmt.Sprintf("%s %s", newResource.GetName(), result))
+			if replicasAfter, ok := nodesReplicas(newResource, database); ok && existed && replicasAfter != replicasBefore {
+				database.Status.History = resources.AppendHistory(
+					database.Status.History,
+					resources.HistoryActionScaled,
+					resources.HistoryOutcomeSucceeded,
+					database.Generation,
+					fmt.Sprintf("Scaled from %d to %d nodes", replicasBefore, replicasAfter),
+					time.Now(),
+				)
+			}
 		}
 	}
 	r.Log.Info("resource sync complete")
-	return Continue, ctrl.Result{Requeue: false}, nil
+
+	if err := r.removeStaleWorkload(ctx, database); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonResourcesSyncFailed,
+			fmt.Sprintf("Failed to remove the previous workload: %s", err),
+		)
+		r.recordFailedCondition(ctx, database, metav1.Condition{
+			Type:    ResourcesSyncedCondition,
+			Reason:  ResourcesSyncedReasonSyncFailed,
+			Message: fmt.Sprintf("Failed to remove the previous workload: %s", err),
+		})
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if len(changed) > 0 {
+		database.Status.History = resources.AppendHistory(
+			database.Status.History,
+			resources.HistoryActionResourcesUpdated,
+			resources.HistoryOutcomeSucceeded,
+			database.Generation,
+			strings.Join(changed, ", "),
+			time.Now(),
+		)
+	}
+
+	if hashErr != nil {
+		r.Log.Error(hashErr, "failed to record resources sync status")
+		if setCondition(database, resourcesSyncedCondition()) {
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if database.Status.ResourcesSync != nil && database.Status.ResourcesSync.Hash != hash {
+		r.Log.Info("rendered resources changed", "hash", hash)
+	}
+	database.Status.ResourcesSync = resources.NewResourcesSyncStatus(database, hash, time.Now())
+	setCondition(database, resourcesSyncedCondition())
+	return r.setState(ctx, database)
 }
 
 func (r *Reconciler) setInitialStatus(
@@ -271,76 +682,40 @@ func (r *Reconciler) handleTenantCreation(
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step handleTenantCreation")
+	if err := chaos.Inject(ctx, database, "handleTenantCreation"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
 
 	path := database.GetPath()
 	var storageUnits []ydbv1alpha1.StorageUnit
 	var shared bool
 	var sharedDatabasePath string
 	switch {
-	case database.Spec.Resources != nil:
-		storageUnits = database.Spec.Resources.StorageUnits
-		shared = false
-	case database.Spec.SharedResources != nil:
-		storageUnits = database.Spec.SharedResources.StorageUnits
-		shared = true
-	case database.Spec.ServerlessResources != nil:
-		sharedDatabaseCr := &ydbv1alpha1.Database{}
-		err := r.Get(ctx, types.NamespacedName{
-			Name:      database.Spec.ServerlessResources.SharedDatabaseRef.Name,
-			Namespace: database.Spec.ServerlessResources.SharedDatabaseRef.Namespace,
-		}, sharedDatabaseCr)
+	case database.Spec.Resources != nil, database.Spec.SharedResources != nil:
+		var err error
+		storageUnits, err = database.GetStorageUnits()
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				r.Recorder.Event(
-					database,
-					corev1.EventTypeWarning,
-					"Pending",
-					fmt.Sprintf(
-						"Database (%s/%s) not found.",
-						database.Spec.ServerlessResources.SharedDatabaseRef.Name,
-						database.Spec.ServerlessResources.SharedDatabaseRef.Namespace,
-					),
-				)
-				return Stop, ctrl.Result{RequeueAfter: SharedDatabaseAwaitRequeueDelay}, nil
-			}
 			r.Recorder.Event(
 				database,
 				corev1.EventTypeWarning,
-				"Pending",
-				fmt.Sprintf(
-					"Failed to get Database (%s, %s) resource, error: %s",
-					database.Spec.ServerlessResources.SharedDatabaseRef.Name,
-					database.Spec.ServerlessResources.SharedDatabaseRef.Namespace,
-					err,
-				),
-			)
-			return Stop, ctrl.Result{RequeueAfter: SharedDatabaseAwaitRequeueDelay}, err
-		}
-
-		if sharedDatabaseCr.Status.State != "Ready" {
-			r.Recorder.Event(
-				database,
-				corev1.EventTypeWarning,
-				"Pending",
-				fmt.Sprintf(
-					"Referenced shared Database (%s, %s) in a bad state: %s != Ready",
-					database.Spec.ServerlessResources.SharedDatabaseRef.Name,
-					database.Spec.ServerlessResources.SharedDatabaseRef.Namespace,
-					sharedDatabaseCr.Status.State,
-				),
+				events.ReasonTenantInitializationFailed,
+				fmt.Sprintf("Invalid storage units: %s", err),
 			)
-			return Stop, ctrl.Result{RequeueAfter: SharedDatabaseAwaitRequeueDelay}, err
+			return Stop, ctrl.Result{Requeue: true}, err
 		}
-		sharedDatabasePath = fmt.Sprintf(ydbv1alpha1.TenantNameFormat, sharedDatabaseCr.Spec.Domain, sharedDatabaseCr.Name)
+		shared = database.Spec.SharedResources != nil
+	case database.Spec.ServerlessResources != nil:
+		sharedDatabaseCr := database.SharedDatabase
+		sharedDatabasePath = sharedDatabaseCr.TenantPath()
 	default:
 		// TODO: move this logic to webhook
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
-			"ControllerError",
+			events.ReasonControllerError,
 			ErrIncorrectDatabaseResourcesConfiguration.Error(),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, ErrIncorrectDatabaseResourcesConfiguration
+		return Stop, ctrl.Result{Requeue: true}, ErrIncorrectDatabaseResourcesConfiguration
 	}
 	tenant := cms.Tenant{
 		StorageEndpoint:      database.GetStorageEndpoint(),
@@ -349,23 +724,183 @@ func (r *Reconciler) handleTenantCreation(
 		Shared:               shared,
 		SharedDatabasePath:   sharedDatabasePath,
 		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+		IdempotencyKey:       string(database.UID),
+		Attributes:           database.GetTenantAttributes(),
+		Quotas:               database.GetTenantQuotas(),
+		DataSizeQuota:        database.Spec.DataSizeQuota,
+		PlanResolution:       database.Spec.TenantOptions.PlanResolutionMilliseconds(),
 	}
-	err := tenant.Create(ctx)
-	if err != nil {
+
+	if database.Status.TenantOperation != nil {
+		return r.pollTenantOperation(ctx, database, &tenant)
+	}
+
+	// The tenant may already exist if the operator was restarted after
+	// CreateDatabase succeeded but before the condition was persisted.
+	var status *Ydb_Cms.GetDatabaseStatusResult
+	err := chaos.Inject(ctx, database, chaos.CMSGetDatabaseStatus)
+	if err == nil {
+		status, err = tenant.GetStatus(ctx)
+	}
+	switch {
+	case err == nil:
+		return r.adoptTenant(ctx, database, &tenant, status)
+	case errors.Is(err, cms.ErrTenantNotFound):
+		if stop, result := r.acquireCMSWindow(database); stop {
+			return stop, result, nil
+		}
+		var operationID string
+		err = chaos.Inject(ctx, database, chaos.CMSCreateDatabase)
+		if err == nil {
+			operationID, err = tenant.Create(ctx)
+		}
+		r.releaseCMSWindow(database)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				events.ReasonTenantInitializationFailed,
+				fmt.Sprintf("Error creating tenant %s: %s", tenant.Path, err),
+			)
+			return Stop, ctrl.Result{}, err
+		}
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    TenantCreationRequestedCondition,
+			Status:  "True",
+			Reason:  TenantCreationRequestedReasonAccepted,
+			Message: "CreateDatabase is accepted by CMS",
+		})
+		if operationID == "" {
+			return r.completeTenantCreation(ctx, database, &tenant)
+		}
+
+		database.Status.TenantOperation = &ydbv1alpha1.TenantOperationStatus{
+			ID:        operationID,
+			StartTime: metav1.Now(),
+		}
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    TenantOperationReadyCondition,
+			Status:  "False",
+			Reason:  TenantOperationReadyReasonRunning,
+			Message: fmt.Sprintf("Waiting for operation %s", operationID),
+		})
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			events.ReasonTenantCreating,
+			fmt.Sprintf("Tenant %s is being created, operation %s", tenant.Path, operationID),
+		)
+		if _, _, err := r.setState(ctx, database); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return Stop, ctrl.Result{RequeueAfter: TenantOperationRequeueDelay}, nil
+	default:
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantInitializationFailed,
+			fmt.Sprintf("Error checking tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{}, err
+	}
+}
+
+// pollTenantOperation checks the CreateDatabase operation kept in
+// status.tenantOperation on every reconcile instead of waiting for it. A
+// failed or forgotten operation is dropped, so the next reconcile checks
+// the tenant and creates it again with the same idempotency key.
+func (r *Reconciler) pollTenantOperation(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	tenant *cms.Tenant,
+) (bool, ctrl.Result, error) {
+	operation := database.Status.TenantOperation
+	ready, err := tenant.CheckOperation(ctx, operation.ID)
+	switch {
+	case errors.Is(err, cms.ErrOperationNotFound):
+		r.Log.Info("tenant operation is not found, checking the tenant again", "operation", operation.ID)
+		database.Status.TenantOperation = nil
+		return r.setState(ctx, database)
+	case errors.Is(err, cms.ErrOperationFailed):
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
-			"InitializingFailed",
+			events.ReasonTenantInitializationFailed,
 			fmt.Sprintf("Error creating tenant %s: %s", tenant.Path, err),
 		)
-		return Stop, ctrl.Result{RequeueAfter: TenantCreationRequeueDelay}, err
+		database.Status.TenantOperation = nil
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    TenantOperationReadyCondition,
+			Status:  "False",
+			Reason:  TenantOperationReadyReasonFailed,
+			Message: err.Error(),
+		})
+		if _, _, err := r.setState(ctx, database); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return Stop, ctrl.Result{RequeueAfter: TenantCreationRequeueDelay}, nil
+	case err != nil:
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantInitializationFailed,
+			fmt.Sprintf("Error checking operation %s of tenant %s: %s", operation.ID, tenant.Path, err),
+		)
+		return Stop, ctrl.Result{}, err
+	case !ready:
+		return Stop, ctrl.Result{RequeueAfter: TenantOperationRequeueDelay}, nil
+	}
+
+	database.Status.TenantOperation = nil
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:    TenantOperationReadyCondition,
+		Status:  "True",
+		Reason:  TenantOperationReadyReasonSucceeded,
+		Message: fmt.Sprintf("Operation %s succeeded", operation.ID),
+	})
+	return r.completeTenantCreation(ctx, database, tenant)
+}
+
+func (r *Reconciler) completeTenantCreation(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	tenant *cms.Tenant,
+) (bool, ctrl.Result, error) {
+	if _, err := tenant.GetStatus(ctx); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonTenantInitializationFailed,
+			fmt.Sprintf("Error verifying tenant %s after creation: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{}, err
 	}
 	r.Recorder.Event(
 		database,
 		corev1.EventTypeNormal,
-		"Initialized",
+		events.ReasonTenantCreated,
+		fmt.Sprintf("Tenant %s created", tenant.Path),
+	)
+	if len(tenant.Attributes) > 0 {
+		database.Status.TenantAttributes = tenant.Attributes
+	}
+	// The tenant has the units of spec, adopted ones are compared by handleStorageUnits
+	database.Status.StorageUnitsGeneration = database.Generation
+	database.Status.History = resources.AppendHistory(
+		database.Status.History,
+		resources.HistoryActionTenantCreated,
+		resources.HistoryOutcomeSucceeded,
+		database.Generation,
 		fmt.Sprintf("Tenant %s created", tenant.Path),
+		time.Now(),
 	)
+	return r.setTenantInitialized(ctx, database)
+}
+
+func (r *Reconciler) setTenantInitialized(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	database.Status.ResourcesKind = database.GetResourcesKind()
+	database.Status.TenantResourcesKind = database.Status.ResourcesKind
 	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 		Type:    TenantInitializedCondition,
 		Status:  "True",
@@ -385,23 +920,35 @@ func (r *Reconciler) setState(
 		Name:      database.Name,
 	}, databaseCr)
 	if err != nil {
-		r.Recorder.Event(databaseCr, corev1.EventTypeWarning, "ControllerError", "Failed fetching CR before status update")
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		r.Recorder.Event(databaseCr, corev1.EventTypeWarning, events.ReasonControllerError, "Failed fetching CR before status update")
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
-	databaseCr.Status.State = database.Status.State
-	databaseCr.Status.Conditions = database.Status.Conditions
+	if databaseCr.Status.State != database.Status.State {
+		now := metav1.Now()
+		database.Status.StateTransitionTime = &now
+	}
+	databaseCr.Status = database.Status
 
 	err = r.Status().Update(ctx, databaseCr)
 	if err != nil {
 		r.Recorder.Event(
 			databaseCr,
 			corev1.EventTypeWarning,
-			"ControllerError",
+			events.ReasonControllerError,
 			fmt.Sprintf("failed setting status: %s", err),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
 	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
 }
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleTenantQuotas keeps the tenant quotas and data size quota in CMS in
+// sync with the spec. CMS replaces them together, so both are applied on
+// any change. The quotas applied last are kept in status, so removing them
+// from the spec lifts the limits.
+func (r *Reconciler) handleTenantQuotas(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	desired := database.GetTenantQuotas()
+	dataSize := database.Spec.DataSizeQuota
+	if equality.Semantic.DeepEqual(desired, database.Status.TenantQuotas) &&
+		equality.Semantic.DeepEqual(dataSize, database.Status.DataSizeQuota) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleTenantQuotas")
+
+	tenant := cms.Tenant{
+		StorageEndpoint:      database.GetStorageEndpoint(),
+		Path:                 database.GetPath(),
+		UseGrpcSecureChannel: database.Storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		CA:                   database.StorageCA,
+	}
+
+	if stop, result := r.acquireCMSWindow(database); stop {
+		return stop, result, nil
+	}
+	err := tenant.SetQuotas(ctx, desired, dataSize)
+	r.releaseCMSWindow(database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to update quotas of tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonTenantQuotasSynced,
+		fmt.Sprintf("Updated quotas of tenant %s", tenant.Path),
+	)
+
+	database.Status.TenantQuotas = desired.DeepCopy()
+	database.Status.DataSizeQuota = dataSize.DeepCopy()
+	return r.setState(ctx, database)
+}
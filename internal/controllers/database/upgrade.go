@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	UpgradeRequeueDelay = 15 * time.Second
+
+	DefaultUpgradeBatchInterval = 30 * time.Second
+
+	// databaseContainerName is the container running the YDB node
+	databaseContainerName = "ydb-dynamic"
+)
+
+// checkUpgrade starts an upgrade when spec.image differs from the image of
+// the StatefulSet. An image with a version newer than the one of the
+// Storage is held back until the Storage is upgraded, the database nodes
+// must never run ahead of the storage nodes. The new pod template is
+// applied by handleResourcesSync together with the partition of the first
+// batch.
+func (r *Reconciler) checkUpgrade(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	if database.Spec.Upgrade == nil || database.Spec.Workload == ydbv1alpha1.WorkloadDeployment {
+		if database.Status.Upgrade != nil {
+			database.Status.Upgrade = nil
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	image := database.Spec.Image.Name
+	if upgrade := database.Status.Upgrade; upgrade != nil && upgrade.ToImage == image {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step checkUpgrade")
+
+	statefulSet, err := r.getStatefulSet(ctx, database)
+	if err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if statefulSet == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	current := statefulSetImage(statefulSet)
+	if current == "" || current == image {
+		// The spec went back to the image the pods run before the upgrade
+		// was applied
+		if database.Status.Upgrade != nil {
+			database.Status.Upgrade = nil
+			return r.setState(ctx, database)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	version := autoupdate.ImageTag(image)
+	storageVersion := autoupdate.ImageTag(database.Storage.Spec.Image.Name)
+	if autoupdate.Newer(version, storageVersion) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonDatabaseUpgradeBlocked,
+			fmt.Sprintf("Version %s is newer than version %s of Storage %s, upgrade the Storage first",
+				version, storageVersion, database.Storage.Name),
+		)
+		return Stop, ctrl.Result{RequeueAfter: UpgradeRequeueDelay}, nil
+	}
+
+	batchSize := upgradeBatchSize(database.Spec.Upgrade)
+	replicas := database.Replicas()
+	database.Status.Upgrade = &ydbv1alpha1.DatabaseUpgradeStatus{
+		FromImage: current,
+		ToImage:   image,
+		Partition: lowerPartition(replicas, batchSize),
+		Replicas:  replicas,
+		StartTime: metav1.Now(),
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseUpgradeStarted,
+		fmt.Sprintf("Upgrading from %s to %s in batches of %d pods", current, image, batchSize),
+	)
+	return r.setState(ctx, database)
+}
+
+// handleUpgrade moves the partition of the StatefulSet down one batch at a
+// time, highest ordinals first. The next batch starts once every updated
+// pod is ready, the self-check does not report the database Degraded and
+// spec.upgrade.batchInterval has passed since. With the coordinated rollout
+// the pods are restarted by handleCoordinatedRollout and the progress is
+// only followed.
+func (r *Reconciler) handleUpgrade(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	upgrade := database.Status.Upgrade
+	if upgrade == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleUpgrade")
+
+	statefulSet, err := r.getStatefulSet(ctx, database)
+	if err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if statefulSet == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if statefulSetImage(statefulSet) != upgrade.ToImage || statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		// The new pod template is not applied or observed yet
+		return Stop, ctrl.Result{RequeueAfter: UpgradeRequeueDelay}, nil
+	}
+
+	podList := &corev1.PodList{}
+	err = r.List(ctx, podList,
+		client.InNamespace(database.Namespace),
+		client.MatchingLabels{
+			labels.InstanceKey:  database.Name,
+			labels.ComponentKey: labels.DynamicComponent,
+		},
+	)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to list database pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	replicas := database.Replicas()
+	notReady := resources.NotReadyPods(database.Name, replicas, podList.Items)
+	changed := upgrade.UpdatedReplicas != statefulSet.Status.UpdatedReplicas || upgrade.Replicas != replicas
+	upgrade.UpdatedReplicas = statefulSet.Status.UpdatedReplicas
+	upgrade.Replicas = replicas
+
+	if upgrade.UpdatedReplicas >= replicas && len(notReady) == 0 {
+		msg := fmt.Sprintf("Upgraded from %s to %s", upgrade.FromImage, upgrade.ToImage)
+		r.Recorder.Event(database, corev1.EventTypeNormal, events.ReasonDatabaseUpgraded, msg)
+		database.Status.History = resources.AppendHistory(
+			database.Status.History,
+			resources.HistoryActionUpgraded,
+			resources.HistoryOutcomeSucceeded,
+			database.Generation,
+			msg,
+			time.Now(),
+		)
+		database.Status.Upgrade = nil
+		return r.setState(ctx, database)
+	}
+	if database.Spec.CoordinatedRollout {
+		if changed {
+			return r.setState(ctx, database)
+		}
+		return Stop, ctrl.Result{RequeueAfter: UpgradeRequeueDelay}, nil
+	}
+
+	healthy := len(notReady) == 0 &&
+		upgrade.UpdatedReplicas >= replicas-upgrade.Partition &&
+		!meta.IsStatusConditionTrue(database.Status.Conditions, DegradedCondition)
+	if !healthy {
+		if upgrade.BatchReadyTime != nil {
+			upgrade.BatchReadyTime = nil
+			changed = true
+		}
+		if changed {
+			return r.setState(ctx, database)
+		}
+		return Stop, ctrl.Result{RequeueAfter: UpgradeRequeueDelay}, nil
+	}
+	if upgrade.BatchReadyTime == nil {
+		now := metav1.Now()
+		upgrade.BatchReadyTime = &now
+		return r.setState(ctx, database)
+	}
+	interval := DefaultUpgradeBatchInterval
+	if database.Spec.Upgrade.BatchInterval != nil {
+		interval = database.Spec.Upgrade.BatchInterval.Duration
+	}
+	if wait := interval - time.Since(upgrade.BatchReadyTime.Time); wait > 0 {
+		if changed {
+			return r.setState(ctx, database)
+		}
+		return Stop, ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	partition := lowerPartition(upgrade.Partition, upgradeBatchSize(database.Spec.Upgrade))
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonDatabaseUpgrading,
+		fmt.Sprintf("Updating pods with ordinals %d to %d, %d of %d pods updated", partition, upgrade.Partition-1, upgrade.UpdatedReplicas, replicas),
+	)
+	// The partition is patched here rather than left to handleResourcesSync,
+	// which may skip the StatefulSet until the resync period
+	patch := client.MergeFrom(statefulSet.DeepCopy())
+	statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		},
+	}
+	if err := r.Patch(ctx, statefulSet, patch); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to set StatefulSet partition to %d: %s", partition, err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	upgrade.Partition = partition
+	upgrade.BatchReadyTime = nil
+	return r.setState(ctx, database)
+}
+
+// getStatefulSet returns the StatefulSet of the database, nil when it does
+// not exist yet
+func (r *Reconciler) getStatefulSet(ctx context.Context, database *resources.DatabaseBuilder) (*appsv1.StatefulSet, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKey{Name: database.Name, Namespace: database.Namespace}, statefulSet)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get StatefulSet: %s", err),
+		)
+		return nil, err
+	}
+	return statefulSet, nil
+}
+
+func statefulSetImage(statefulSet *appsv1.StatefulSet) string {
+	for _, container := range statefulSet.Spec.Template.Spec.Containers {
+		if container.Name == databaseContainerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func upgradeBatchSize(upgrade *ydbv1alpha1.UpgradeStrategy) int32 {
+	if upgrade.BatchSize < 1 {
+		return 1
+	}
+	return upgrade.BatchSize
+}
+
+func lowerPartition(partition, batchSize int32) int32 {
+	if partition <= batchSize {
+		return 0
+	}
+	return partition - batchSize
+}
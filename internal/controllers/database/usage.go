@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/monitoring"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleResourceUsage records CPU and memory usage of the pods in status.
+// Failing to collect the usage never blocks the reconcile.
+func (r *Reconciler) handleResourceUsage(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	settings := r.Settings.Get()
+	if !settings.FeatureEnabled(operatorconfig.FeatureResourceUsageReporting) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleResourceUsage")
+
+	last := database.Status.ResourceUsage
+	if last != nil && time.Since(last.LastUpdateTime.Time) < settings.ResourceUsageInterval {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	usage, err := monitoring.GetPodsResourceUsage(ctx, r.Client, database.Namespace, map[string]string{
+		labels.InstanceKey:  database.Name,
+		labels.ComponentKey: labels.DynamicComponent,
+	})
+	if errors.Is(err, monitoring.ErrMetricsAPIUnavailable) {
+		r.Log.Info("metrics API is not available, skipping resource usage")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "failed to get pods resource usage")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	database.Status.ResourceUsage = usage
+	return r.setState(ctx, database)
+}
+
+// handleCapacityEstimate records the total resources requested by the pods
+// and volumes in status, the same estimate is shown as a warning on create
+func (r *Reconciler) handleCapacityEstimate(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleCapacityEstimate")
+
+	estimate := database.EstimateCapacity()
+	if database.Status.Capacity.Equal(&estimate) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	database.Status.Capacity = &estimate
+	return r.setState(ctx, database)
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+const (
+	// storageRefIndex indexes Databases by the namespaced name of their
+	// Storage
+	storageRefIndex = "spec.storageClusterRef"
+	// sharedDatabaseRefIndex indexes serverless Databases by the namespaced
+	// name of their shared Database
+	sharedDatabaseRefIndex = "spec.serverlessResources.sharedDatabaseRef"
+)
+
+func indexStorageRef(obj client.Object) []string {
+	database, ok := obj.(*ydbv1alpha1.Database)
+	if !ok || database.Spec.StorageClusterRef == nil {
+		return nil
+	}
+	return []string{storageKey(database).String()}
+}
+
+func indexSharedDatabaseRef(obj client.Object) []string {
+	database, ok := obj.(*ydbv1alpha1.Database)
+	if !ok || database.Spec.ServerlessResources == nil {
+		return nil
+	}
+	ref := database.Spec.ServerlessResources.SharedDatabaseRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = database.Namespace
+	}
+	return []string{types.NamespacedName{Namespace: namespace, Name: ref.Name}.String()}
+}
+
+// databasesForStorage maps a Storage to the Databases referencing it, so
+// they are reconciled as soon as it changes its state instead of on their
+// next requeue
+func (r *Reconciler) databasesForStorage(obj client.Object) []reconcile.Request {
+	return r.databasesByIndex(obj, storageRefIndex)
+}
+
+// serverlessDatabasesForShared maps a Database to the serverless Databases
+// running on it, so their tenants are created as soon as it becomes Ready
+// instead of on their next requeue
+func (r *Reconciler) serverlessDatabasesForShared(obj client.Object) []reconcile.Request {
+	return r.databasesByIndex(obj, sharedDatabaseRefIndex)
+}
+
+func (r *Reconciler) databasesByIndex(obj client.Object, index string) []reconcile.Request {
+	databases := &ydbv1alpha1.DatabaseList{}
+	err := r.List(context.Background(), databases, client.MatchingFields{
+		index: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String(),
+	})
+	if err != nil {
+		ctrl.Log.WithName("database").Error(err, "failed to list the databases referencing an object",
+			"index", index, "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(databases.Items))
+	for _, database := range databases.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: database.Namespace, Name: database.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// getWorkloadReplicas returns the ready and updated replicas of the
+// StatefulSet or Deployment running the dynamic nodes, see spec.workload
+func (r *Reconciler) getWorkloadReplicas(ctx context.Context, database *resources.DatabaseBuilder) (int32, int32, error) {
+	key := client.ObjectKey{Name: database.Name, Namespace: database.Namespace}
+	if database.Spec.Workload == ydbv1alpha1.WorkloadDeployment {
+		found := &appsv1.Deployment{}
+		if err := r.Get(ctx, key, found); err != nil {
+			return 0, 0, err
+		}
+		return found.Status.ReadyReplicas, found.Status.UpdatedReplicas, nil
+	}
+
+	found := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, key, found); err != nil {
+		return 0, 0, err
+	}
+	return found.Status.ReadyReplicas, found.Status.UpdatedReplicas, nil
+}
+
+// getWorkloadPodSpec returns the pod spec of the StatefulSet or Deployment
+// running the dynamic nodes
+func (r *Reconciler) getWorkloadPodSpec(ctx context.Context, database *resources.DatabaseBuilder) (corev1.PodSpec, error) {
+	key := client.ObjectKey{Name: database.Name, Namespace: database.Namespace}
+	if database.Spec.Workload == ydbv1alpha1.WorkloadDeployment {
+		found := &appsv1.Deployment{}
+		if err := r.Get(ctx, key, found); err != nil {
+			return corev1.PodSpec{}, err
+		}
+		return found.Spec.Template.Spec, nil
+	}
+
+	found := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, key, found); err != nil {
+		return corev1.PodSpec{}, err
+	}
+	return found.Spec.Template.Spec, nil
+}
+
+// nodesReplicas returns the replicas of obj if it is the existing workload
+// running the dynamic nodes
+func nodesReplicas(obj client.Object, database *resources.DatabaseBuilder) (int32, bool) {
+	if obj.GetName() != database.Name {
+		return 0, false
+	}
+	if replicas, ok := resources.StatefulSetReplicas(obj); ok {
+		return replicas, true
+	}
+	return resources.DeploymentReplicas(obj)
+}
+
+// removeStaleWorkload deletes the workload of the other kind once
+// spec.workload is changed, the pods of the new one are started by the
+// resources sync
+func (r *Reconciler) removeStaleWorkload(ctx context.Context, database *resources.DatabaseBuilder) error {
+	var stale client.Object = &appsv1.StatefulSet{}
+	if database.Spec.Workload != ydbv1alpha1.WorkloadDeployment {
+		stale = &appsv1.Deployment{}
+	}
+
+	err := r.Get(ctx, client.ObjectKey{Name: database.Name, Namespace: database.Namespace}, stale)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !isOwnedBy(stale, database) {
+		return nil
+	}
+
+	if err := r.Delete(ctx, stale, client.PropagationPolicy("Background")); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		events.ReasonResourceRecreated,
+		fmt.Sprintf("Workload changed to %s, deleted %T %s", database.Spec.Workload, stale, stale.GetName()),
+	)
+	return nil
+}
+
+func isOwnedBy(obj client.Object, database *resources.DatabaseBuilder) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == database.UID {
+			return true
+		}
+	}
+	return false
+}
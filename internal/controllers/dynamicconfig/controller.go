@@ -0,0 +1,69 @@
+package dynamicconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+)
+
+// Reconciler reconciles a DynamicConfig object
+type Reconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Log      logr.Logger
+
+	// Settings are the operator-wide settings, reloaded from OperatorConfig
+	Settings *operatorconfig.Store
+}
+
+//+kubebuilder:rbac:groups=ydb.tech,resources=dynamicconfigs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ydb.tech,resources=dynamicconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ydb.tech,resources=dynamicconfigs/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log = log.FromContext(ctx)
+
+	config := &ydbv1alpha1.DynamicConfig{}
+	err := r.Get(ctx, req.NamespacedName, config)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.Log.Info("dynamicconfig resources not found")
+			operatormetrics.Forget(operatormetrics.KindDynamicConfig, req.Namespace, req.Name)
+			return ctrl.Result{Requeue: false}, nil
+		}
+		r.Log.Error(err, "unexpected Get error")
+		return ctrl.Result{Requeue: true}, err
+	}
+	start := time.Now()
+	result, err := r.Sync(ctx, config)
+	operatormetrics.ObserveReconcile(operatormetrics.KindDynamicConfig, req.Namespace, req.Name, time.Since(start), err)
+	if err != nil {
+		r.Log.Error(err, "unexpected Sync error")
+	}
+	return result, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ydbv1alpha1.DynamicConfig{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{RateLimiter: r.Settings.Get().RequeueRateLimiter()}).
+		Complete(r)
+}
@@ -0,0 +1,222 @@
+package dynamicconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	Pending ConfigState = "Pending"
+	Applied ConfigState = "Applied"
+	Failed  ConfigState = "Failed"
+
+	StatusUpdateRequeueDelay = 1 * time.Second
+	RetryRequeueDelay        = 1 * time.Minute
+
+	AppliedCondition      = "Applied"
+	AppliedReasonApplied  = "Applied"
+	AppliedReasonInvalid  = "Invalid"
+	AppliedReasonRejected = "Rejected"
+
+	Stop     = true
+	Continue = false
+)
+
+type ConfigState string
+
+// Sync applies the document once per generation of the resource. Deleting
+// the resource leaves the applied configuration in place.
+func (r *Reconciler) Sync(ctx context.Context, config *ydbv1alpha1.DynamicConfig) (ctrl.Result, error) {
+	if config.Status.State == string(Applied) && config.Status.ObservedGeneration == config.Generation {
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	metadata, stop, result, err := r.validate(ctx, config)
+	if stop {
+		return r.observe(config, "validate", result, err)
+	}
+	storage, stop, result, err := r.waitForStorage(ctx, config)
+	if stop {
+		return r.observe(config, "waitForStorage", result, err)
+	}
+	stop, result, err = r.apply(ctx, config, storage, metadata)
+	if stop {
+		return r.observe(config, "apply", result, err)
+	}
+	return r.observe(config, "", result, err)
+}
+
+// observe exports the step that ended the sync and the config state
+func (r *Reconciler) observe(
+	config *ydbv1alpha1.DynamicConfig,
+	step string,
+	result ctrl.Result,
+	err error,
+) (ctrl.Result, error) {
+	operatormetrics.ObserveStep(operatormetrics.KindDynamicConfig, config.Namespace, config.Name, step, err)
+	operatormetrics.SetState(operatormetrics.KindDynamicConfig, config.Namespace, config.Name, config.Status.State)
+	return result, err
+}
+
+// validate repeats the check of the webhook, which may be disabled. An
+// invalid document is not retried until the spec changes.
+func (r *Reconciler) validate(
+	ctx context.Context,
+	config *ydbv1alpha1.DynamicConfig,
+) (*ydbv1alpha1.DynamicConfigMetadata, bool, ctrl.Result, error) {
+	r.Log.Info("running step validate")
+
+	metadata, err := ydbv1alpha1.ParseDynamicConfigMetadata(config.Spec.Config)
+	if err == nil {
+		return metadata, Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Recorder.Event(config, corev1.EventTypeWarning, events.ReasonDynamicConfigInvalid, err.Error())
+	stop, result, updateErr := r.setFailed(ctx, config, AppliedReasonInvalid, err.Error())
+	if updateErr != nil {
+		return nil, stop, result, updateErr
+	}
+	return nil, Stop, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) waitForStorage(
+	ctx context.Context,
+	config *ydbv1alpha1.DynamicConfig,
+) (*resources.StorageClusterBuilder, bool, ctrl.Result, error) {
+	r.Log.Info("running step waitForStorage")
+
+	namespace := config.Spec.StorageRef.Namespace
+	if namespace == "" {
+		namespace = config.Namespace
+	}
+	storageCr := &ydbv1alpha1.Storage{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      config.Spec.StorageRef.Name,
+		Namespace: namespace,
+	}, storageCr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Recorder.Event(
+				config,
+				corev1.EventTypeWarning,
+				events.ReasonDynamicConfigWaitingForStorage,
+				fmt.Sprintf("Storage (%s/%s) not found.", config.Spec.StorageRef.Name, namespace),
+			)
+			return nil, Stop, ctrl.Result{Requeue: true}, nil
+		}
+		return nil, Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if storageCr.Status.State != "Ready" {
+		r.Recorder.Event(
+			config,
+			corev1.EventTypeWarning,
+			events.ReasonDynamicConfigWaitingForStorage,
+			fmt.Sprintf(
+				"Referenced storage cluster (%s, %s) in a bad state: %s != Ready",
+				storageCr.Name,
+				storageCr.Namespace,
+				storageCr.Status.State,
+			),
+		)
+		return nil, Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	storage := resources.NewCluster(storageCr)
+	return &storage, Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// apply pushes the document to the console. YDB bumps the version of the
+// configuration on every replace, the document has to carry the current
+// one, so the applied version is the one of the document plus one.
+func (r *Reconciler) apply(
+	ctx context.Context,
+	config *ydbv1alpha1.DynamicConfig,
+	storage *resources.StorageClusterBuilder,
+	metadata *ydbv1alpha1.DynamicConfigMetadata,
+) (bool, ctrl.Result, error) {
+	r.Log.Info("running step apply")
+
+	console := cms.DynamicConfig{
+		StorageEndpoint:      storage.GetGRPCEndpoint(),
+		UseGrpcSecureChannel: storage.Spec.Service.GRPC.TLSConfiguration.Enabled,
+	}
+	if err := console.Replace(ctx, config.Spec.Config, config.Spec.AllowUnknownFields); err != nil {
+		message := fmt.Sprintf("Failed to apply dynamic config version %d: %s", metadata.Version, err)
+		r.Recorder.Event(config, corev1.EventTypeWarning, events.ReasonDynamicConfigFailed, message)
+		if stop, result, updateErr := r.setFailed(ctx, config, AppliedReasonRejected, message); updateErr != nil {
+			return stop, result, updateErr
+		}
+		return Stop, ctrl.Result{RequeueAfter: RetryRequeueDelay}, nil
+	}
+
+	config.Status.State = string(Applied)
+	config.Status.ObservedGeneration = config.Generation
+	config.Status.AppliedVersion = metadata.Version + 1
+	config.Status.Message = ""
+	message := fmt.Sprintf("Applied dynamic config, version %d", config.Status.AppliedVersion)
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:    AppliedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  AppliedReasonApplied,
+		Message: message,
+	})
+	r.Recorder.Event(config, corev1.EventTypeNormal, events.ReasonDynamicConfigApplied, message)
+	return r.setState(ctx, config)
+}
+
+func (r *Reconciler) setFailed(
+	ctx context.Context,
+	config *ydbv1alpha1.DynamicConfig,
+	reason string,
+	message string,
+) (bool, ctrl.Result, error) {
+	config.Status.State = string(Failed)
+	config.Status.Message = message
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:    AppliedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	return r.setState(ctx, config)
+}
+
+func (r *Reconciler) setState(
+	ctx context.Context,
+	config *ydbv1alpha1.DynamicConfig,
+) (bool, ctrl.Result, error) {
+	configCr := &ydbv1alpha1.DynamicConfig{}
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: config.Namespace,
+		Name:      config.Name,
+	}, configCr)
+	if err != nil {
+		r.Recorder.Event(configCr, corev1.EventTypeWarning, events.ReasonControllerError, "Failed fetching CR before status update")
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	configCr.Status = config.Status
+
+	err = r.Status().Update(ctx, configCr)
+	if err != nil {
+		r.Recorder.Event(configCr, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed setting status: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
@@ -0,0 +1,200 @@
+package fleetreport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+)
+
+const (
+	DefaultInterval = 5 * time.Minute
+
+	// StalledLimit caps the stalled resources listed in a report, all of
+	// them are counted
+	StalledLimit = 50
+
+	// stalledCondition is set by the Storage and Database controllers
+	stalledCondition = "Stalled"
+
+	unknownState   = "Unknown"
+	unknownVersion = "unknown"
+)
+
+// Reconciler regenerates the status of FleetReport objects
+type Reconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+}
+
+//+kubebuilder:rbac:groups=ydb.tech,resources=fleetreports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ydb.tech,resources=fleetreports/status,verbs=get;update;patch
+
+// Reconcile lists the Storages and Databases in the namespaces of the
+// report and replaces its status with their summary
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log = log.FromContext(ctx)
+
+	report := &ydbv1alpha1.FleetReport{}
+	err := r.Get(ctx, req.NamespacedName, report)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.Log.Info("fleetreport resources not found")
+			return ctrl.Result{Requeue: false}, nil
+		}
+		r.Log.Error(err, "unexpected Get error")
+		return ctrl.Result{}, err
+	}
+
+	interval := DefaultInterval
+	if report.Spec.Interval != nil && report.Spec.Interval.Duration > 0 {
+		interval = report.Spec.Interval.Duration
+	}
+	generated := report.Status.GeneratedAt
+	if generated != nil && report.Status.ObservedGeneration == report.Generation && time.Since(generated.Time) < interval {
+		return ctrl.Result{RequeueAfter: interval - time.Since(generated.Time)}, nil
+	}
+
+	storages, databases, err := r.listMembers(ctx, report.Spec.Namespaces)
+	if err != nil {
+		r.Recorder.Event(report, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list resources: %s", err))
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	report.Status = ydbv1alpha1.FleetReportStatus{
+		GeneratedAt:        &now,
+		ObservedGeneration: report.Generation,
+		Storages:           summarize(storages),
+		Databases:          summarize(databases),
+	}
+	if err := r.Status().Update(ctx, report); err != nil {
+		r.Recorder.Event(report, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed setting status: %s", err))
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// member is what a report needs of a Storage or Database
+type member struct {
+	namespace  string
+	name       string
+	state      string
+	version    string
+	since      *metav1.Time
+	conditions []metav1.Condition
+}
+
+func (r *Reconciler) listMembers(ctx context.Context, namespaces []string) ([]member, []member, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var storages, databases []member
+	for _, namespace := range namespaces {
+		storageList := &ydbv1alpha1.StorageList{}
+		if err := r.List(ctx, storageList, client.InNamespace(namespace)); err != nil {
+			return nil, nil, err
+		}
+		for _, storage := range storageList.Items {
+			storages = append(storages, member{
+				namespace:  storage.Namespace,
+				name:       storage.Name,
+				state:      storage.Status.State,
+				version:    autoupdate.ImageTag(storage.Spec.Image.Name),
+				since:      storage.Status.StateTransitionTime,
+				conditions: storage.Status.Conditions,
+			})
+		}
+
+		databaseList := &ydbv1alpha1.DatabaseList{}
+		if err := r.List(ctx, databaseList, client.InNamespace(namespace)); err != nil {
+			return nil, nil, err
+		}
+		for _, database := range databaseList.Items {
+			// The version the nodes report is preferred over the one of the
+			// image, they differ while the database is updated
+			version := database.Status.Version
+			if version == "" {
+				version = autoupdate.ImageTag(database.Spec.Image.Name)
+			}
+			databases = append(databases, member{
+				namespace:  database.Namespace,
+				name:       database.Name,
+				state:      database.Status.State,
+				version:    version,
+				since:      database.Status.StateTransitionTime,
+				conditions: database.Status.Conditions,
+			})
+		}
+	}
+	return storages, databases, nil
+}
+
+func summarize(members []member) ydbv1alpha1.FleetSummary {
+	summary := ydbv1alpha1.FleetSummary{
+		Total:    int32(len(members)),
+		States:   map[string]int32{},
+		Versions: map[string]int32{},
+	}
+	for _, m := range members {
+		state := m.state
+		if state == "" {
+			state = unknownState
+		}
+		summary.States[state]++
+		version := m.version
+		if version == "" {
+			version = unknownVersion
+		}
+		summary.Versions[version]++
+
+		condition := meta.FindStatusCondition(m.conditions, stalledCondition)
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		summary.StalledCount++
+		summary.Stalled = append(summary.Stalled, ydbv1alpha1.FleetStalledResource{
+			Namespace: m.namespace,
+			Name:      m.name,
+			State:     m.state,
+			Since:     m.since,
+			Message:   condition.Message,
+		})
+	}
+
+	sort.SliceStable(summary.Stalled, func(i, j int) bool {
+		a, b := summary.Stalled[i].Since, summary.Stalled[j].Since
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.Before(b)
+	})
+	if len(summary.Stalled) > StalledLimit {
+		summary.Stalled = summary.Stalled[:StalledLimit]
+	}
+	return summary
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ydbv1alpha1.FleetReport{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,273 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	Pending   OperationState = "Pending"
+	Running   OperationState = "Running"
+	Succeeded OperationState = "Succeeded"
+	Failed    OperationState = "Failed"
+
+	StatusUpdateRequeueDelay = 1 * time.Second
+	RetryRequeueDelay        = 30 * time.Second
+
+	DefaultMaxAttempts = 3
+
+	// Keep status readable, ydbd output may be large
+	maxMessageLength = 4096
+
+	Stop     = true
+	Continue = false
+)
+
+var ErrMissingOperationParameters = errors.New("parameters for the operation kind are not set")
+
+type OperationState string
+
+func (r *Reconciler) Sync(ctx context.Context, operation *ydbv1alpha1.Operation) (ctrl.Result, error) {
+	// Operations are executed once, finished ones are kept for audit only
+	if operation.Status.State == string(Succeeded) || operation.Status.State == string(Failed) {
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	storage, stop, result, err := r.waitForStorage(ctx, operation)
+	if stop {
+		return r.observe(operation, "waitForStorage", result, err)
+	}
+	stop, result, err = r.execute(ctx, operation, storage)
+	if stop {
+		return r.observe(operation, "execute", result, err)
+	}
+	return r.observe(operation, "", result, err)
+}
+
+// observe exports the step that ended the sync and the operation state
+func (r *Reconciler) observe(
+	operation *ydbv1alpha1.Operation,
+	step string,
+	result ctrl.Result,
+	err error,
+) (ctrl.Result, error) {
+	operatormetrics.ObserveStep(operatormetrics.KindOperation, operation.Namespace, operation.Name, step, err)
+	operatormetrics.SetState(operatormetrics.KindOperation, operation.Namespace, operation.Name, operation.Status.State)
+	return result, err
+}
+
+func (r *Reconciler) waitForStorage(
+	ctx context.Context,
+	operation *ydbv1alpha1.Operation,
+) (*resources.StorageClusterBuilder, bool, ctrl.Result, error) {
+	r.Log.Info("running step waitForStorage")
+
+	namespace := operation.Spec.StorageRef.Namespace
+	if namespace == "" {
+		namespace = operation.Namespace
+	}
+	storageCr := &ydbv1alpha1.Storage{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      operation.Spec.StorageRef.Name,
+		Namespace: namespace,
+	}, storageCr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Recorder.Event(
+				operation,
+				corev1.EventTypeWarning,
+				events.ReasonOperationWaitingForStorage,
+				fmt.Sprintf("Storage (%s/%s) not found.", operation.Spec.StorageRef.Name, namespace),
+			)
+			return nil, Stop, ctrl.Result{Requeue: true}, nil
+		}
+		return nil, Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if storageCr.Status.State != "Ready" {
+		r.Recorder.Event(
+			operation,
+			corev1.EventTypeWarning,
+			events.ReasonOperationWaitingForStorage,
+			fmt.Sprintf(
+				"Referenced storage cluster (%s, %s) in a bad state: %s != Ready",
+				storageCr.Name,
+				storageCr.Namespace,
+				storageCr.Status.State,
+			),
+		)
+		return nil, Stop, ctrl.Result{Requeue: true}, nil
+	}
+
+	storage := resources.NewCluster(storageCr)
+	return &storage, Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// execute runs the command of the operation in the first storage pod. An
+// attempt is counted in status before the command runs, in the next
+// reconcile, so the command never runs more times than status.attempts
+// tells, whatever happens to the status update. An attempt interrupted by
+// an operator restart is run again under the same number.
+func (r *Reconciler) execute(
+	ctx context.Context,
+	operation *ydbv1alpha1.Operation,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	r.Log.Info("running step execute")
+
+	cmd, err := buildCommand(operation, storage)
+	if err != nil {
+		r.Recorder.Event(operation, corev1.EventTypeWarning, events.ReasonOperationFailed, err.Error())
+		operation.Status.State = string(Failed)
+		operation.Status.Message = err.Error()
+		now := metav1.Now()
+		operation.Status.CompletionTime = &now
+		return r.setState(ctx, operation)
+	}
+
+	if operation.Status.State != string(Running) {
+		if operation.Status.StartTime == nil {
+			now := metav1.Now()
+			operation.Status.StartTime = &now
+		}
+		operation.Status.State = string(Running)
+		operation.Status.Attempts++
+		return r.setState(ctx, operation)
+	}
+
+	podName := fmt.Sprintf("%s-0", storage.Name)
+	r.Recorder.Event(
+		operation,
+		corev1.EventTypeNormal,
+		events.ReasonOperationRunning,
+		fmt.Sprintf("Executing %s on %s/%s, attempt %d", operation.Spec.Kind, storage.Namespace, podName, operation.Status.Attempts),
+	)
+	stdout, stderr, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, podName, "ydb-storage", cmd)
+	if err != nil {
+		operation.Status.Message = truncate(err.Error())
+
+		maxAttempts := operation.Spec.MaxAttempts
+		if maxAttempts == 0 {
+			maxAttempts = DefaultMaxAttempts
+		}
+		if operation.Status.Attempts < maxAttempts {
+			r.Recorder.Event(operation, corev1.EventTypeWarning, events.ReasonOperationRetrying, operation.Status.Message)
+			operation.Status.State = string(Pending)
+			if _, _, updateErr := r.setState(ctx, operation); updateErr != nil {
+				return Stop, ctrl.Result{Requeue: true}, updateErr
+			}
+			return Stop, ctrl.Result{RequeueAfter: RetryRequeueDelay}, nil
+		}
+
+		r.Recorder.Event(operation, corev1.EventTypeWarning, events.ReasonOperationFailed, operation.Status.Message)
+		operation.Status.State = string(Failed)
+		now := metav1.Now()
+		operation.Status.CompletionTime = &now
+		return r.setState(ctx, operation)
+	}
+
+	r.Recorder.Event(operation, corev1.EventTypeNormal, events.ReasonOperationSucceeded, fmt.Sprintf("%s completed", operation.Spec.Kind))
+	operation.Status.State = string(Succeeded)
+	operation.Status.Message = truncate(strings.TrimSpace(stdout + stderr))
+	now := metav1.Now()
+	operation.Status.CompletionTime = &now
+	return r.setState(ctx, operation)
+}
+
+func buildCommand(operation *ydbv1alpha1.Operation, storage *resources.StorageClusterBuilder) ([]string, error) {
+	ydbd := fmt.Sprintf("%s/%s", ydbv1alpha1.BinariesDir, ydbv1alpha1.DaemonBinaryName)
+	cmd := []string{ydbd}
+	if storage.Spec.Service.GRPC.TLSConfiguration != nil && storage.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		cmd = append(cmd, "-s", storage.GetGRPCEndpointWithProto())
+	}
+
+	switch operation.Spec.Kind {
+	case ydbv1alpha1.OperationDrainNode:
+		if operation.Spec.DrainNode == nil {
+			return nil, fmt.Errorf("%w: drainNode", ErrMissingOperationParameters)
+		}
+		return append(cmd, "admin", "node", fmt.Sprint(operation.Spec.DrainNode.NodeID), "drain"), nil
+	case ydbv1alpha1.OperationReassignGroups:
+		if operation.Spec.ReassignGroups == nil {
+			return nil, fmt.Errorf("%w: reassignGroups", ErrMissingOperationParameters)
+		}
+		var request strings.Builder
+		for _, vdisk := range operation.Spec.ReassignGroups.VDisks {
+			fmt.Fprintf(
+				&request,
+				"Command { ReassignGroupDisk { GroupId: %d GroupGeneration: %d FailRealmIdx: %d FailDomainIdx: %d VDiskIdx: %d } } ",
+				vdisk.GroupID,
+				vdisk.GroupGeneration,
+				vdisk.FailRealmIdx,
+				vdisk.FailDomainIdx,
+				vdisk.VDiskIdx,
+			)
+		}
+		return append(cmd, "admin", "blobstorage", "config", "invoke", "--proto", request.String()), nil
+	case ydbv1alpha1.OperationSetConfig:
+		if operation.Spec.SetConfig == nil {
+			return nil, fmt.Errorf("%w: setConfig", ErrMissingOperationParameters)
+		}
+		// `admin console execute` reads the request from a file, pass it
+		// through the shell as $0 to avoid quoting issues
+		requestFile := fmt.Sprintf("/tmp/operation-%s.txt", operation.UID)
+		script := fmt.Sprintf(
+			"printf '%%s' \"$0\" > %s && exec %s admin console execute --domain=%s --retry=10 %s",
+			requestFile,
+			strings.Join(cmd, " "),
+			storage.Spec.Domain,
+			requestFile,
+		)
+		return []string{"/bin/sh", "-c", script, operation.Spec.SetConfig.Request}, nil
+	default:
+		return nil, fmt.Errorf("unknown operation kind %q", operation.Spec.Kind)
+	}
+}
+
+func truncate(message string) string {
+	if len(message) > maxMessageLength {
+		return message[:maxMessageLength]
+	}
+	return message
+}
+
+func (r *Reconciler) setState(
+	ctx context.Context,
+	operation *ydbv1alpha1.Operation,
+) (bool, ctrl.Result, error) {
+	operationCr := &ydbv1alpha1.Operation{}
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: operation.Namespace,
+		Name:      operation.Name,
+	}, operationCr)
+	if err != nil {
+		r.Recorder.Event(operationCr, corev1.EventTypeWarning, events.ReasonControllerError, "Failed fetching CR before status update")
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	operationCr.Status = operation.Status
+
+	err = r.Status().Update(ctx, operationCr)
+	if err != nil {
+		r.Recorder.Event(operationCr, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed setting status: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
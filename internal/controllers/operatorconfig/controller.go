@@ -0,0 +1,92 @@
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+)
+
+const (
+	AppliedCondition     = "Applied"
+	AppliedReasonApplied = "Applied"
+	AppliedReasonIgnored = "Ignored"
+)
+
+// Reconciler loads the OperatorConfig into the settings Store
+type Reconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+
+	Settings *operatorconfig.Store
+}
+
+//+kubebuilder:rbac:groups=ydb.tech,resources=operatorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ydb.tech,resources=operatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the OperatorConfig named "default". Other objects are
+// marked as ignored, so a single config is in effect at any time.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log = log.FromContext(ctx)
+
+	config := &ydbv1alpha1.OperatorConfig{}
+	err := r.Get(ctx, req.NamespacedName, config)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if req.Name == ydbv1alpha1.OperatorConfigName {
+				r.Log.Info("operator config removed, using default settings")
+				r.Settings.Apply(nil)
+			}
+			return ctrl.Result{Requeue: false}, nil
+		}
+		r.Log.Error(err, "unexpected Get error")
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:    AppliedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  AppliedReasonApplied,
+		Message: "Settings are in effect",
+	}
+	if config.Name == ydbv1alpha1.OperatorConfigName {
+		r.Settings.Apply(config)
+		r.Log.Info("operator config applied", "generation", config.Generation)
+		r.Recorder.Event(config, corev1.EventTypeNormal, events.ReasonOperatorConfigApplied, fmt.Sprintf("Generation %d applied", config.Generation))
+	} else {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = AppliedReasonIgnored
+		condition.Message = fmt.Sprintf("Only the OperatorConfig named %q is used", ydbv1alpha1.OperatorConfigName)
+		r.Recorder.Event(config, corev1.EventTypeWarning, events.ReasonOperatorConfigIgnored, condition.Message)
+	}
+
+	meta.SetStatusCondition(&config.Status.Conditions, condition)
+	config.Status.ObservedGeneration = config.Generation
+	if err := r.Status().Update(ctx, config); err != nil {
+		r.Log.Error(err, "failed to update operator config status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: false}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ydbv1alpha1.OperatorConfig{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// AutoUpdateCheckInterval is how often a storage with an auto-update policy
+// is checked for a new release and an open maintenance window
+const AutoUpdateCheckInterval = 5 * time.Minute
+
+// handleAutoUpdate moves a Ready storage to the latest patch release of its
+// version published in the channel of the auto-update policy. Only the spec
+// image is changed, the pods are then replaced by the regular StatefulSet
+// rolling update, one at a time and behind the readiness gates.
+func (r *Reconciler) handleAutoUpdate(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	policy := storage.Spec.AutoUpdate
+	settings := r.Settings.Get()
+	if policy == nil || !policy.Enabled || settings.VersionManifestURL == "" || r.Versions == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleAutoUpdate")
+
+	if storage.Status.State != string(Ready) || !autoupdate.InWindow(policy.MaintenanceWindow, time.Now()) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	versions, err := r.Versions.Versions(ctx, settings.VersionManifestURL, settings.VersionManifestRefreshInterval, policy.Channel)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonAutoUpdateFailed, fmt.Sprintf("Failed to get released versions: %s", err))
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	current := autoupdate.ImageTag(storage.Spec.Image.Name)
+	next, ok := autoupdate.NextPatch(current, versions, "")
+	if !ok {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	storageCr := &ydbv1alpha1.Storage{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to get Storage: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	patch := client.MergeFrom(storageCr.DeepCopy())
+	storageCr.Spec.Image.Name = autoupdate.WithTag(storageCr.Spec.Image.Name, next)
+	if storageCr.Spec.YDBVersion != "" {
+		storageCr.Spec.YDBVersion = next
+	}
+	if err := r.Patch(ctx, storageCr, patch); err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonAutoUpdateFailed, fmt.Sprintf("Failed to update image to %s: %s", next, err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	msg := fmt.Sprintf("Version %s updated to %s", current, next)
+	r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonAutoUpdated, msg)
+	storage.Status.History = resources.AppendHistory(
+		storage.Status.History,
+		resources.HistoryActionAutoUpdated,
+		resources.HistoryOutcomeSucceeded,
+		storageCr.Generation,
+		msg,
+		time.Now(),
+	)
+	return r.setState(ctx, storage)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	ConnectionCheckRequeueDelay = 15 * time.Second
+
+	ConnectionVerifiedCondition    = "ConnectionVerified"
+	ConnectionVerifiedReasonPassed = "Passed"
+	ConnectionVerifiedReasonFailed = "Failed"
+)
+
+// checkConnection is the smoke test a storage passes before it becomes
+// Ready. Ready pods don't prove clients can reach them: a Service selector
+// not matching the pods or a certificate not covering the Service name
+// only shows when tenants are initialized. The check connects through the
+// gRPC Service with the CA of the storage, and keeps the reconcile waiting
+// until it succeeds.
+func (r *Reconciler) checkConnection(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step checkConnection")
+
+	condition := metav1.Condition{
+		Type:    ConnectionVerifiedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  ConnectionVerifiedReasonPassed,
+		Message: fmt.Sprintf("Connected to %s", storage.GetGRPCEndpointWithProto()),
+	}
+	ca, err := r.grpcCertificateAuthority(ctx, storage)
+	if err == nil {
+		err = healthcheck.CheckConnection(ctx, storage, ca)
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ConnectionVerifiedReasonFailed
+		condition.Message = fmt.Sprintf("Failed to connect to %s: %s", storage.GetGRPCEndpointWithProto(), err)
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageConnectionFailed, condition.Message)
+	}
+
+	current := meta.FindStatusCondition(storage.Status.Conditions, ConnectionVerifiedCondition)
+	if current == nil || current.Status != condition.Status || current.Message != condition.Message {
+		meta.SetStatusCondition(&storage.Status.Conditions, condition)
+		if _, _, updateErr := r.setState(ctx, storage); updateErr != nil {
+			return Stop, ctrl.Result{Requeue: true}, updateErr
+		}
+	}
+	if err != nil {
+		return Stop, ctrl.Result{RequeueAfter: ConnectionCheckRequeueDelay}, nil
+	}
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// grpcCertificateAuthority returns the CA the certificate of the gRPC
+// Service is issued by, nil when TLS is off or the CA is not specified and
+// the system store has to be trusted
+func (r *Reconciler) grpcCertificateAuthority(ctx context.Context, storage *resources.StorageClusterBuilder) ([]byte, error) {
+	tls := storage.Spec.Service.GRPC.TLSConfiguration
+	if tls == nil || !tls.Enabled {
+		return nil, nil
+	}
+
+	name, key := resources.TLSSecretName(storage.Name), ""
+	if !tls.OperatorManaged {
+		if tls.CertificateAuthority.Name == "" {
+			return nil, nil
+		}
+		name, key = tls.CertificateAuthority.Name, tls.CertificateAuthority.Key
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: storage.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the CA secret %s: %w", name, err)
+	}
+	if tls.OperatorManaged {
+		return resources.TLSCertificateAuthority(secret), nil
+	}
+	return secret.Data[key], nil
+}
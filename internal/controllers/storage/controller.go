@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -12,12 +14,18 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/autoupdate"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
 // Reconciler reconciles a Storage object
@@ -28,7 +36,16 @@ type Reconciler struct {
 	Recorder record.EventRecorder
 	Log      logr.Logger
 
+	// Settings are the operator-wide settings, reloaded from OperatorConfig
+	Settings *operatorconfig.Store
+
+	// Versions provides the releases rolled by auto-update policies. Nil
+	// disables auto-updates.
+	Versions *autoupdate.Source
+
 	WithServiceMonitors bool
+
+	childDeletions *resources.ChildDeletions
 }
 
 //+kubebuilder:rbac:groups=ydb.tech,resources=storages,verbs=get;list;watch;create;update;patch;delete
@@ -39,9 +56,15 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors/status,verbs=get;update;patch
 
@@ -55,12 +78,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("storage resources not found")
+			operatormetrics.Forget(operatormetrics.KindStorage, req.Namespace, req.Name)
+			r.childDeletions.Forget(req.NamespacedName)
 			return ctrl.Result{Requeue: false}, nil
 		}
 		r.Log.Error(err, "unexpected Get error")
-		return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		return ctrl.Result{Requeue: true}, err
 	}
+	start := time.Now()
 	result, err := r.Sync(ctx, storage)
+	operatormetrics.ObserveReconcile(operatormetrics.KindStorage, req.Namespace, req.Name, time.Since(start), err)
 	if err != nil {
 		r.Log.Error(err, "unexpected Sync error")
 	}
@@ -73,7 +100,13 @@ func ignoreDeletionPredicate() predicate.Predicate {
 			// Ignore updates to CR status in which case metadata.Generation does not change
 			_, isService := e.ObjectOld.(*corev1.Service)
 
-			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || isService
+			// Decommission, disaster recovery and credentials rotation are
+			// requested with annotations
+			_, isStorage := e.ObjectOld.(*ydbv1alpha1.Storage)
+			annotationsChanged := isStorage &&
+				!reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || isService || annotationsChanged
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// Evaluates to false if the object has been confirmed deleted.
@@ -84,17 +117,22 @@ func ignoreDeletionPredicate() predicate.Predicate {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	controller := ctrl.NewControllerManagedBy(mgr).For(&ydbv1alpha1.Storage{})
+	r.childDeletions = resources.NewChildDeletions()
+	deleted := builder.WithPredicates(r.childDeletions.Predicate("Storage"))
+
+	options := controller.Options{RateLimiter: r.Settings.Get().RequeueRateLimiter()}
+	controller := ctrl.NewControllerManagedBy(mgr).For(&ydbv1alpha1.Storage{}).WithOptions(options)
 
 	if r.WithServiceMonitors {
 		controller = controller.
-			Owns(&monitoringv1.ServiceMonitor{})
+			Owns(&monitoringv1.ServiceMonitor{}, deleted)
 	}
 
 	controller = controller.
-		Owns(&corev1.Service{}).
-		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.ConfigMap{})
+		Owns(&corev1.Service{}, deleted).
+		Owns(&appsv1.StatefulSet{}, deleted).
+		Owns(&appsv1.Deployment{}, deleted).
+		Owns(&corev1.ConfigMap{}, deleted)
 
 	return controller.WithEventFilter(ignoreDeletionPredicate()).
 		Complete(r)
@@ -0,0 +1,368 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	DecommissionPhaseValidating       = "Validating"
+	DecommissionPhaseDecommitting     = "Decommitting"
+	DecommissionPhaseWaitingForVDisks = "WaitingForVDisks"
+	DecommissionPhaseShrinking        = "Shrinking"
+	DecommissionPhaseCompleted        = "Completed"
+	DecommissionPhaseRejected         = "Rejected"
+
+	NodesDecommissionedCondition        = "NodesDecommissioned"
+	NodesDecommissionedReasonInProgress = ReasonInProgress
+	NodesDecommissionedReasonUnsafe     = "Unsafe"
+	NodesDecommissionedReasonCompleted  = ReasonCompleted
+
+	DecommissionRequeueDelay = 15 * time.Second
+
+	// Decommit statuses of a drive: a drive in use, and one the VDisks are
+	// moved off and no groups are placed on
+	DriveDecommitNone     = "DECOMMIT_NONE"
+	DriveDecommitImminent = "DECOMMIT_IMMINENT"
+)
+
+// handleDecommission removes storage pods for good. It is started by setting
+// the ydb.tech/decommission-nodes annotation to the ordinals of the pods and
+// goes through the phases below, keeping the progress in
+// status.decommission and the NodesDecommissioned condition:
+//
+//   - Validating: the removal is rejected when it is unsafe, i.e. the pods
+//     are not the last ones of the StatefulSet, the rest would be too few
+//     for the erasure, they host the static group, the hosts are listed in
+//     the configuration or the self check is not GOOD
+//   - Decommitting: the drives of the nodes are marked DECOMMIT_IMMINENT, so
+//     the BS controller moves their VDisks to the other nodes
+//   - WaitingForVDisks: the nodes have to run no VDisks
+//   - Shrinking: spec.nodes is decreased, which regenerates the hosts of the
+//     static config and scales the StatefulSet down
+//
+// The annotation is removed once the decommission completes or is rejected.
+// A StatefulSet only removes its last pods, so re-mapping the ordinals of
+// the remaining pods is not supported.
+func (r *Reconciler) handleDecommission(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	if _, requested := storage.Annotations[ydbv1alpha1.DecommissionAnnotation]; !requested {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDecommission")
+
+	// A finished decommission is kept in status until the next one is requested
+	status := storage.Status.Decommission
+	if status == nil || status.Phase == DecommissionPhaseCompleted || status.Phase == DecommissionPhaseRejected {
+		storage.Status.Decommission = &ydbv1alpha1.DecommissionStatus{
+			Phase:     DecommissionPhaseValidating,
+			StartTime: metav1.Now(),
+		}
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    NodesDecommissionedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  NodesDecommissionedReasonInProgress,
+			Message: "Decommission requested",
+		})
+		r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonStorageDecommission, "Decommission requested")
+		return r.setState(ctx, storage)
+	}
+
+	switch status.Phase {
+	case DecommissionPhaseValidating:
+		return r.validateDecommission(ctx, storage)
+	case DecommissionPhaseDecommitting:
+		return r.decommitDrives(ctx, storage)
+	case DecommissionPhaseWaitingForVDisks:
+		return r.waitForMovedVDisks(ctx, storage)
+	default:
+		return r.shrinkStorage(ctx, storage)
+	}
+}
+
+func (r *Reconciler) validateDecommission(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	ordinals, err := parseDecommissionOrdinals(storage.Annotations[ydbv1alpha1.DecommissionAnnotation], storage.TotalNodes())
+	if err != nil {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf("Invalid annotation %s: %s", ydbv1alpha1.DecommissionAnnotation, err))
+	}
+
+	count := int32(len(ordinals))
+	if ordinals[0] != storage.TotalNodes()-count {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf(
+			"Only the last pods of the StatefulSet can be removed, expected ordinals %d-%d",
+			storage.TotalNodes()-count,
+			storage.TotalNodes()-1,
+		))
+	}
+	if storage.Spec.Nodes-count < ydbv1alpha1.MinNodesForErasure(storage.Spec.Erasure) {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf(
+			"Erasure %s requires at least %d storage nodes, %d would be left",
+			storage.Spec.Erasure,
+			ydbv1alpha1.MinNodesForErasure(storage.Spec.Erasure),
+			storage.Spec.Nodes-count,
+		))
+	}
+	if len(storage.GetBlockDevicePaths(0)) != len(storage.Spec.DataStore) {
+		return r.rejectDecommission(ctx, storage, "Data stores in Filesystem mode are not supported")
+	}
+
+	configured, err := configuration.HostsConfigured(storage.Unwrap())
+	if err != nil {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	if configured {
+		return r.rejectDecommission(ctx, storage, "The configuration lists the hosts itself, they have to be removed there")
+	}
+
+	staticNodes, err := configuration.StaticGroupNodeIDs(storage.Unwrap())
+	if err != nil {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	removed := map[uint32]bool{}
+	for _, ordinal := range ordinals {
+		for _, id := range podNodeIDs(storage, ordinal) {
+			removed[id] = true
+		}
+	}
+	for _, id := range staticNodes {
+		if removed[uint32(id)] {
+			return r.rejectDecommission(ctx, storage, fmt.Sprintf("Node %d hosts the static group, which can't be moved", id))
+		}
+	}
+
+	result, err := healthcheck.GetSelfCheckResult(ctx, storage)
+	if err != nil {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf("SelfCheck failed: %s", err))
+	}
+	if result.SelfCheckResult != Ydb_Monitoring.SelfCheck_GOOD {
+		return r.rejectDecommission(ctx, storage, fmt.Sprintf(
+			"SelfCheck result is %s, the groups may not tolerate the removal",
+			result.SelfCheckResult,
+		))
+	}
+
+	var pods []string
+	for _, ordinal := range ordinals {
+		pods = append(pods, fmt.Sprintf("%s-%d", storage.Name, ordinal))
+	}
+	storage.Status.Decommission.Pods = pods
+	r.recordDecommissionStep(storage, DecommissionPhaseDecommitting, fmt.Sprintf("Removing pods %s", strings.Join(pods, ", ")))
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) decommitDrives(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	podName := fmt.Sprintf("%s-0", storage.Name)
+	for _, pod := range storage.Status.Decommission.Pods {
+		for j := 0; j < configuration.NodesPerPod(storage.Unwrap()); j++ {
+			for _, path := range storage.GetBlockDevicePaths(j) {
+				cmd := driveDecommitCommand(storage, pod, ydbv1alpha1.InterconnectPort+j, path, DriveDecommitImminent)
+				if _, _, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, podName, "ydb-storage", cmd); err != nil {
+					r.Recorder.Event(
+						storage,
+						corev1.EventTypeWarning,
+						events.ReasonStorageDecommission,
+						fmt.Sprintf("Failed to decommit drive %s of pod %s: %s", path, pod, err),
+					)
+					return Stop, ctrl.Result{}, err
+				}
+			}
+		}
+		r.Log.Info("decommitted drives", "pod", pod)
+	}
+
+	r.recordDecommissionStep(storage, DecommissionPhaseWaitingForVDisks, "Drives are decommitted, waiting for the VDisks to move")
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) waitForMovedVDisks(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	status := storage.Status.Decommission
+	vdisks := int32(0)
+	for _, pod := range status.Pods {
+		for _, id := range podNodeIDs(storage, podOrdinal(pod)) {
+			count, err := cms.NodeVDisks(ctx, storage.GetStatusEndpoint(), id)
+			if err != nil {
+				r.Log.Error(err, "failed to get node vdisks", "pod", pod, "node", id)
+				return Stop, ctrl.Result{RequeueAfter: DecommissionRequeueDelay}, nil
+			}
+			vdisks += count
+		}
+	}
+
+	if vdisks > 0 {
+		if vdisks != status.VDisks {
+			status.VDisks = vdisks
+			status.Message = fmt.Sprintf("%d VDisks left to move", vdisks)
+			if _, _, err := r.setState(ctx, storage); err != nil {
+				return Stop, ctrl.Result{Requeue: true}, err
+			}
+		}
+		return Stop, ctrl.Result{RequeueAfter: DecommissionRequeueDelay}, nil
+	}
+
+	status.VDisks = 0
+	r.recordDecommissionStep(storage, DecommissionPhaseShrinking, "All VDisks are moved")
+	return r.setState(ctx, storage)
+}
+
+// shrinkStorage decreases spec.nodes by the number of removed pods, unless
+// it is already done, and finishes the decommission
+func (r *Reconciler) shrinkStorage(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	status := storage.Status.Decommission
+	count := int32(len(status.Pods))
+	if count > 0 && podOrdinal(status.Pods[0]) < storage.TotalNodes() {
+		storageCr := &ydbv1alpha1.Storage{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		patch := client.MergeFrom(storageCr.DeepCopy())
+		storageCr.Spec.Nodes -= count
+		if err := r.Patch(ctx, storageCr, patch); err != nil {
+			r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to decrease spec.nodes: %s", err))
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+	}
+
+	message := fmt.Sprintf("Removed pods %s, %d storage nodes left", strings.Join(status.Pods, ", "), storage.Spec.Nodes)
+	r.recordDecommissionStep(storage, DecommissionPhaseCompleted, message)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:    NodesDecommissionedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  NodesDecommissionedReasonCompleted,
+		Message: message,
+	})
+	return r.finishDecommission(ctx, storage, resources.HistoryOutcomeSucceeded)
+}
+
+// rejectDecommission leaves the storage as it is and reports why the removal
+// is unsafe in the NodesDecommissioned condition
+func (r *Reconciler) rejectDecommission(ctx context.Context, storage *resources.StorageClusterBuilder, message string) (bool, ctrl.Result, error) {
+	storage.Status.Decommission.Phase = DecommissionPhaseRejected
+	storage.Status.Decommission.Message = message
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:    NodesDecommissionedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  NodesDecommissionedReasonUnsafe,
+		Message: message,
+	})
+	r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageDecommissionRejected, message)
+	return r.finishDecommission(ctx, storage, resources.HistoryOutcomeFailed)
+}
+
+// recordDecommissionStep moves the decommission to the next phase and logs
+// the step taken in status and in the events
+func (r *Reconciler) recordDecommissionStep(storage *resources.StorageClusterBuilder, next, message string) {
+	status := storage.Status.Decommission
+	r.Log.Info("decommission step", "phase", status.Phase, "message", message)
+	r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonStorageDecommission, fmt.Sprintf("%s: %s", status.Phase, message))
+	status.Phase = next
+	status.Message = message
+}
+
+func (r *Reconciler) finishDecommission(ctx context.Context, storage *resources.StorageClusterBuilder, outcome string) (bool, ctrl.Result, error) {
+	storage.Status.History = resources.AppendHistory(
+		storage.Status.History,
+		resources.HistoryActionNodesDecommissioned,
+		outcome,
+		storage.Generation,
+		storage.Status.Decommission.Message,
+		time.Now(),
+	)
+	if stop, result, err := r.setState(ctx, storage); err != nil {
+		return stop, result, err
+	}
+
+	storageCr := &ydbv1alpha1.Storage{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	patch := client.MergeFrom(storageCr.DeepCopy())
+	delete(storageCr.Annotations, ydbv1alpha1.DecommissionAnnotation)
+	if err := r.Patch(ctx, storageCr, patch); err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to remove annotation: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
+
+// parseDecommissionOrdinals returns the sorted pod ordinals listed in the
+// annotation value
+func parseDecommissionOrdinals(value string, pods int32) ([]int32, error) {
+	seen := map[int32]bool{}
+	var ordinals []int32
+	for _, item := range strings.Split(value, ",") {
+		ordinal, err := strconv.ParseInt(strings.TrimSpace(item), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a pod ordinal", item)
+		}
+		if ordinal < 0 || int32(ordinal) >= pods {
+			return nil, fmt.Errorf("pod ordinal %d is out of range 0-%d", ordinal, pods-1)
+		}
+		if !seen[int32(ordinal)] {
+			seen[int32(ordinal)] = true
+			ordinals = append(ordinals, int32(ordinal))
+		}
+	}
+	sort.Slice(ordinals, func(i, j int) bool { return ordinals[i] < ordinals[j] })
+	return ordinals, nil
+}
+
+func podOrdinal(pod string) int32 {
+	ordinal, err := strconv.ParseInt(pod[strings.LastIndex(pod, "-")+1:], 10, 32)
+	if err != nil {
+		return -1
+	}
+	return int32(ordinal)
+}
+
+// podNodeIDs returns the ids of the static nodes run by the pod,
+// as generated in the hosts of the configuration
+func podNodeIDs(storage *resources.StorageClusterBuilder, ordinal int32) []uint32 {
+	nodesPerPod := configuration.NodesPerPod(storage.Unwrap())
+	var ids []uint32
+	for j := 0; j < nodesPerPod; j++ {
+		ids = append(ids, uint32(int(ordinal)*nodesPerPod+j+1))
+	}
+	return ids
+}
+
+// driveDecommitCommand sets the decommit status of the drive of the static
+// node in the blobstorage config
+func driveDecommitCommand(storage *resources.StorageClusterBuilder, host string, port int, path, status string) []string {
+	cmd := []string{
+		fmt.Sprintf("%s/%s", ydbv1alpha1.BinariesDir, ydbv1alpha1.DaemonBinaryName),
+	}
+	if storage.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		cmd = append(
+			cmd,
+			"-s", storage.GetGRPCEndpointWithProto(),
+		)
+	}
+	return append(
+		cmd,
+		"admin", "blobstorage", "config", "invoke",
+		"--proto",
+		fmt.Sprintf(
+			`Command { UpdateDriveStatus { HostKey { Fqdn: "%s" IcPort: %d } Path: "%s" DecommitStatus: %s } }`,
+			host, port, path, status,
+		),
+	)
+}
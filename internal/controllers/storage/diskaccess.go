@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleDiskAccess records the disk access mode of the storage nodes in
+// status before the StatefulSet is built from it. Mode Auto asks the API
+// server whether a privileged pod is admitted to the namespace and falls
+// back to spec.diskAccess.fallback when it is not. The detected mode is
+// kept, the volumes of the StatefulSet depend on it.
+func (r *Reconciler) handleDiskAccess(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	if storage.Spec.DiskAccess == nil || storage.Spec.DiskAccess.Mode != ydbv1alpha1.DiskAccessAuto {
+		if mode := storage.DiskAccessMode(); storage.Status.DiskAccessMode != mode {
+			storage.Status.DiskAccessMode = mode
+			return r.setState(ctx, storage)
+		}
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if storage.Status.DiskAccessMode != "" {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDiskAccess")
+
+	admitted, reason, err := r.privilegedPodsAdmitted(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to check whether privileged pods are admitted: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	mode := ydbv1alpha1.DiskAccessPrivileged
+	msg := "Privileged pods are admitted, the storage nodes run privileged"
+	if !admitted {
+		mode = storage.Spec.DiskAccess.Fallback
+		if mode == "" {
+			mode = ydbv1alpha1.DiskAccessFileBacked
+		}
+		msg = fmt.Sprintf("Privileged pods are not admitted, the disks are accessed in %s mode: %s", mode, reason)
+	}
+	r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonStorageDiskAccessDetected, msg)
+	storage.Status.DiskAccessMode = mode
+	return r.setState(ctx, storage)
+}
+
+// privilegedPodsAdmitted creates a privileged pod in the namespace of the
+// storage in dry-run mode, so the Pod Security admission, policy webhooks
+// and any other admission plugin of the cluster all have their say. The
+// reason of the rejection is returned when the pod is not admitted.
+func (r *Reconciler) privilegedPodsAdmitted(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, string, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-disk-access-check", storage.Name),
+			Namespace: storage.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "ydb-storage",
+				Image: storage.Spec.Image.Name,
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.Bool(true),
+				},
+			}},
+			NodeSelector: storage.Spec.NodeSelector,
+			Tolerations:  storage.Spec.Tolerations,
+		},
+	}
+	err := r.Create(ctx, pod, client.DryRunAll)
+	switch {
+	case err == nil:
+		return true, "", nil
+	case apierrors.IsForbidden(err) || apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		return false, err.Error(), nil
+	default:
+		return false, "", err
+	}
+}
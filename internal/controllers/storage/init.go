@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
@@ -56,6 +58,9 @@ func (r *Reconciler) runInitScripts(
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step runInitScripts")
+	if err := chaos.Inject(ctx, storage, "runInitScripts"); err != nil {
+		return Stop, ctrl.Result{}, err
+	}
 	podName := fmt.Sprintf("%s-0", storage.Name)
 
 	if meta.IsStatusConditionTrue(storage.Status.Conditions, InitStorageStepCondition) {
@@ -65,31 +70,27 @@ func (r *Reconciler) runInitScripts(
 			Reason:  StorageInitializedReasonCompleted,
 			Message: "Storage initialized successfully",
 		})
+		storage.Status.History = resources.AppendHistory(
+			storage.Status.History,
+			resources.HistoryActionStorageInitialized,
+			resources.HistoryOutcomeSucceeded,
+			storage.Generation,
+			"",
+			time.Now(),
+		)
 		return r.setState(ctx, storage)
 	}
 
-	cmd := []string{
-		fmt.Sprintf("%s/%s", v1alpha1.BinariesDir, v1alpha1.DaemonBinaryName),
-	}
-	if storage.Spec.Service.GRPC.TLSConfiguration.Enabled {
-		cmd = append(
-			cmd,
-			"-s", storage.GetGRPCEndpointWithProto(),
-		)
+	cmd := blobstorageInitCommand(storage)
+	if err := chaos.Inject(ctx, storage, chaos.BlobstorageInit); err != nil {
+		return Stop, ctrl.Result{}, err
 	}
-	cmd = append(
-		cmd,
-		"admin", "blobstorage", "config", "init",
-		"--yaml-file",
-		fmt.Sprintf("%s/%s", v1alpha1.ConfigDir, v1alpha1.ConfigFileName),
-	)
-
 	stdout, _, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, podName, "ydb-storage", cmd)
 	if err != nil {
 		if mismatchItemConfigGenerationRegexp.MatchString(stdout) {
 			r.Log.Info("Storage is already initialized, continuing...")
 		} else {
-			return Stop, ctrl.Result{RequeueAfter: StorageInitializationRequeueDelay}, err
+			return Stop, ctrl.Result{}, err
 		}
 	}
 
@@ -101,3 +102,23 @@ func (r *Reconciler) runInitScripts(
 	})
 	return r.setState(ctx, storage)
 }
+
+// blobstorageInitCommand applies the blobstorage config of the mounted
+// configuration file, also used to restore the static group on recovery
+func blobstorageInitCommand(storage *resources.StorageClusterBuilder) []string {
+	cmd := []string{
+		fmt.Sprintf("%s/%s", v1alpha1.BinariesDir, v1alpha1.DaemonBinaryName),
+	}
+	if storage.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		cmd = append(
+			cmd,
+			"-s", storage.GetGRPCEndpointWithProto(),
+		)
+	}
+	return append(
+		cmd,
+		"admin", "blobstorage", "config", "init",
+		"--yaml-file",
+		fmt.Sprintf("%s/%s", v1alpha1.ConfigDir, v1alpha1.ConfigFileName),
+	)
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/dashboards"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleLinks publishes the links to the embedded UI and the dashboards of
+// the storage in status and annotations, so kubectl describe shows them
+func (r *Reconciler) handleLinks(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleLinks")
+
+	settings := r.Settings.Get()
+	links, err := dashboards.Render(settings.UIURLTemplate, settings.GrafanaURLTemplate, dashboards.Target{
+		Kind:      "Storage",
+		Namespace: storage.Namespace,
+		Name:      storage.Name,
+	})
+	if err != nil {
+		// A broken template must not block the reconcile
+		r.Log.Error(err, "failed to render links")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	if dashboards.Annotate(resources.CopyDict(storage.Annotations), links) {
+		storageCr := &ydbv1alpha1.Storage{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+		patch := client.MergeFrom(storageCr.DeepCopy())
+		if storageCr.Annotations == nil {
+			storageCr.Annotations = map[string]string{}
+		}
+		dashboards.Annotate(storageCr.Annotations, links)
+		if err := r.Patch(ctx, storageCr, patch); err != nil {
+			r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to update link annotations: %s", err))
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	if dashboards.Equal(storage.Status.Links, links) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	storage.Status.Links = links
+	return r.setState(ctx, storage)
+}
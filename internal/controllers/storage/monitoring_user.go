@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// handleMonitoringUser registers the isolated monitoring credentials as a
+// user of the root domain, and again whenever the Secret changes, e.g. on a
+// rotation. The user is dropped once the credentials are no longer
+// isolated. A failure is reported and retried on the next reconcile, the
+// steps after it are not held back.
+func (r *Reconciler) handleMonitoringUser(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	isolated := storage.Spec.Monitoring != nil && storage.Spec.Monitoring.IsolatedCredentials
+	if !isolated && storage.Status.MonitoringCredentialsVersion == "" {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	username := resources.MonitoringUsername(storage.Name)
+	if !isolated {
+		r.Log.Info("running step handleMonitoringUser")
+		err := monitoringUserClient(storage).Execute(ctx, fmt.Sprintf("DROP USER IF EXISTS %s;", username))
+		if err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				events.ReasonMonitoringUserFailed,
+				fmt.Sprintf("Failed to drop monitoring user %s: %s", username, err),
+			)
+			return Continue, ctrl.Result{Requeue: false}, nil
+		}
+		storage.Status.MonitoringCredentialsVersion = ""
+		return r.setState(ctx, storage)
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      resources.MonitoringSecretName(storage.Name),
+		Namespace: storage.Namespace,
+	}, secret)
+	if apierrors.IsNotFound(err) {
+		// Created by the next resources sync
+		return Continue, ctrl.Result{Requeue: false}, nil
+	} else if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to get monitoring Secret: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	if secret.ResourceVersion == storage.Status.MonitoringCredentialsVersion {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleMonitoringUser")
+
+	username, password, err := resources.MonitoringCredentials(secret)
+	if err == nil {
+		err = monitoringUserClient(storage).SetUserPassword(ctx, username, password)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonMonitoringUserFailed,
+			fmt.Sprintf("Failed to register monitoring user %s: %s", username, err),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		events.ReasonMonitoringUserApplied,
+		fmt.Sprintf("Monitoring user %s registered in domain %s", username, storage.Spec.Domain),
+	)
+	storage.Status.MonitoringCredentialsVersion = secret.ResourceVersion
+	return r.setState(ctx, storage)
+}
+
+func monitoringUserClient(storage *resources.StorageClusterBuilder) *scripting.Client {
+	tls := storage.Spec.Service.GRPC.TLSConfiguration
+	return &scripting.Client{
+		Endpoint:             storage.GetGRPCEndpoint(),
+		UseGrpcSecureChannel: tls != nil && tls.Enabled,
+		Database:             "/" + storage.Spec.Domain,
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleMountedObjects records in status the ConfigMaps and Secrets the
+// storage pods use and the hashes of their data. Secrets are not
+// watched, their changes are picked up with the periodic requeue.
+func (r *Reconciler) handleMountedObjects(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleMountedObjects")
+
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: storage.Name, Namespace: storage.Namespace}, statefulSet)
+	if apierrors.IsNotFound(err) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	var mounted []ydbv1alpha1.MountedObject
+	if err == nil {
+		mounted, err = resources.MountedObjects(ctx, r, storage.Namespace, statefulSet.Spec.Template.Spec)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to read the objects mounted into the pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	if equality.Semantic.DeepEqual(mounted, storage.Status.MountedObjects) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	storage.Status.MountedObjects = mounted
+	return r.setState(ctx, storage)
+}
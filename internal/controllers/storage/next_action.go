@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	ActionPendingCondition     = "ActionPending"
+	ActionPendingReasonWaiting = "Waiting"
+	ActionPendingReasonIdle    = "Idle"
+)
+
+// nextActions describe the steps that keep the reconcile waiting. Other
+// steps only stop it to update the status and leave the action as it is.
+var nextActions = map[string]func(storage *resources.StorageClusterBuilder) string{
+	"handleResourcesSync":       func(*resources.StorageClusterBuilder) string { return "syncing resources" },
+	"handleDisasterRecovery":    describeDisasterRecovery,
+	"handleDecommission":        describeDecommission,
+	"waitForStatefulSetToScale": describeScale,
+	"setInitialStatus":          func(*resources.StorageClusterBuilder) string { return "initializing storage" },
+	"runSelfCheck":              func(*resources.StorageClusterBuilder) string { return "waiting for a good self check" },
+	"waitForPDisks":             describePDisks,
+	"runInitScripts":            func(*resources.StorageClusterBuilder) string { return "running init scripts" },
+}
+
+// setNextAction describes what the controller does or waits for after the
+// step in status.nextAction and the ActionPending condition. A completed
+// reconcile has no next action. It reports whether the status changed.
+func (r *Reconciler) setNextAction(storage *resources.StorageClusterBuilder, step string) bool {
+	action := storage.Status.NextAction
+	switch {
+	case step == "":
+		action = ""
+	case nextActions[step] != nil:
+		action = nextActions[step](storage)
+	}
+
+	condition := metav1.Condition{
+		Type:    ActionPendingCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  ActionPendingReasonWaiting,
+		Message: action,
+	}
+	if action == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ActionPendingReasonIdle
+		condition.Message = "Nothing to do"
+	}
+	current := meta.FindStatusCondition(storage.Status.Conditions, ActionPendingCondition)
+	if action == storage.Status.NextAction && current != nil && current.Message == condition.Message {
+		return false
+	}
+	storage.Status.NextAction = action
+	meta.SetStatusCondition(&storage.Status.Conditions, condition)
+	return true
+}
+
+func describeDisasterRecovery(storage *resources.StorageClusterBuilder) string {
+	if recovery := storage.Status.DisasterRecovery; recovery != nil {
+		return fmt.Sprintf("disaster recovery: %s", recovery.Phase)
+	}
+	return "starting disaster recovery"
+}
+
+func describeDecommission(storage *resources.StorageClusterBuilder) string {
+	decommission := storage.Status.Decommission
+	switch {
+	case decommission == nil:
+		return "starting decommission"
+	case decommission.Phase == DecommissionPhaseWaitingForVDisks:
+		return fmt.Sprintf("decommission: %d VDisks left to move", decommission.VDisks)
+	default:
+		return fmt.Sprintf("decommission: %s", decommission.Phase)
+	}
+}
+
+func describePDisks(storage *resources.StorageClusterBuilder) string {
+	if pdisks := storage.Status.PDisks; pdisks != nil {
+		return fmt.Sprintf("formatting PDisks %d/%d", pdisks.Formatted, pdisks.Total)
+	}
+	return "formatting PDisks"
+}
+
+func describeScale(storage *resources.StorageClusterBuilder) string {
+	if meta.IsStatusConditionFalse(storage.Status.Conditions, ConnectionVerifiedCondition) {
+		return "waiting for the gRPC service to accept connections"
+	}
+	return "waiting for pods to become ready"
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// checkPDisksScript prints "formatted <path>" or "pending <path>" for every
+// device passed as an argument, depending on whether ydbd finds a PDisk label
+const checkPDisksScript = `for d in "$@"; do ` +
+	`if %s admin blobstorage disk info "$d" >/dev/null 2>&1; then echo "formatted $d"; else echo "pending $d"; fi; ` +
+	`done`
+
+type podPDisks struct {
+	formatted int32
+	err       error
+}
+
+// waitForPDisks waits for the PDisks of all storage pods to be formatted
+// after the blobstorage config is initialized. The pods are checked in
+// parallel, at most PDiskCheckConcurrency at once, and the progress is
+// kept in status.pdisks. Data stores in Filesystem mode are not checked.
+func (r *Reconciler) waitForPDisks(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, InitStorageStepCondition) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	devices := storage.GetBlockDevicePaths(0)
+	if len(devices) == 0 {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step waitForPDisks")
+
+	concurrency := r.Settings.Get().PDiskCheckConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	nodesPerPod := configuration.NodesPerPod(storage.Unwrap())
+	results := make([]podPDisks, storage.TotalNodes())
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			podName := fmt.Sprintf("%s-%d", storage.Name, i)
+			for node := 0; node < nodesPerPod && results[i].err == nil; node++ {
+				formatted, err := r.countFormattedPDisks(storage, podName, node)
+				results[i].formatted += formatted
+				results[i].err = err
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	podDevices := int32(nodesPerPod * len(devices))
+	status := &v1alpha1.PDisksStatus{Total: storage.TotalNodes() * podDevices}
+	for i, result := range results {
+		podName := fmt.Sprintf("%s-%d", storage.Name, i)
+		if result.err != nil {
+			r.Log.Info(fmt.Sprintf("failed to check PDisks of pod %s: %s", podName, result.err))
+		}
+		status.Formatted += result.formatted
+		if result.formatted < podDevices {
+			status.PendingPods = append(status.PendingPods, podName)
+		}
+	}
+
+	changed := !reflect.DeepEqual(storage.Status.PDisks, status)
+	storage.Status.PDisks = status
+	if status.Formatted == status.Total {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	if changed {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			events.ReasonStoragePDisksFormatting,
+			fmt.Sprintf("Waiting for PDisks to be formatted: %d/%d", status.Formatted, status.Total),
+		)
+		if _, _, err := r.setState(ctx, storage); err != nil {
+			return Stop, ctrl.Result{}, err
+		}
+	}
+	return Stop, ctrl.Result{RequeueAfter: StorageInitializationRequeueDelay}, nil
+}
+
+// countFormattedPDisks counts the formatted PDisks of the static node with
+// the given index within the pod
+func (r *Reconciler) countFormattedPDisks(
+	storage *resources.StorageClusterBuilder,
+	podName string,
+	node int,
+) (int32, error) {
+	cmd := append([]string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf(checkPDisksScript, fmt.Sprintf("%s/%s", v1alpha1.BinariesDir, v1alpha1.DaemonBinaryName)),
+		"sh",
+	}, storage.GetBlockDevicePaths(node)...)
+
+	stdout, _, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, podName, resources.StorageNodeContainerName(node), cmd)
+	if err != nil {
+		return 0, err
+	}
+
+	var formatted int32
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.HasPrefix(line, "formatted ") {
+			formatted++
+		}
+	}
+	return formatted, nil
+}
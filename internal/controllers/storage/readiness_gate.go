@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	NodeReadyReasonContainersNotReady = "ContainersNotReady"
+	NodeReadyReasonNotServing         = "NotServing"
+	NodeReadyReasonServing            = "Serving"
+	NodeReadyReasonNotInitialized     = "NotInitialized"
+
+	NodeCheckTimeout = 5 * time.Second
+)
+
+// handleReadinessGates sets the ydb.tech/node-ready condition of the
+// storage pods once their nodes answer the gRPC health service. Until the
+// storage is initialized only the containers readiness is required, as the
+// initialization itself waits for the pods to become ready.
+func (r *Reconciler) handleReadinessGates(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	if !storage.Spec.ReadinessGate {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleReadinessGates")
+
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList,
+		client.InNamespace(storage.Namespace),
+		client.MatchingLabels{
+			labels.InstanceKey:  storage.Name,
+			labels.ComponentKey: labels.StorageComponent,
+		},
+	)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to list storage pods: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	secure := storage.Spec.Service.GRPC.TLSConfiguration != nil && storage.Spec.Service.GRPC.TLSConfiguration.Enabled
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		ready, reason, message := r.checkNode(ctx, storage, pod, secure)
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if !resources.SetNodeReadyGate(pod, ready, reason, message) {
+			continue
+		}
+		if err := r.Status().Patch(ctx, pod, patch); err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				events.ReasonControllerError,
+				fmt.Sprintf("Failed to update readiness gate of pod %s: %s", pod.Name, err),
+			)
+			return Stop, ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) checkNode(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	pod *corev1.Pod,
+	secure bool,
+) (bool, string, string) {
+	if !resources.ContainersReady(pod) {
+		return false, NodeReadyReasonContainersNotReady, "Containers are not ready"
+	}
+
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		return true, NodeReadyReasonNotInitialized, "Storage is not initialized yet"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, NodeCheckTimeout)
+	defer cancel()
+
+	endpoint := resources.NodeGRPCEndpoint(pod.Name, storage.Name, storage.Namespace)
+	if err := healthcheck.CheckNodeHealth(ctx, endpoint, secure); err != nil {
+		return false, NodeReadyReasonNotServing, err.Error()
+	}
+	return true, NodeReadyReasonServing, "Node is serving"
+}
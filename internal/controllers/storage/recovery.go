@@ -0,0 +1,543 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	RecoveryPhaseValidating            = "Validating"
+	RecoveryPhaseRelocatingStaticGroup = "RelocatingStaticGroup"
+	RecoveryPhaseRestartingNodes       = "RestartingNodes"
+	RecoveryPhaseWaitingForNodes       = "WaitingForNodes"
+	RecoveryPhaseReinitializingStorage = "ReinitializingStorage"
+	RecoveryPhaseVerifying             = "Verifying"
+	RecoveryPhaseCompleted             = "Completed"
+	RecoveryPhaseRejected              = "Rejected"
+
+	DisasterRecoveryRequeueDelay = 10 * time.Second
+)
+
+// handleDisasterRecovery automates the recovery of a storage whose static
+// group lost more VDisks than its erasure tolerates. It is started by
+// setting the ydb.tech/disaster-recovery annotation to
+// "confirm-<storage name>" and goes through the phases below, recording
+// every step in status.disasterRecovery and in the events:
+//
+//   - Validating: the static group has to have lost its quorum, according
+//     to the pods of its nodes and the VDisk states their whiteboards report.
+//     Every node that lost a VDisk of the group gets a running replacement
+//     in a pod the group doesn't use yet, in the same zone with mirror-3-dc
+//   - RelocatingStaticGroup: the VDisks of the group are moved to the
+//     replacement nodes in spec.configuration and the group generation is
+//     incremented, as in the manual static group reconfiguration
+//   - RestartingNodes: once the new configuration is synced, the pods are
+//     deleted, so the nodes start with the moved static group
+//   - WaitingForNodes: the pods of the nodes hosting the moved group, the
+//     surviving and the replacement ones, have to be running again
+//   - ReinitializingStorage: the blobstorage config init is rerun to restore
+//     the base config
+//   - Verifying: the moved static group has to regain its quorum
+//
+// The rest of the sync waits while the recovery is in progress. The annotation
+// is removed once the recovery completes or is rejected, setting it again
+// starts a new recovery. Configurations listing the hosts or the static
+// PDisks themselves are left to the administrator.
+func (r *Reconciler) handleDisasterRecovery(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	value, requested := storage.Annotations[ydbv1alpha1.DisasterRecoveryAnnotation]
+	if !requested {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDisasterRecovery")
+
+	if value != "confirm-"+storage.Name {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			events.ReasonStorageDisasterRecoveryRejected,
+			fmt.Sprintf("Annotation %s has to be set to confirm-%s", ydbv1alpha1.DisasterRecoveryAnnotation, storage.Name),
+		)
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	// A finished recovery is kept in status until the next one is requested
+	status := storage.Status.DisasterRecovery
+	if status == nil || status.Phase == RecoveryPhaseCompleted || status.Phase == RecoveryPhaseRejected {
+		storage.Status.DisasterRecovery = &ydbv1alpha1.DisasterRecoveryStatus{
+			Phase:     RecoveryPhaseValidating,
+			StartTime: metav1.Now(),
+		}
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageDisasterRecovery, "Disaster recovery requested")
+		return r.setState(ctx, storage)
+	}
+
+	switch status.Phase {
+	case RecoveryPhaseValidating:
+		return r.validateDisasterRecovery(ctx, storage)
+	case RecoveryPhaseRelocatingStaticGroup:
+		return r.relocateStaticGroup(ctx, storage)
+	case RecoveryPhaseRestartingNodes:
+		return r.restartStorageNodes(ctx, storage)
+	case RecoveryPhaseWaitingForNodes:
+		return r.waitForRecoveredNodes(ctx, storage)
+	case RecoveryPhaseReinitializingStorage:
+		return r.reinitializeStorage(ctx, storage)
+	default:
+		return r.verifyStaticGroup(ctx, storage)
+	}
+}
+
+func (r *Reconciler) validateDisasterRecovery(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	configured, err := configuration.HostsConfigured(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	if configured {
+		return r.rejectDisasterRecovery(ctx, storage, "The configuration lists the hosts itself, the static group has to be moved there")
+	}
+	configured, err = configuration.PDisksConfigured(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	if configured {
+		return r.rejectDisasterRecovery(ctx, storage, "The configuration lists the static PDisks itself, the static group has to be moved there")
+	}
+	locations, err := configuration.StaticGroupLocations(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	if len(locations) == 0 {
+		return r.rejectDisasterRecovery(ctx, storage, "The configuration has no static group")
+	}
+
+	pods, err := r.listStoragePods(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list cluster pods: %s", err))
+		return Stop, ctrl.Result{}, err
+	}
+
+	// An unknown group status is no proof of a quorum loss, the validation
+	// is retried until the whiteboards of the running nodes respond
+	lost, err := r.lostStaticGroupLocations(ctx, storage, locations, pods)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageDisasterRecovery,
+			fmt.Sprintf("Failed to get the static group status: %s", err))
+		return Stop, ctrl.Result{RequeueAfter: DisasterRecoveryRequeueDelay}, nil
+	}
+	if !staticGroupQuorumLost(storage.Spec.Erasure, lost) {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf(
+			"The static group lost %d of %d VDisks, which erasure %s tolerates",
+			len(lost),
+			len(locations),
+			storage.Spec.Erasure,
+		))
+	}
+
+	relocations, err := planStaticGroupRelocations(storage, locations, lost, pods)
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to move the static group: %s", err))
+	}
+	storage.Status.DisasterRecovery.Relocations = relocations
+
+	var moves []string
+	for _, relocation := range relocations {
+		moves = append(moves, fmt.Sprintf("%d to %d", relocation.NodeID, relocation.ReplacementNodeID))
+	}
+	r.recordRecoveryStep(storage, RecoveryPhaseRelocatingStaticGroup, resources.HistoryOutcomeSucceeded, fmt.Sprintf(
+		"The static group lost its quorum with %d of %d VDisks, moving its VDisks from nodes %s",
+		len(lost),
+		len(locations),
+		strings.Join(moves, ", "),
+	))
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) relocateStaticGroup(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	moves := map[int]int{}
+	for _, relocation := range storage.Status.DisasterRecovery.Relocations {
+		moves[int(relocation.NodeID)] = int(relocation.ReplacementNodeID)
+	}
+
+	// The spec is patched before the phase moves on, a repeated attempt
+	// finds the group moved already
+	nodeIDs, err := configuration.StaticGroupNodeIDs(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	for _, id := range nodeIDs {
+		if _, ok := moves[id]; !ok {
+			continue
+		}
+		storageCr := &ydbv1alpha1.Storage{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+			return Stop, ctrl.Result{}, err
+		}
+		config, err := configuration.RelocateStaticGroup(storageCr.Spec.Configuration, moves)
+		if err != nil {
+			return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to move the static group: %s", err))
+		}
+		patch := client.MergeFrom(storageCr.DeepCopy())
+		storageCr.Spec.Configuration = config
+		if err := r.Patch(ctx, storageCr, patch); err != nil {
+			r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to update spec.configuration: %s", err))
+			return Stop, ctrl.Result{}, err
+		}
+		return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+	}
+
+	r.recordRecoveryStep(storage, RecoveryPhaseRestartingNodes, resources.HistoryOutcomeSucceeded,
+		"The static group is moved in spec.configuration with an incremented group generation")
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) restartStorageNodes(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	// The resources are synced earlier in the same reconcile, the nodes
+	// are restarted only once the moved static group is rendered for them
+	hash, err := resources.RenderedHash(storage, storage.GetResourceBuilders())
+	if err != nil {
+		return Stop, ctrl.Result{}, err
+	}
+	if storage.Status.ResourcesSync == nil || storage.Status.ResourcesSync.Hash != hash {
+		r.Log.Info("waiting for the moved static group to be synced")
+		return Stop, ctrl.Result{RequeueAfter: DisasterRecoveryRequeueDelay}, nil
+	}
+
+	pods, err := r.listStoragePods(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list cluster pods: %s", err))
+		return Stop, ctrl.Result{}, err
+	}
+
+	var deleted []string
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			r.recordRecoveryStep(storage, RecoveryPhaseRestartingNodes, resources.HistoryOutcomeFailed,
+				fmt.Sprintf("Failed to delete pod %s: %s", pod.Name, err))
+			_, _, _ = r.setState(ctx, storage)
+			return Stop, ctrl.Result{}, err
+		}
+		deleted = append(deleted, pod.Name)
+	}
+
+	r.recordRecoveryStep(storage, RecoveryPhaseWaitingForNodes, resources.HistoryOutcomeSucceeded,
+		fmt.Sprintf("Restarted pods: %s", strings.Join(deleted, ", ")))
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) waitForRecoveredNodes(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	locations, err := configuration.StaticGroupLocations(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	pods, err := r.listStoragePods(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list cluster pods: %s", err))
+		return Stop, ctrl.Result{}, err
+	}
+
+	// Only the nodes hosting the moved group matter, the failed ones may
+	// never come back
+	running := runningNodeIDs(storage, pods)
+	var nodes, waiting []string
+	seen := map[int]bool{}
+	for _, location := range locations {
+		if seen[location.NodeID] {
+			continue
+		}
+		seen[location.NodeID] = true
+		nodes = append(nodes, strconv.Itoa(location.NodeID))
+		if !running[location.NodeID] {
+			waiting = append(waiting, strconv.Itoa(location.NodeID))
+		}
+	}
+	if len(waiting) > 0 {
+		r.Log.Info("waiting for the static group nodes to run", "nodes", strings.Join(waiting, ", "))
+		return Stop, ctrl.Result{RequeueAfter: DisasterRecoveryRequeueDelay}, nil
+	}
+
+	r.recordRecoveryStep(storage, RecoveryPhaseReinitializingStorage, resources.HistoryOutcomeSucceeded,
+		fmt.Sprintf("Static group nodes %s are running", strings.Join(nodes, ", ")))
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) reinitializeStorage(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	podName := fmt.Sprintf("%s-0", storage.Name)
+	stdout, _, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, podName, "ydb-storage", blobstorageInitCommand(storage))
+
+	// A base config applied before the failure is kept, whether the static
+	// group serves again is verified in the next phase
+	msg := "Blobstorage config applied"
+	if err != nil {
+		if !mismatchItemConfigGenerationRegexp.MatchString(stdout) {
+			r.recordRecoveryStep(storage, RecoveryPhaseReinitializingStorage, resources.HistoryOutcomeFailed,
+				fmt.Sprintf("Blobstorage config init failed: %s", err))
+			_, _, _ = r.setState(ctx, storage)
+			return Stop, ctrl.Result{}, err
+		}
+		msg = "Blobstorage config is applied already"
+	}
+
+	r.recordRecoveryStep(storage, RecoveryPhaseVerifying, resources.HistoryOutcomeSucceeded, msg)
+	return r.setState(ctx, storage)
+}
+
+func (r *Reconciler) verifyStaticGroup(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	locations, err := configuration.StaticGroupLocations(storage.Unwrap())
+	if err != nil {
+		return r.rejectDisasterRecovery(ctx, storage, fmt.Sprintf("Failed to parse the configuration: %s", err))
+	}
+	pods, err := r.listStoragePods(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list cluster pods: %s", err))
+		return Stop, ctrl.Result{}, err
+	}
+
+	lost, err := r.lostStaticGroupLocations(ctx, storage, locations, pods)
+	if err != nil {
+		r.Log.Error(err, "failed to get the static group status")
+		return Stop, ctrl.Result{RequeueAfter: DisasterRecoveryRequeueDelay}, nil
+	}
+	if staticGroupQuorumLost(storage.Spec.Erasure, lost) {
+		r.Log.Info(fmt.Sprintf("waiting for the static group to serve: %d/%d VDisks", len(locations)-len(lost), len(locations)))
+		return Stop, ctrl.Result{RequeueAfter: DisasterRecoveryRequeueDelay}, nil
+	}
+
+	r.recordRecoveryStep(storage, RecoveryPhaseCompleted, resources.HistoryOutcomeSucceeded,
+		fmt.Sprintf("The static group regained its quorum, %d of %d VDisks serve", len(locations)-len(lost), len(locations)))
+	return r.finishDisasterRecovery(ctx, storage)
+}
+
+// lostStaticGroupLocations returns the VDisk locations of the static group
+// that don't serve: those on the nodes of pods that are not running, and
+// those the whiteboard of a running node reports missing or not OK
+func (r *Reconciler) lostStaticGroupLocations(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	locations []configuration.StaticGroupLocation,
+	pods []corev1.Pod,
+) ([]configuration.StaticGroupLocation, error) {
+	running := runningNodeIDs(storage, pods)
+	reported := map[int][]cms.GroupVDisk{}
+
+	var lost []configuration.StaticGroupLocation
+	for _, location := range locations {
+		if !running[location.NodeID] {
+			lost = append(lost, location)
+			continue
+		}
+		vdisks, ok := reported[location.NodeID]
+		if !ok {
+			var err error
+			vdisks, err = cms.NodeGroupVDisks(ctx, storage.GetStatusEndpoint(), uint32(location.NodeID), configuration.StaticGroupID)
+			if err != nil {
+				return nil, fmt.Errorf("node %d: %w", location.NodeID, err)
+			}
+			reported[location.NodeID] = vdisks
+		}
+		if !vdiskServes(vdisks, location) {
+			lost = append(lost, location)
+		}
+	}
+	return lost, nil
+}
+
+func vdiskServes(vdisks []cms.GroupVDisk, location configuration.StaticGroupLocation) bool {
+	for _, vdisk := range vdisks {
+		if vdisk.Ring == location.Ring && vdisk.FailDomain == location.FailDomain && vdisk.VDisk == location.VDisk {
+			return vdisk.State == cms.VDiskStateOK
+		}
+	}
+	return false
+}
+
+// staticGroupQuorumLost reports whether the fail domains with lost VDisks
+// exceed what the erasure tolerates: 2 fail domains with block-4-2, a ring
+// and one more fail domain with mirror-3-dc, and none otherwise
+func staticGroupQuorumLost(erasure ydbv1alpha1.ErasureType, lost []configuration.StaticGroupLocation) bool {
+	domains := map[[2]int]bool{}
+	rings := map[int]int{}
+	for _, location := range lost {
+		domain := [2]int{location.Ring, location.FailDomain}
+		if !domains[domain] {
+			domains[domain] = true
+			rings[location.Ring]++
+		}
+	}
+
+	switch erasure {
+	case ydbv1alpha1.ErasureBlock42:
+		return len(domains) > 2
+	case ydbv1alpha1.ErasureMirror3DC:
+		worst := 0
+		for _, count := range rings {
+			if count > worst {
+				worst = count
+			}
+		}
+		return len(domains)-worst > 1
+	default:
+		return len(domains) > 0
+	}
+}
+
+// planStaticGroupRelocations picks a replacement for every node that lost a
+// VDisk of the static group. A replacement is a running node of a pod that
+// hosts no other VDisk of the group, so the group keeps its fail domains,
+// and with mirror-3-dc it is in the zone of the failed node.
+func planStaticGroupRelocations(
+	storage *resources.StorageClusterBuilder,
+	locations []configuration.StaticGroupLocation,
+	lost []configuration.StaticGroupLocation,
+	pods []corev1.Pod,
+) ([]ydbv1alpha1.StaticGroupRelocation, error) {
+	nodesPerPod := configuration.NodesPerPod(storage.Unwrap())
+	ordinal := func(nodeID int) int { return (nodeID - 1) / nodesPerPod }
+
+	used := map[int]bool{}
+	for _, location := range locations {
+		used[ordinal(location.NodeID)] = true
+	}
+	var failed []int
+	seen := map[int]bool{}
+	for _, location := range lost {
+		if !seen[location.NodeID] {
+			seen[location.NodeID] = true
+			failed = append(failed, location.NodeID)
+		}
+	}
+	sort.Ints(failed)
+
+	running := runningNodeIDs(storage, pods)
+	total := int(storage.TotalNodes()) * nodesPerPod
+
+	var relocations []ydbv1alpha1.StaticGroupRelocation
+	for _, nodeID := range failed {
+		replacement := 0
+		for id := 1; id <= total && replacement == 0; id++ {
+			if !running[id] || used[ordinal(id)] {
+				continue
+			}
+			if storage.Spec.Erasure == ydbv1alpha1.ErasureMirror3DC && ordinal(id)%3 != ordinal(nodeID)%3 {
+				continue
+			}
+			replacement = id
+		}
+		if replacement == 0 {
+			return nil, fmt.Errorf("no running node outside of the static group can replace node %d", nodeID)
+		}
+		used[ordinal(replacement)] = true
+		relocations = append(relocations, ydbv1alpha1.StaticGroupRelocation{
+			NodeID:            int32(nodeID),
+			ReplacementNodeID: int32(replacement),
+		})
+	}
+	return relocations, nil
+}
+
+// runningNodeIDs returns the ids of the static nodes of the running pods
+func runningNodeIDs(storage *resources.StorageClusterBuilder, pods []corev1.Pod) map[int]bool {
+	ids := map[int]bool{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, id := range podNodeIDs(storage, podOrdinal(pod.Name)) {
+			ids[int(id)] = true
+		}
+	}
+	return ids
+}
+
+// rejectDisasterRecovery leaves the storage as it is and reports why
+func (r *Reconciler) rejectDisasterRecovery(ctx context.Context, storage *resources.StorageClusterBuilder, message string) (bool, ctrl.Result, error) {
+	r.recordRecoveryStep(storage, RecoveryPhaseRejected, resources.HistoryOutcomeFailed, message)
+	r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageDisasterRecoveryRejected, message)
+	return r.finishDisasterRecovery(ctx, storage)
+}
+
+// recordRecoveryStep moves the recovery to the next phase and logs the step
+// taken in status and in the events
+func (r *Reconciler) recordRecoveryStep(storage *resources.StorageClusterBuilder, next, outcome, message string) {
+	status := storage.Status.DisasterRecovery
+	status.Steps = append(status.Steps, ydbv1alpha1.DisasterRecoveryStep{
+		Phase:   status.Phase,
+		Time:    metav1.Now(),
+		Outcome: outcome,
+		Message: message,
+	})
+	if len(status.Steps) > resources.MaxHistoryEntries {
+		status.Steps = status.Steps[len(status.Steps)-resources.MaxHistoryEntries:]
+	}
+	r.Log.Info("disaster recovery step", "phase", status.Phase, "outcome", outcome, "message", message)
+	r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageDisasterRecovery, fmt.Sprintf("%s: %s", status.Phase, message))
+	status.Phase = next
+}
+
+func (r *Reconciler) finishDisasterRecovery(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	status := storage.Status.DisasterRecovery
+	outcome := resources.HistoryOutcomeSucceeded
+	if status.Phase == RecoveryPhaseRejected {
+		outcome = resources.HistoryOutcomeFailed
+	}
+	storage.Status.History = resources.AppendHistory(
+		storage.Status.History,
+		resources.HistoryActionDisasterRecovery,
+		outcome,
+		storage.Generation,
+		status.Steps[len(status.Steps)-1].Message,
+		time.Now(),
+	)
+	if stop, result, err := r.setState(ctx, storage); err != nil {
+		return stop, result, err
+	}
+	return r.removeDisasterRecoveryAnnotation(ctx, storage)
+}
+
+func (r *Reconciler) removeDisasterRecoveryAnnotation(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	storageCr := &ydbv1alpha1.Storage{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(storage), storageCr); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	patch := client.MergeFrom(storageCr.DeepCopy())
+	delete(storageCr.Annotations, ydbv1alpha1.DisasterRecoveryAnnotation)
+	if err := r.Patch(ctx, storageCr, patch); err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to remove annotation: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
+
+func (r *Reconciler) listStoragePods(ctx context.Context, storage *resources.StorageClusterBuilder) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList,
+		client.InNamespace(storage.Namespace),
+		client.MatchingLabels{
+			labels.InstanceKey:  storage.Name,
+			labels.ComponentKey: labels.StorageComponent,
+		},
+	)
+	return podList.Items, err
+}
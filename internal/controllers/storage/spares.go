@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// SpareNodeActivationDelay is how long a storage pod has to be not ready
+// before the drives of a spare pod are enabled for the self heal, so that
+// restarts and rolling updates don't touch the spare pods
+const SpareNodeActivationDelay = 5 * time.Minute
+
+// handleSpareNodes keeps the drives of the spare pods decommitted, so no
+// groups are placed on them, while the other storage pods are ready. For
+// every pod not ready for SpareNodeActivationDelay the drives of a spare
+// pod are enabled and the self heal moves the VDisks of the failed node to
+// them. Once all the pods are ready again the spare pods are decommitted
+// again, which moves the VDisks they took back and keeps them spare for the
+// next failure. The drive states set are kept in status.spareNodes.
+func (r *Reconciler) handleSpareNodes(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	if storage.Spec.SpareNodes == 0 && storage.Status.SpareNodes == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if configured, err := configuration.HostsConfigured(storage.Unwrap()); err != nil || configured {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleSpareNodes")
+
+	pods, err := r.listStoragePods(ctx, storage)
+	if err != nil {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed to list cluster pods: %s", err))
+		return Stop, ctrl.Result{}, err
+	}
+	byName := map[string]*corev1.Pod{}
+	for i := range pods {
+		byName[pods[i].Name] = &pods[i]
+	}
+
+	now := time.Now()
+	failed := int32(0)
+	for ordinal := int32(0); ordinal < storage.Spec.Nodes; ordinal++ {
+		if pod, ok := byName[fmt.Sprintf("%s-%d", storage.Name, ordinal)]; ok && podFailed(pod, now) {
+			failed++
+		}
+	}
+
+	desired := &ydbv1alpha1.SpareNodesStatus{}
+	for ordinal := storage.Spec.Nodes; ordinal < storage.TotalNodes(); ordinal++ {
+		pod := fmt.Sprintf("%s-%d", storage.Name, ordinal)
+		if ordinal-storage.Spec.Nodes < failed {
+			desired.Active = append(desired.Active, pod)
+		} else {
+			desired.Standby = append(desired.Standby, pod)
+		}
+	}
+
+	current := storage.Status.SpareNodes
+	if current == nil {
+		current = &ydbv1alpha1.SpareNodesStatus{}
+	}
+	if reflect.DeepEqual(current, desired) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	enabled := map[string]bool{}
+	for _, pod := range current.Active {
+		enabled[pod] = true
+	}
+	decommitted := map[string]bool{}
+	for _, pod := range current.Standby {
+		decommitted[pod] = true
+
+		// Pods that are no longer spare, after spec.nodes grew, are put to use
+		if podOrdinal(pod) < storage.Spec.Nodes {
+			if err := r.setSpareDrives(storage, pods, pod, DriveDecommitNone); err != nil {
+				return r.failSpareNodes(storage, pod, err)
+			}
+		}
+	}
+
+	var activated, parked []string
+	for _, pod := range desired.Active {
+		if !enabled[pod] {
+			if err := r.setSpareDrives(storage, pods, pod, DriveDecommitNone); err != nil {
+				return r.failSpareNodes(storage, pod, err)
+			}
+			activated = append(activated, pod)
+		}
+	}
+	for _, pod := range desired.Standby {
+		if !decommitted[pod] {
+			if err := r.setSpareDrives(storage, pods, pod, DriveDecommitImminent); err != nil {
+				return r.failSpareNodes(storage, pod, err)
+			}
+			parked = append(parked, pod)
+		}
+	}
+
+	if len(activated) > 0 {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageSpareNodes, fmt.Sprintf(
+			"%d storage pods failed, enabled the drives of spare pods %s for the self heal",
+			failed,
+			strings.Join(activated, ", "),
+		))
+	}
+	if len(parked) > 0 {
+		r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonStorageSpareNodes, fmt.Sprintf(
+			"Decommitted the drives of spare pods %s",
+			strings.Join(parked, ", "),
+		))
+	}
+	storage.Status.SpareNodes = desired
+	if len(desired.Active) == 0 && len(desired.Standby) == 0 {
+		storage.Status.SpareNodes = nil
+	}
+	return r.setState(ctx, storage)
+}
+
+// setSpareDrives sets the decommit status of the drives of every node of the
+// spare pod through the blobstorage config, from any ready storage pod
+func (r *Reconciler) setSpareDrives(storage *resources.StorageClusterBuilder, pods []corev1.Pod, pod, status string) error {
+	var execPod string
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && resources.ContainersReady(&pods[i]) {
+			execPod = pods[i].Name
+			break
+		}
+	}
+	if execPod == "" {
+		return errors.New("no ready storage pod to run the blobstorage config from")
+	}
+
+	for j := 0; j < configuration.NodesPerPod(storage.Unwrap()); j++ {
+		for _, path := range storage.GetDrivePaths(j) {
+			cmd := driveDecommitCommand(storage, pod, ydbv1alpha1.InterconnectPort+j, path, status)
+			if _, _, err := exec.InPod(r.Scheme, r.Config, storage.Namespace, execPod, "ydb-storage", cmd); err != nil {
+				return fmt.Errorf("drive %s: %w", path, err)
+			}
+		}
+	}
+	r.Log.Info("set spare drives", "pod", pod, "status", status)
+	return nil
+}
+
+func (r *Reconciler) failSpareNodes(storage *resources.StorageClusterBuilder, pod string, err error) (bool, ctrl.Result, error) {
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeWarning,
+		events.ReasonStorageSpareNodes,
+		fmt.Sprintf("Failed to update the drives of spare pod %s: %s", pod, err),
+	)
+	return Stop, ctrl.Result{}, err
+}
+
+// podFailed reports whether the pod has not been ready for
+// SpareNodeActivationDelay
+func podFailed(pod *corev1.Pod, now time.Time) bool {
+	since := pod.CreationTimestamp.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return false
+		}
+		since = condition.LastTransitionTime.Time
+	}
+	return now.Sub(since) >= SpareNodeActivationDelay
+}
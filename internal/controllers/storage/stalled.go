@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	StalledCondition         = "Stalled"
+	StalledReasonStepBlocked = "StepBlocked"
+	StalledReasonProgressing = "Progressing"
+)
+
+// checkStalled sets the Stalled condition when the storage has stayed in
+// Provisioning or Initializing for longer than the stalled threshold, naming the
+// step that stopped the reconcile. The result of the step is passed through.
+// It also keeps status.nextAction and the ActionPending condition in sync
+// with the step.
+func (r *Reconciler) checkStalled(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	step string,
+	result ctrl.Result,
+	err error,
+) (ctrl.Result, error) {
+	operatormetrics.ObserveStep(operatormetrics.KindStorage, storage.Namespace, storage.Name, step, err)
+	operatormetrics.SetState(operatormetrics.KindStorage, storage.Namespace, storage.Name, storage.Status.State)
+
+	actionChanged := r.setNextAction(storage, step)
+	threshold := r.Settings.Get().StalledThreshold
+	if threshold <= 0 {
+		return result, r.updateNextAction(ctx, storage, actionChanged, err)
+	}
+
+	current := meta.FindStatusCondition(storage.Status.Conditions, StalledCondition)
+	inProgress := storage.Status.State == string(Provisioning) || storage.Status.State == string(Initializing)
+
+	var condition metav1.Condition
+	switch {
+	case inProgress && step != "" && storage.Status.StateTransitionTime != nil &&
+		time.Since(storage.Status.StateTransitionTime.Time) > threshold:
+		condition = metav1.Condition{
+			Type:   StalledCondition,
+			Status: metav1.ConditionTrue,
+			Reason: StalledReasonStepBlocked,
+			Message: fmt.Sprintf(
+				"Storage is %s for more than %s, blocked at step %s",
+				storage.Status.State,
+				threshold,
+				step,
+			),
+		}
+		if current != nil && current.Status == metav1.ConditionTrue && current.Message == condition.Message {
+			return result, r.updateNextAction(ctx, storage, actionChanged, err)
+		}
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStalled, condition.Message)
+	case current != nil && current.Status == metav1.ConditionTrue && (!inProgress || step == ""):
+		condition = metav1.Condition{
+			Type:    StalledCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  StalledReasonProgressing,
+			Message: fmt.Sprintf("Storage is %s", storage.Status.State),
+		}
+	default:
+		return result, r.updateNextAction(ctx, storage, actionChanged, err)
+	}
+
+	meta.SetStatusCondition(&storage.Status.Conditions, condition)
+	if _, _, updateErr := r.setState(ctx, storage); updateErr != nil {
+		r.Log.Error(updateErr, "failed to update Stalled condition")
+	}
+	return result, err
+}
+
+// updateNextAction persists a changed status.nextAction, the error of the
+// step is passed through
+func (r *Reconciler) updateNextAction(ctx context.Context, storage *resources.StorageClusterBuilder, changed bool, err error) error {
+	if changed {
+		if _, _, updateErr := r.setState(ctx, storage); updateErr != nil {
+			r.Log.Error(updateErr, "failed to update next action")
+		}
+	}
+	return err
+}
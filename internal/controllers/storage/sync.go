@@ -2,8 +2,10 @@ package storage
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
@@ -11,12 +13,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/chaos"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
@@ -27,15 +32,17 @@ const (
 	Initializing ClusterState = "Initializing"
 	Ready        ClusterState = "Ready"
 
-	DefaultRequeueDelay               = 10 * time.Second
 	StatusUpdateRequeueDelay          = 1 * time.Second
-	SelfCheckRequeueDelay             = 30 * time.Second
 	StorageInitializationRequeueDelay = 5 * time.Second
 
 	ReasonInProgress  = "InProgress"
 	ReasonNotRequired = "NotRequired"
 	ReasonCompleted   = "Completed"
 
+	PodsReadyCondition      = "PodsReady"
+	PodsReadyReasonReady    = "Ready"
+	PodsReadyReasonNotReady = "NotReady"
+
 	StorageInitializedCondition        = "StorageInitialized"
 	StorageInitializedReasonInProgress = ReasonInProgress
 	StorageInitializedReasonCompleted  = ReasonCompleted
@@ -66,31 +73,106 @@ func (r *Reconciler) Sync(ctx context.Context, cr *ydbv1alpha1.Storage) (ctrl.Re
 
 	storage := resources.NewCluster(cr)
 	storage.SetStatusOnFirstReconcile()
+	storage.ConfigRevisionHistory = r.Settings.Get().ConfigRevisionHistory
 
+	stop, result, err = r.handleDiskAccess(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleDiskAccess", result, err)
+	}
 	stop, result, err = r.handleResourcesSync(ctx, &storage)
 	if stop {
-		return result, err
+		return r.checkStalled(ctx, &storage, "handleResourcesSync", result, err)
+	}
+	stop, result, err = r.handleGarbageCollection(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleGarbageCollection", result, err)
+	}
+	stop, result, err = r.handleCapacityEstimate(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleCapacityEstimate", result, err)
+	}
+	stop, result, err = r.handleNodesUtilization(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleNodesUtilization", result, err)
+	}
+	stop, result, err = r.handleMountedObjects(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleMountedObjects", result, err)
+	}
+	stop, result, err = r.handleLinks(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleLinks", result, err)
+	}
+	stop, result, err = r.handleDisasterRecovery(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleDisasterRecovery", result, err)
+	}
+	stop, result, err = r.handleDecommission(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleDecommission", result, err)
+	}
+	stop, result, err = r.handleSpareNodes(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleSpareNodes", result, err)
+	}
+	stop, result, err = r.handleReadinessGates(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleReadinessGates", result, err)
 	}
 	stop, result, err = r.waitForStatefulSetToScale(ctx, &storage)
 	if stop {
-		return result, err
+		return r.checkStalled(ctx, &storage, "waitForStatefulSetToScale", result, err)
 	}
 	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
 		stop, result, err = r.setInitialStatus(ctx, &storage)
 		if stop {
-			return result, err
+			return r.checkStalled(ctx, &storage, "setInitialStatus", result, err)
 		}
 		stop, result, err = r.runSelfCheck(ctx, &storage, false)
 		if stop {
-			return result, err
+			return r.checkStalled(ctx, &storage, "runSelfCheck", result, err)
+		}
+		stop, result, err = r.waitForPDisks(ctx, &storage)
+		if stop {
+			return r.checkStalled(ctx, &storage, "waitForPDisks", result, err)
 		}
 		stop, result, err = r.runInitScripts(ctx, &storage)
 		if stop {
-			return result, err
+			return r.checkStalled(ctx, &storage, "runInitScripts", result, err)
 		}
 	}
-	_, result, err = r.runSelfCheck(ctx, &storage, false)
-	return result, err
+	stop, result, err = r.handleMonitoringUser(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleMonitoringUser", result, err)
+	}
+	stop, result, err = r.handleResourceUsage(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleResourceUsage", result, err)
+	}
+	stop, result, err = r.handleAutoUpdate(ctx, &storage)
+	if stop {
+		return r.checkStalled(ctx, &storage, "handleAutoUpdate", result, err)
+	}
+	stop, result, err = r.runSelfCheck(ctx, &storage, false)
+	if stop {
+		return r.checkStalled(ctx, &storage, "runSelfCheck", result, err)
+	}
+	return r.checkStalled(ctx, &storage, "", r.periodicResult(&storage), nil)
+}
+
+// periodicResult requeues a healthy storage for the steps running on a
+// schedule: the resource usage, the auto-update and the full resources
+// sync
+func (r *Reconciler) periodicResult(storage *resources.StorageClusterBuilder) ctrl.Result {
+	settings := r.Settings.Get()
+	result := ctrl.Result{RequeueAfter: settings.ResourceUsageInterval}
+	if settings.ResourcesResyncPeriod < result.RequeueAfter {
+		result.RequeueAfter = settings.ResourcesResyncPeriod
+	}
+	if policy := storage.Spec.AutoUpdate; policy != nil && policy.Enabled && AutoUpdateCheckInterval < result.RequeueAfter {
+		result.RequeueAfter = AutoUpdateCheckInterval
+	}
+	return result
 }
 
 func (r *Reconciler) waitForStatefulSetToScale(
@@ -98,6 +180,9 @@ func (r *Reconciler) waitForStatefulSetToScale(
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step waitForStatefulSetToScale")
+	if err := chaos.Inject(ctx, storage, "waitForStatefulSetToScale"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
 	found := &appsv1.StatefulSet{}
 	err := r.Get(ctx, types.NamespacedName{
 		Name:      storage.Name,
@@ -105,15 +190,15 @@ func (r *Reconciler) waitForStatefulSetToScale(
 	}, found)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+			return Stop, ctrl.Result{Requeue: true}, nil
 		}
 		r.Recorder.Event(
 			storage,
-			corev1.EventTypeNormal,
-			"Syncing",
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
 			fmt.Sprintf("Failed to get StatefulSets: %s", err),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
 	podLabels := labels.Common(storage.Name, make(map[string]string))
@@ -136,34 +221,60 @@ func (r *Reconciler) waitForStatefulSetToScale(
 	if err != nil {
 		r.Recorder.Event(
 			storage,
-			corev1.EventTypeNormal,
-			"Syncing",
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
 			fmt.Sprintf("Failed to list cluster pods: %s", err),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
-	runningPods := 0
-	for _, e := range podList.Items {
-		if e.Status.Phase == "Running" {
-			runningPods++
+	// Spare pods have to be ready as well to take over a failed node
+	replicas := storage.TotalNodes()
+	if found.Status.ReadyReplicas != replicas || found.Status.UpdatedReplicas != replicas {
+		msg := fmt.Sprintf("Waiting for pods to become ready: ready %d/%d, updated %d/%d",
+			found.Status.ReadyReplicas,
+			replicas,
+			found.Status.UpdatedReplicas,
+			replicas,
+		)
+		if notReady := resources.NotReadyPods(found.Name, replicas, podList.Items); len(notReady) > 0 {
+			msg += fmt.Sprintf(", not ready: %s", strings.Join(notReady, ", "))
 		}
-	}
-
-	if runningPods != int(storage.Spec.Nodes) {
-		msg := fmt.Sprintf("Waiting for number of running pods to match expected: %d != %d", runningPods, storage.Spec.Nodes)
-		r.Recorder.Event(storage, corev1.EventTypeNormal, string(Provisioning), msg)
+		r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonPodsNotReady, msg)
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    PodsReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  PodsReadyReasonNotReady,
+			Message: msg,
+		})
 		storage.Status.State = string(Provisioning)
 		return r.setState(ctx, storage)
 	}
 
+	changed := false
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, PodsReadyCondition) {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    PodsReadyCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  PodsReadyReasonReady,
+			Message: "All pods are ready and up to date",
+		})
+		changed = true
+	}
+
 	if storage.Status.State != string(Ready) &&
 		meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
-		r.Recorder.Event(storage, corev1.EventTypeNormal, "ResourcesReady", "Everything should be in sync")
+		if stop, result, err := r.checkConnection(ctx, storage); stop {
+			return stop, result, err
+		}
+		r.Recorder.Event(storage, corev1.EventTypeNormal, events.ReasonResourcesReady, "Everything should be in sync")
 		storage.Status.State = string(Ready)
-		return r.setState(ctx, storage)
+		changed = true
 	}
 
+	if changed {
+		return r.setState(ctx, storage)
+	}
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -172,19 +283,40 @@ func (r *Reconciler) handleResourcesSync(
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step handleResourcesSync")
+	if err := chaos.Inject(ctx, storage, "handleResourcesSync"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	key := types.NamespacedName{Namespace: storage.Namespace, Name: storage.Name}
+	builders := storage.GetResourceBuilders()
+	hash, hashErr := resources.RenderedHash(storage, builders)
+	if hashErr == nil && !r.childDeletions.SyncRequired(key, storage.Status.ResourcesSync) && !resources.ResourcesSyncRequired(
+		storage.Status.ResourcesSync,
+		hash,
+		r.Settings.Get().ResourcesResyncPeriod,
+		time.Now(),
+	) {
+		r.Log.Info("rendered resources are unchanged, skipping")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
 
-	for _, builder := range storage.GetResourceBuilders() {
+	var changed []string
+	for _, builder := range builders {
 		newResource := builder.Placeholder(storage)
 
-		result, err := resources.CreateOrUpdateIgnoreStatus(ctx, r.Client, newResource, func() error {
+		var replicasBefore int32
+		var existed bool
+		result, diff, err := resources.CreateOrUpdateIgnoreStatusWithDiff(ctx, r.Client, newResource, func() error {
 			var err error
 
+			replicasBefore, existed = resources.StatefulSetReplicas(newResource)
+
 			err = builder.Build(newResource)
 			if err != nil {
 				r.Recorder.Event(
 					storage,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					events.ReasonResourcesSyncFailed,
 					fmt.Sprintf("Failed building resources: %s", err),
 				)
 				return err
@@ -194,7 +326,7 @@ func (r *Reconciler) handleResourcesSync(
 				r.Recorder.Event(
 					storage,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					events.ReasonResourcesSyncFailed,
 					fmt.Sprintf("Error setting controller reference for resource: %s", err),
 				)
 				return err
@@ -209,25 +341,75 @@ func (r *Reconciler) handleResourcesSync(
 			newResource.GetNamespace(),
 			newResource.GetName(),
 		)
-		if err != nil {
+		if goerrors.Is(err, resources.ErrRecreating) {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeNormal,
+				events.ReasonResourceRecreated,
+				eventMessage+", recreating to change immutable fields",
+			)
+			return Stop, ctrl.Result{Requeue: true}, nil
+		} else if err != nil {
 			r.Recorder.Event(
 				storage,
 				corev1.EventTypeWarning,
-				"ProvisioningFailed",
+				events.ReasonResourcesSyncFailed,
 				eventMessage+fmt.Sprintf(", failed to sync, error: %s", err),
 			)
-			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			return Stop, ctrl.Result{Requeue: true}, err
 		} else if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated {
+			eventMessage += fmt.Sprintf(", changed, result: %s", result)
+			if len(diff) > 0 {
+				r.Log.Info("resource updated",
+					"kind", reflect.TypeOf(newResource).Elem().Name(),
+					"name", newResource.GetName(),
+					"diff", diff,
+				)
+				if r.Settings.Get().EventDiffs {
+					eventMessage += ", fields: " + resources.FormatDiff(diff)
+				}
+			}
 			r.Recorder.Event(
 				storage,
 				corev1.EventTypeNormal,
-				string(Provisioning),
-				eventMessage+fmt.Sprintf(", changed, result: %s", result),
+				events.ReasonResourceUpdated,
+				eventMessage,
 			)
+			changed = append(changed, fmt.Sprintf("%s %s", newResource.GetName(), result))
+			if replicasAfter, ok := resources.StatefulSetReplicas(newResource); ok && existed && replicasAfter != replicasBefore {
+				storage.Status.History = resources.AppendHistory(
+					storage.Status.History,
+					resources.HistoryActionScaled,
+					resources.HistoryOutcomeSucceeded,
+					storage.Generation,
+					fmt.Sprintf("Scaled from %d to %d nodes", replicasBefore, replicasAfter),
+					time.Now(),
+				)
+			}
 		}
 	}
 	r.Log.Info("resource sync complete")
-	return Continue, ctrl.Result{Requeue: false}, nil
+
+	if len(changed) > 0 {
+		storage.Status.History = resources.AppendHistory(
+			storage.Status.History,
+			resources.HistoryActionResourcesUpdated,
+			resources.HistoryOutcomeSucceeded,
+			storage.Generation,
+			strings.Join(changed, ", "),
+			time.Now(),
+		)
+	}
+
+	if hashErr != nil {
+		r.Log.Error(hashErr, "failed to record resources sync status")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if storage.Status.ResourcesSync != nil && storage.Status.ResourcesSync.Hash != hash {
+		r.Log.Info("rendered resources changed", "hash", hash)
+	}
+	storage.Status.ResourcesSync = resources.NewResourcesSyncStatus(storage, hash, time.Now())
+	return r.setState(ctx, storage)
 }
 
 func (r *Reconciler) runSelfCheck(
@@ -236,10 +418,13 @@ func (r *Reconciler) runSelfCheck(
 	waitForGoodResultWithoutIssues bool,
 ) (bool, ctrl.Result, error) {
 	r.Log.Info("running step runSelfCheck")
+	if err := chaos.Inject(ctx, storage, "runSelfCheck"); err != nil {
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
 	result, err := healthcheck.GetSelfCheckResult(ctx, storage)
 	if err != nil {
 		r.Log.Error(err, "GetSelfCheckResult error")
-		return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, err
+		return Stop, ctrl.Result{}, err
 	}
 
 	eventType := corev1.EventTypeNormal
@@ -250,12 +435,12 @@ func (r *Reconciler) runSelfCheck(
 	r.Recorder.Event(
 		storage,
 		eventType,
-		"SelfCheck",
+		events.ReasonStorageSelfCheck,
 		fmt.Sprintf("SelfCheck result: %s, issues found: %d", result.SelfCheckResult.String(), len(result.IssueLog)),
 	)
 
 	if waitForGoodResultWithoutIssues && result.SelfCheckResult.String() != "GOOD" {
-		return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, err
+		return Stop, ctrl.Result{}, err
 	}
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
@@ -270,17 +455,20 @@ func (r *Reconciler) setState(
 		Name:      storage.Name,
 	}, storageCr)
 	if err != nil {
-		r.Recorder.Event(storageCr, corev1.EventTypeWarning, "ControllerError", "Failed fetching CR before status update")
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		r.Recorder.Event(storageCr, corev1.EventTypeWarning, events.ReasonControllerError, "Failed fetching CR before status update")
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
-	storageCr.Status.State = storage.Status.State
-	storageCr.Status.Conditions = storage.Status.Conditions
+	if storageCr.Status.State != storage.Status.State {
+		now := metav1.Now()
+		storage.Status.StateTransitionTime = &now
+	}
+	storageCr.Status = storage.Status
 
 	err = r.Status().Update(ctx, storageCr)
 	if err != nil {
-		r.Recorder.Event(storageCr, corev1.EventTypeWarning, "ControllerError", fmt.Sprintf("Failed setting status: %s", err))
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		r.Recorder.Event(storageCr, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed setting status: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
 	}
 
 	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/monitoring"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleResourceUsage records CPU and memory usage of the pods in status.
+// Failing to collect the usage never blocks the reconcile.
+func (r *Reconciler) handleResourceUsage(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	settings := r.Settings.Get()
+	if !settings.FeatureEnabled(operatorconfig.FeatureResourceUsageReporting) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleResourceUsage")
+
+	last := storage.Status.ResourceUsage
+	if last != nil && time.Since(last.LastUpdateTime.Time) < settings.ResourceUsageInterval {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	usage, err := monitoring.GetPodsResourceUsage(ctx, r.Client, storage.Namespace, map[string]string{
+		labels.InstanceKey:  storage.Name,
+		labels.ComponentKey: labels.StorageComponent,
+	})
+	if errors.Is(err, monitoring.ErrMetricsAPIUnavailable) {
+		r.Log.Info("metrics API is not available, skipping resource usage")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	if err != nil {
+		r.Log.Error(err, "failed to get pods resource usage")
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	storage.Status.ResourceUsage = usage
+	return r.setState(ctx, storage)
+}
+
+// handleCapacityEstimate records the total resources requested by the pods
+// and volumes in status, the same estimate is shown as a warning on create
+func (r *Reconciler) handleCapacityEstimate(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	r.Log.Info("running step handleCapacityEstimate")
+
+	estimate := storage.EstimateCapacity()
+	if storage.Status.Capacity.Equal(&estimate) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	storage.Status.Capacity = &estimate
+	return r.setState(ctx, storage)
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	NodesUtilizedCondition          = "NodesUtilized"
+	NodesUtilizedReasonAll          = "AllUtilized"
+	NodesUtilizedReasonRemainder    = "Remainder"
+	NodesUtilizedReasonBelowMinimum = "BelowMinimum"
+)
+
+// handleNodesUtilization records in status how many of spec.nodes the
+// erasure utilizes. The nodes over a multiple of the ones the erasure
+// places its groups on add no usable capacity, a warning is emitted when
+// the utilization changes to leave some over.
+func (r *Reconciler) handleNodesUtilization(ctx context.Context, storage *resources.StorageClusterBuilder) (bool, ctrl.Result, error) {
+	utilized := storage.UtilizedNodes()
+	condition := metav1.Condition{
+		Type:    NodesUtilizedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  NodesUtilizedReasonAll,
+		Message: fmt.Sprintf("Erasure %s utilizes all %d storage nodes", storage.Spec.Erasure, storage.Spec.Nodes),
+	}
+	switch {
+	case utilized == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NodesUtilizedReasonBelowMinimum
+		condition.Message = fmt.Sprintf(
+			"Erasure %s requires at least %d storage nodes, %d are set",
+			storage.Spec.Erasure, ydbv1alpha1.MinNodesForErasure(storage.Spec.Erasure), storage.Spec.Nodes,
+		)
+	case utilized < storage.Spec.Nodes:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = NodesUtilizedReasonRemainder
+		condition.Message = fmt.Sprintf(
+			"Erasure %s utilizes the storage nodes in multiples of %d, %d of %d nodes are unutilized",
+			storage.Spec.Erasure, ydbv1alpha1.NodesMultipleForErasure(storage.Spec.Erasure),
+			storage.Spec.Nodes-utilized, storage.Spec.Nodes,
+		)
+	}
+
+	current := meta.FindStatusCondition(storage.Status.Conditions, NodesUtilizedCondition)
+	if storage.Status.UtilizedNodes == utilized && current != nil &&
+		current.Status == condition.Status && current.Message == condition.Message {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleNodesUtilization")
+
+	if condition.Status == metav1.ConditionFalse {
+		r.Recorder.Event(storage, corev1.EventTypeWarning, events.ReasonStorageNodesUnutilized, condition.Message)
+	}
+	condition.ObservedGeneration = storage.Generation
+	meta.SetStatusCondition(&storage.Status.Conditions, condition)
+	storage.Status.UtilizedNodes = utilized
+	return r.setState(ctx, storage)
+}
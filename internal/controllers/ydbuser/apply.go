@@ -0,0 +1,280 @@
+package ydbuser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// applySpec brings the database to the spec. Objects leaving the spec are
+// removed first, grants before the groups and users they refer to. Status
+// follows every change made, so a failure in the middle is resumed from
+// where it stopped. It reports whether anything was changed.
+func (r *Reconciler) applySpec(ctx context.Context, user *ydbv1alpha1.YdbUser, db *scripting.Client) (bool, error) {
+	changed := false
+
+	var grants []ydbv1alpha1.DatabaseGrant
+	for i, grant := range user.Status.Grants {
+		if containsGrant(user.Spec.Grants, grant) {
+			grants = append(grants, grant)
+			continue
+		}
+		if err := db.Revoke(ctx, grantPath(db.Database, grant.Path), grant.Subject, grant.Permissions); err != nil {
+			user.Status.Grants = append(grants, user.Status.Grants[i:]...)
+			return changed, err
+		}
+		changed = true
+	}
+	user.Status.Grants = grants
+
+	var groups []ydbv1alpha1.DatabaseGroup
+	for i, group := range user.Status.Groups {
+		if findGroup(user.Spec.Groups, group.Name) != nil {
+			groups = append(groups, group)
+			continue
+		}
+		if err := db.Execute(ctx, fmt.Sprintf("DROP GROUP IF EXISTS %s;", group.Name)); err != nil {
+			user.Status.Groups = append(groups, user.Status.Groups[i:]...)
+			return changed, err
+		}
+		changed = true
+	}
+	user.Status.Groups = groups
+
+	var users []ydbv1alpha1.AppliedDatabaseUser
+	for i, applied := range user.Status.Users {
+		if findUser(user.Spec.Users, applied.Name) != nil {
+			users = append(users, applied)
+			continue
+		}
+		if err := db.Execute(ctx, fmt.Sprintf("DROP USER IF EXISTS %s;", applied.Name)); err != nil {
+			user.Status.Users = append(users, user.Status.Users[i:]...)
+			return changed, err
+		}
+		changed = true
+	}
+	user.Status.Users = users
+
+	for _, spec := range user.Spec.Users {
+		applied, err := r.applyUser(ctx, user, db, spec)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || applied
+	}
+	for _, spec := range user.Spec.Groups {
+		applied, err := applyGroup(ctx, user, db, spec)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || applied
+	}
+	for _, grant := range user.Spec.Grants {
+		if containsGrant(user.Status.Grants, grant) {
+			continue
+		}
+		if err := db.Grant(ctx, grantPath(db.Database, grant.Path), grant.Subject, grant.Permissions); err != nil {
+			return changed, err
+		}
+		user.Status.Grants = append(user.Status.Grants, grant)
+		changed = true
+	}
+	return changed, nil
+}
+
+// applyUser creates the user or changes its password when the Secret
+// changed. A user existing before is only taken over, by changing its
+// password, when the spec adopts it.
+func (r *Reconciler) applyUser(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+	db *scripting.Client,
+	spec ydbv1alpha1.DatabaseUser,
+) (bool, error) {
+	ref := spec.PasswordSecretRef
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: user.Namespace}, secret)
+	if err != nil {
+		return false, fmt.Errorf("failed to get password of user %s: %w", spec.Name, err)
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return false, fmt.Errorf("key %s not found in Secret %s", ref.Key, ref.Name)
+	}
+
+	applied := findAppliedUser(user.Status.Users, spec.Name)
+	if applied != nil && applied.PasswordVersion == secret.ResourceVersion {
+		return false, nil
+	}
+	alter := fmt.Sprintf("ALTER USER %s PASSWORD %s;", spec.Name, scripting.Quote(string(password)))
+	if applied == nil {
+		create := fmt.Sprintf("CREATE USER %s PASSWORD %s;", spec.Name, scripting.Quote(string(password)))
+		if err := db.Execute(ctx, create); err != nil {
+			if !spec.Adopt {
+				return false, fmt.Errorf("failed to create user %s, set adopt to take over an existing user: %w", spec.Name, err)
+			}
+			if alterErr := db.Execute(ctx, alter); alterErr != nil {
+				return false, fmt.Errorf("failed to create user %s: %w", spec.Name, err)
+			}
+		}
+		user.Status.Users = append(user.Status.Users, ydbv1alpha1.AppliedDatabaseUser{
+			Name:            spec.Name,
+			PasswordVersion: secret.ResourceVersion,
+		})
+		return true, nil
+	}
+	if err := db.Execute(ctx, alter); err != nil {
+		return false, fmt.Errorf("failed to change password of user %s: %w", spec.Name, err)
+	}
+	applied.PasswordVersion = secret.ResourceVersion
+	return true, nil
+}
+
+// applyGroup creates the group and adds and drops members as in spec. A
+// group existing before is only taken over, when members are added to it,
+// when the spec adopts it.
+func applyGroup(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+	db *scripting.Client,
+	spec ydbv1alpha1.DatabaseGroup,
+) (bool, error) {
+	applied := findGroup(user.Status.Groups, spec.Name)
+	var current []string
+	var createErr error
+	if applied == nil {
+		createErr = db.Execute(ctx, fmt.Sprintf("CREATE GROUP %s;", spec.Name))
+		if createErr != nil && !spec.Adopt {
+			return false, fmt.Errorf("failed to create group %s, set adopt to take over an existing group: %w", spec.Name, createErr)
+		}
+		if createErr != nil && len(spec.Members) == 0 {
+			return false, fmt.Errorf("failed to create group %s: %w", spec.Name, createErr)
+		}
+	} else if reflect.DeepEqual(applied.Members, spec.Members) {
+		return false, nil
+	} else {
+		current = applied.Members
+	}
+
+	var added, dropped []string
+	for _, member := range spec.Members {
+		if !contains(current, member) {
+			added = append(added, member)
+		}
+	}
+	for _, member := range current {
+		if !contains(spec.Members, member) {
+			dropped = append(dropped, member)
+		}
+	}
+	if len(added) > 0 {
+		statement := fmt.Sprintf("ALTER GROUP %s ADD USER %s;", spec.Name, strings.Join(added, ", "))
+		if err := db.Execute(ctx, statement); err != nil {
+			if createErr != nil {
+				return false, fmt.Errorf("failed to create group %s: %w", spec.Name, createErr)
+			}
+			return false, fmt.Errorf("failed to add members to group %s: %w", spec.Name, err)
+		}
+	}
+	if len(dropped) > 0 {
+		statement := fmt.Sprintf("ALTER GROUP %s DROP USER %s;", spec.Name, strings.Join(dropped, ", "))
+		if err := db.Execute(ctx, statement); err != nil {
+			// The added members are kept, adding them again is harmless
+			return false, fmt.Errorf("failed to drop members from group %s: %w", spec.Name, err)
+		}
+	}
+
+	members := append([]string(nil), spec.Members...)
+	if applied == nil {
+		user.Status.Groups = append(user.Status.Groups, ydbv1alpha1.DatabaseGroup{Name: spec.Name, Members: members})
+	} else {
+		applied.Members = members
+	}
+	return true, nil
+}
+
+// removeApplied removes everything in status from the database
+func (r *Reconciler) removeApplied(ctx context.Context, user *ydbv1alpha1.YdbUser, db *scripting.Client) error {
+	for len(user.Status.Grants) > 0 {
+		grant := user.Status.Grants[0]
+		if err := db.Revoke(ctx, grantPath(db.Database, grant.Path), grant.Subject, grant.Permissions); err != nil {
+			return err
+		}
+		user.Status.Grants = user.Status.Grants[1:]
+	}
+	for len(user.Status.Groups) > 0 {
+		if err := db.Execute(ctx, fmt.Sprintf("DROP GROUP IF EXISTS %s;", user.Status.Groups[0].Name)); err != nil {
+			return err
+		}
+		user.Status.Groups = user.Status.Groups[1:]
+	}
+	for len(user.Status.Users) > 0 {
+		if err := db.Execute(ctx, fmt.Sprintf("DROP USER IF EXISTS %s;", user.Status.Users[0].Name)); err != nil {
+			return err
+		}
+		user.Status.Users = user.Status.Users[1:]
+	}
+	return nil
+}
+
+// grantPath returns the absolute path of the scheme object of a grant. The
+// path is checked by Validate not to leave the database.
+func grantPath(database, path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return database
+	}
+	return database + "/" + path
+}
+
+func containsGrant(grants []ydbv1alpha1.DatabaseGrant, grant ydbv1alpha1.DatabaseGrant) bool {
+	for _, item := range grants {
+		if reflect.DeepEqual(item, grant) {
+			return true
+		}
+	}
+	return false
+}
+
+func findGroup(groups []ydbv1alpha1.DatabaseGroup, name string) *ydbv1alpha1.DatabaseGroup {
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func findUser(users []ydbv1alpha1.DatabaseUser, name string) *ydbv1alpha1.DatabaseUser {
+	for i := range users {
+		if users[i].Name == name {
+			return &users[i]
+		}
+	}
+	return nil
+}
+
+func findAppliedUser(users []ydbv1alpha1.AppliedDatabaseUser, name string) *ydbv1alpha1.AppliedDatabaseUser {
+	for i := range users {
+		if users[i].Name == name {
+			return &users[i]
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
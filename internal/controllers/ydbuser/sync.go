@@ -0,0 +1,342 @@
+package ydbuser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/events"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatormetrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+const (
+	Pending UserState = "Pending"
+	Ready   UserState = "Ready"
+	Failed  UserState = "Failed"
+
+	StatusUpdateRequeueDelay = 1 * time.Second
+	RetryRequeueDelay        = 1 * time.Minute
+
+	// PasswordCheckInterval is how often the password Secrets of applied
+	// users are checked for changes
+	PasswordCheckInterval = 5 * time.Minute
+
+	AppliedCondition      = "Applied"
+	AppliedReasonApplied  = "Applied"
+	AppliedReasonInvalid  = "Invalid"
+	AppliedReasonRejected = "Rejected"
+
+	Stop     = true
+	Continue = false
+)
+
+type UserState string
+
+// Sync applies the spec to the database and keeps what was applied in
+// status, so the users, groups and grants leaving the spec are removed
+func (r *Reconciler) Sync(ctx context.Context, user *ydbv1alpha1.YdbUser) (ctrl.Result, error) {
+	if user.DeletionTimestamp != nil {
+		result, err := r.handleDeletion(ctx, user)
+		return r.observe(user, "handleDeletion", result, err)
+	}
+
+	stop, result, err := r.handleFinalizer(ctx, user)
+	if stop {
+		return r.observe(user, "handleFinalizer", result, err)
+	}
+	stop, result, err = r.validate(ctx, user)
+	if stop {
+		return r.observe(user, "validate", result, err)
+	}
+	database, stop, result, err := r.waitForDatabase(ctx, user)
+	if stop {
+		return r.observe(user, "waitForDatabase", result, err)
+	}
+	stop, result, err = r.apply(ctx, user, database)
+	if stop {
+		return r.observe(user, "apply", result, err)
+	}
+	return r.observe(user, "", ctrl.Result{RequeueAfter: PasswordCheckInterval}, nil)
+}
+
+// observe exports the step that ended the sync and the state of the users
+func (r *Reconciler) observe(
+	user *ydbv1alpha1.YdbUser,
+	step string,
+	result ctrl.Result,
+	err error,
+) (ctrl.Result, error) {
+	operatormetrics.ObserveStep(operatormetrics.KindYdbUser, user.Namespace, user.Name, step, err)
+	operatormetrics.SetState(operatormetrics.KindYdbUser, user.Namespace, user.Name, user.Status.State)
+	return result, err
+}
+
+// handleFinalizer adds the finalizer that removes the applied users, groups
+// and grants from the database once the YdbUser is deleted
+func (r *Reconciler) handleFinalizer(ctx context.Context, user *ydbv1alpha1.YdbUser) (bool, ctrl.Result, error) {
+	if controllerutil.ContainsFinalizer(user, ydbv1alpha1.YdbUserRemovalFinalizer) {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleFinalizer")
+
+	controllerutil.AddFinalizer(user, ydbv1alpha1.YdbUserRemovalFinalizer)
+	if err := r.Update(ctx, user); err != nil {
+		r.Recorder.Event(
+			user,
+			corev1.EventTypeWarning,
+			events.ReasonControllerError,
+			fmt.Sprintf("Failed to add finalizer: %s", err),
+		)
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
+
+// validate repeats the check of the webhook, which may be disabled. The
+// names are put in YQL statements, an invalid spec is not applied until it
+// changes.
+func (r *Reconciler) validate(ctx context.Context, user *ydbv1alpha1.YdbUser) (bool, ctrl.Result, error) {
+	err := user.Validate()
+	if err == nil {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step validate")
+
+	r.Recorder.Event(user, corev1.EventTypeWarning, events.ReasonYdbUserInvalid, err.Error())
+	stop, result, updateErr := r.setFailed(ctx, user, AppliedReasonInvalid, err.Error())
+	if updateErr != nil {
+		return stop, result, updateErr
+	}
+	return Stop, ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) waitForDatabase(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+) (*resources.DatabaseBuilder, bool, ctrl.Result, error) {
+	r.Log.Info("running step waitForDatabase")
+
+	database, err := r.getDatabase(ctx, user)
+	if err != nil {
+		r.Recorder.Event(
+			user,
+			corev1.EventTypeWarning,
+			events.ReasonYdbUserWaitingForDatabase,
+			fmt.Sprintf("Failed to get Database (%s): %s", user.Spec.DatabaseRef.Name, err),
+		)
+		if apierrors.IsNotFound(err) {
+			return nil, Stop, ctrl.Result{Requeue: true}, nil
+		}
+		return nil, Stop, ctrl.Result{Requeue: true}, err
+	}
+	if !databaseServing(database.Database) {
+		r.Recorder.Event(
+			user,
+			corev1.EventTypeWarning,
+			events.ReasonYdbUserWaitingForDatabase,
+			fmt.Sprintf(
+				"Referenced database (%s, %s) in a bad state: %s != Ready",
+				database.Name,
+				database.Namespace,
+				database.Status.State,
+			),
+		)
+		return nil, Stop, ctrl.Result{Requeue: true}, nil
+	}
+	return database, Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// getDatabase returns the referenced Database along with the shared one
+// serving it when it is serverless. The Database is always looked up in the
+// namespace of the YdbUser.
+func (r *Reconciler) getDatabase(ctx context.Context, user *ydbv1alpha1.YdbUser) (*resources.DatabaseBuilder, error) {
+	databaseCr := &ydbv1alpha1.Database{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      user.Spec.DatabaseRef.Name,
+		Namespace: user.Namespace,
+	}, databaseCr)
+	if err != nil {
+		return nil, err
+	}
+	database := resources.NewDatabase(databaseCr)
+
+	if database.Spec.ServerlessResources != nil {
+		ref := database.Spec.ServerlessResources.SharedDatabaseRef
+		if ref.Namespace == "" {
+			ref.Namespace = database.Namespace
+		}
+		database.SharedDatabase = &ydbv1alpha1.Database{}
+		err = r.Get(ctx, types.NamespacedName{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+		}, database.SharedDatabase)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &database, nil
+}
+
+// databaseServing reports whether the database accepts queries, a degraded
+// database still does
+func databaseServing(database *ydbv1alpha1.Database) bool {
+	return database.Status.State == "Ready" || database.Status.State == "Degraded"
+}
+
+func (r *Reconciler) apply(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	r.Log.Info("running step apply")
+
+	changed, err := r.applySpec(ctx, user, newSchemaClient(database))
+	if err != nil {
+		message := fmt.Sprintf("Failed to apply users to database %s: %s", database.GetPath(), err)
+		r.Recorder.Event(user, corev1.EventTypeWarning, events.ReasonYdbUserFailed, message)
+		if stop, result, updateErr := r.setFailed(ctx, user, AppliedReasonRejected, message); updateErr != nil {
+			return stop, result, updateErr
+		}
+		return Stop, ctrl.Result{RequeueAfter: RetryRequeueDelay}, nil
+	}
+	if !changed && user.Status.State == string(Ready) && user.Status.ObservedGeneration == user.Generation {
+		return Continue, ctrl.Result{Requeue: false}, nil
+	}
+
+	user.Status.State = string(Ready)
+	user.Status.ObservedGeneration = user.Generation
+	user.Status.Message = ""
+	message := fmt.Sprintf(
+		"Applied %d users, %d groups and %d grants to database %s",
+		len(user.Status.Users),
+		len(user.Status.Groups),
+		len(user.Status.Grants),
+		database.GetPath(),
+	)
+	meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:    AppliedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  AppliedReasonApplied,
+		Message: message,
+	})
+	r.Recorder.Event(user, corev1.EventTypeNormal, events.ReasonYdbUserApplied, message)
+	return r.setState(ctx, user)
+}
+
+// handleDeletion removes everything applied from the database and then
+// releases the finalizer. A deleted Database has nothing left to remove, a
+// Failed one cannot be reached and the applied objects are left in it.
+func (r *Reconciler) handleDeletion(ctx context.Context, user *ydbv1alpha1.YdbUser) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(user, ydbv1alpha1.YdbUserRemovalFinalizer) {
+		return ctrl.Result{Requeue: false}, nil
+	}
+	r.Log.Info("running step handleDeletion")
+
+	database, err := r.getDatabase(ctx, user)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err == nil && database.Status.State == "Failed" {
+		r.Recorder.Event(
+			user,
+			corev1.EventTypeWarning,
+			events.ReasonYdbUserRemovalFailed,
+			fmt.Sprintf("Database %s is Failed, the users are left in it", database.Name),
+		)
+	} else if err == nil {
+		if !databaseServing(database.Database) {
+			r.Recorder.Event(
+				user,
+				corev1.EventTypeWarning,
+				events.ReasonYdbUserRemovalFailed,
+				fmt.Sprintf("Database %s is %s, waiting to remove the users", database.Name, database.Status.State),
+			)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if err := r.removeApplied(ctx, user, newSchemaClient(database)); err != nil {
+			message := fmt.Sprintf("Failed to remove users from database %s: %s", database.GetPath(), err)
+			r.Recorder.Event(user, corev1.EventTypeWarning, events.ReasonYdbUserRemovalFailed, message)
+			// Keep the progress, the removed objects are not removed again
+			if _, _, updateErr := r.setState(ctx, user); updateErr != nil {
+				return ctrl.Result{Requeue: true}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: RetryRequeueDelay}, nil
+		}
+		r.Recorder.Event(
+			user,
+			corev1.EventTypeNormal,
+			events.ReasonYdbUserRemoved,
+			fmt.Sprintf("Removed users from database %s", database.GetPath()),
+		)
+	}
+
+	controllerutil.RemoveFinalizer(user, ydbv1alpha1.YdbUserRemovalFinalizer)
+	if err := r.Update(ctx, user); err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{Requeue: false}, nil
+}
+
+func (r *Reconciler) setFailed(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+	reason string,
+	message string,
+) (bool, ctrl.Result, error) {
+	user.Status.State = string(Failed)
+	user.Status.Message = message
+	meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:    AppliedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	return r.setState(ctx, user)
+}
+
+func (r *Reconciler) setState(
+	ctx context.Context,
+	user *ydbv1alpha1.YdbUser,
+) (bool, ctrl.Result, error) {
+	userCr := &ydbv1alpha1.YdbUser{}
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: user.Namespace,
+		Name:      user.Name,
+	}, userCr)
+	if err != nil {
+		r.Recorder.Event(userCr, corev1.EventTypeWarning, events.ReasonControllerError, "Failed fetching CR before status update")
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	userCr.Status = user.Status
+
+	err = r.Status().Update(ctx, userCr)
+	if err != nil {
+		r.Recorder.Event(userCr, corev1.EventTypeWarning, events.ReasonControllerError, fmt.Sprintf("Failed setting status: %s", err))
+		return Stop, ctrl.Result{Requeue: true}, err
+	}
+
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
+
+// newSchemaClient returns the client of the database users are managed with
+func newSchemaClient(database *resources.DatabaseBuilder) *scripting.Client {
+	endpoint, secure := database.GetQueryEndpoint()
+	return &scripting.Client{
+		Endpoint:             endpoint,
+		UseGrpcSecureChannel: secure,
+		Database:             database.GetPath(),
+	}
+}
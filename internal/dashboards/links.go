@@ -0,0 +1,80 @@
+// Package dashboards renders the links to the observability pages of Storage
+// and Database resources from the URL templates in the operator settings.
+package dashboards
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// Target is the data the URL templates are executed with
+type Target struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Database path, empty for Storage
+	Path string
+}
+
+// Render executes the templates, empty templates leave their link out. Nil
+// is returned when there are no links at all.
+func Render(uiTemplate, grafanaTemplate string, target Target) (*v1alpha1.ResourceLinks, error) {
+	ui, err := execute("ui", uiTemplate, target)
+	if err != nil {
+		return nil, err
+	}
+	grafana, err := execute("grafana", grafanaTemplate, target)
+	if err != nil {
+		return nil, err
+	}
+	if ui == "" && grafana == "" {
+		return nil, nil
+	}
+	return &v1alpha1.ResourceLinks{UI: ui, Grafana: grafana}, nil
+}
+
+// Annotate sets the link annotations, removing those of missing links, and
+// reports whether they changed
+func Annotate(annotations map[string]string, links *v1alpha1.ResourceLinks) bool {
+	if links == nil {
+		links = &v1alpha1.ResourceLinks{}
+	}
+	changed := setAnnotation(annotations, v1alpha1.UILinkAnnotation, links.UI)
+	return setAnnotation(annotations, v1alpha1.GrafanaLinkAnnotation, links.Grafana) || changed
+}
+
+// Equal reports whether the links are the same, nil meaning no links
+func Equal(a, b *v1alpha1.ResourceLinks) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func execute(name, text string, target Target) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s URL template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, target); err != nil {
+		return "", fmt.Errorf("failed to execute %s URL template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func setAnnotation(annotations map[string]string, key, value string) bool {
+	current, ok := annotations[key]
+	if value == "" {
+		delete(annotations, key)
+		return ok
+	}
+	annotations[key] = value
+	return current != value
+}
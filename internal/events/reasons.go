@@ -0,0 +1,155 @@
+// Package events defines the reasons of the Kubernetes events emitted by the
+// operator. Reasons are part of the operator API: they are kept stable across
+// releases, so alerting and automation can match on them instead of parsing
+// messages.
+//
+// Reasons are CamelCase words. Reasons specific to one kind of resource start
+// with the kind or the object it manages (Storage, Database, Tenant,
+// Operation, DynamicConfig, YdbUser, OperatorConfig). Unprefixed reasons are
+// shared by Storage and Database, the kind of the involved object tells them
+// apart. Failures end with Failed and are emitted as Warning events.
+//
+// The controllers emit the events through Recorder, which holds the events
+// of every reason within a budget.
+package events
+
+// Storage and Database
+const (
+	// The operator failed to talk to the Kubernetes API
+	ReasonControllerError = "ControllerError"
+
+	// A child object was created or changed
+	ReasonResourceUpdated = "ResourceUpdated"
+	// A child object was deleted to change its immutable fields
+	ReasonResourceRecreated = "ResourceRecreated"
+	// A child object no longer used was deleted
+	ReasonResourceDeleted = "ResourceDeleted"
+	// A child object could not be built or applied
+	ReasonResourcesSyncFailed = "ResourcesSyncFailed"
+
+	// Some pods are not ready or not updated yet
+	ReasonPodsNotReady = "PodsNotReady"
+	// All pods are ready and the resource is initialized
+	ReasonResourcesReady = "ResourcesReady"
+
+	// The resource spent too long in Provisioning or Initializing
+	ReasonStalled = "Stalled"
+
+	// The image was updated to a new patch release by the auto-update policy
+	ReasonAutoUpdated      = "AutoUpdated"
+	ReasonAutoUpdateFailed = "AutoUpdateFailed"
+
+	// The isolated monitoring credentials were registered as a YDB user
+	ReasonMonitoringUserApplied = "MonitoringUserApplied"
+	ReasonMonitoringUserFailed  = "MonitoringUserFailed"
+)
+
+// Storage
+const (
+	ReasonStoragePDisksFormatting = "StoragePDisksFormatting"
+	ReasonStorageSelfCheck        = "StorageSelfCheck"
+	ReasonStorageConnectionFailed = "StorageConnectionFailed"
+
+	ReasonStorageDisasterRecovery         = "StorageDisasterRecovery"
+	ReasonStorageDisasterRecoveryRejected = "StorageDisasterRecoveryRejected"
+
+	ReasonStorageDecommission         = "StorageDecommission"
+	ReasonStorageDecommissionRejected = "StorageDecommissionRejected"
+
+	ReasonStorageSpareNodes = "StorageSpareNodes"
+
+	ReasonStorageDiskAccessDetected = "StorageDiskAccessDetected"
+
+	// spec.nodes is not a multiple of the nodes the erasure utilizes at once
+	ReasonStorageNodesUnutilized = "StorageNodesUnutilized"
+)
+
+// Database
+const (
+	ReasonDatabaseSpecInvalid              = "DatabaseSpecInvalid"
+	ReasonDatabaseWaitingForStorage        = "DatabaseWaitingForStorage"
+	ReasonDatabaseWaitingForSharedDatabase = "DatabaseWaitingForSharedDatabase"
+	ReasonDatabaseQuotaExceeded            = "DatabaseQuotaExceeded"
+	ReasonDatabasePaused                   = "DatabasePaused"
+	ReasonDatabaseResumed                  = "DatabaseResumed"
+	ReasonDatabaseStopped                  = "DatabaseStopped"
+
+	ReasonDatabaseNodeConflict = "DatabaseNodeConflict"
+	ReasonDatabaseNodeReleased = "DatabaseNodeReleased"
+
+	ReasonDatabaseNodesDraining   = "DatabaseNodesDraining"
+	ReasonDatabaseNodesDrained    = "DatabaseNodesDrained"
+	ReasonDatabaseNodeDrainFailed = "DatabaseNodeDrainFailed"
+
+	ReasonDatabaseNodeRestarted        = "DatabaseNodeRestarted"
+	ReasonDatabaseNodeRestartPostponed = "DatabaseNodeRestartPostponed"
+	ReasonDatabaseNodeRestartFailed    = "DatabaseNodeRestartFailed"
+	ReasonDatabaseNodeUnpublished      = "DatabaseNodeUnpublished"
+
+	ReasonDatabaseStorageAutoscaled              = "DatabaseStorageAutoscaled"
+	ReasonDatabaseStorageAutoscalingLimitReached = "DatabaseStorageAutoscalingLimitReached"
+	ReasonDatabaseStorageAutoscalingFailed       = "DatabaseStorageAutoscalingFailed"
+
+	ReasonDatabaseResourcesMigrating        = "DatabaseResourcesMigrating"
+	ReasonDatabaseResourcesMigrated         = "DatabaseResourcesMigrated"
+	ReasonDatabaseResourcesMigrationBlocked = "DatabaseResourcesMigrationBlocked"
+	ReasonDatabaseResourcesMigrationFailed  = "DatabaseResourcesMigrationFailed"
+
+	ReasonDatabaseDegraded  = "DatabaseDegraded"
+	ReasonDatabaseRecovered = "DatabaseRecovered"
+
+	ReasonDatabaseInitScriptApplied = "DatabaseInitScriptApplied"
+	ReasonDatabaseInitScriptFailed  = "DatabaseInitScriptFailed"
+
+	ReasonDatabaseUpgradeStarted = "DatabaseUpgradeStarted"
+	ReasonDatabaseUpgrading      = "DatabaseUpgrading"
+	ReasonDatabaseUpgraded       = "DatabaseUpgraded"
+	ReasonDatabaseUpgradeBlocked = "DatabaseUpgradeBlocked"
+
+	ReasonTenantQueued               = "TenantQueued"
+	ReasonTenantCreating             = "TenantCreating"
+	ReasonTenantCreated              = "TenantCreated"
+	ReasonTenantAdopted              = "TenantAdopted"
+	ReasonTenantInitializationFailed = "TenantInitializationFailed"
+	ReasonTenantAttributesSynced     = "TenantAttributesSynced"
+	ReasonTenantQuotasSynced         = "TenantQuotasSynced"
+	ReasonTenantReadReplicasSynced   = "TenantReadReplicasSynced"
+	ReasonTenantStorageUnitsAdded    = "TenantStorageUnitsAdded"
+	ReasonTenantStorageUnitsFailed   = "TenantStorageUnitsFailed"
+	ReasonTenantRemoved              = "TenantRemoved"
+	ReasonTenantRemovalFailed        = "TenantRemovalFailed"
+	ReasonTenantRetained             = "TenantRetained"
+)
+
+// Operation
+const (
+	ReasonOperationWaitingForStorage = "OperationWaitingForStorage"
+	ReasonOperationRunning           = "OperationRunning"
+	ReasonOperationRetrying          = "OperationRetrying"
+	ReasonOperationSucceeded         = "OperationSucceeded"
+	ReasonOperationFailed            = "OperationFailed"
+)
+
+// DynamicConfig
+const (
+	ReasonDynamicConfigWaitingForStorage = "DynamicConfigWaitingForStorage"
+	ReasonDynamicConfigInvalid           = "DynamicConfigInvalid"
+	ReasonDynamicConfigApplied           = "DynamicConfigApplied"
+	ReasonDynamicConfigFailed            = "DynamicConfigFailed"
+)
+
+// YdbUser
+const (
+	ReasonYdbUserWaitingForDatabase = "YdbUserWaitingForDatabase"
+	ReasonYdbUserInvalid            = "YdbUserInvalid"
+	ReasonYdbUserApplied            = "YdbUserApplied"
+	ReasonYdbUserFailed             = "YdbUserFailed"
+	ReasonYdbUserRemoved            = "YdbUserRemoved"
+	ReasonYdbUserRemovalFailed      = "YdbUserRemovalFailed"
+)
+
+// OperatorConfig
+const (
+	ReasonOperatorConfigApplied = "OperatorConfigApplied"
+	ReasonOperatorConfigIgnored = "OperatorConfigIgnored"
+)
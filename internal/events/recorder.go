@@ -0,0 +1,184 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Budget bounds the number of events of a reason the operator emits for an
+// object in a window
+type Budget struct {
+	// Events emitted in a window, 0 means no limit
+	Events int
+	Window time.Duration
+}
+
+// Budgets are the budget of every reason
+type Budgets struct {
+	// Budget of the reasons not listed in Reasons
+	Default Budget
+	Reasons map[string]Budget
+}
+
+// DefaultBudgets gives tighter budgets to the reasons emitted on every retry
+// of a reconcile, which are the ones to flood during outages of the API
+// server or of the clusters
+func DefaultBudgets() Budgets {
+	retry := Budget{Events: 30, Window: time.Minute}
+	return Budgets{
+		Default: Budget{Events: 60, Window: time.Minute},
+		Reasons: map[string]Budget{
+			ReasonControllerError:                  retry,
+			ReasonResourcesSyncFailed:              retry,
+			ReasonPodsNotReady:                     retry,
+			ReasonStorageConnectionFailed:          retry,
+			ReasonDatabaseWaitingForStorage:        retry,
+			ReasonDatabaseWaitingForSharedDatabase: retry,
+			ReasonOperationWaitingForStorage:       retry,
+			ReasonDynamicConfigWaitingForStorage:   retry,
+			ReasonYdbUserWaitingForDatabase:        retry,
+		},
+	}
+}
+
+func (b Budgets) of(reason string) Budget {
+	if budget, ok := b.Reasons[reason]; ok {
+		return budget
+	}
+	return b.Default
+}
+
+// windowKey identifies the events of a reason for an object
+type windowKey struct {
+	uid    types.UID
+	reason string
+}
+
+// window counts the events of a reason for an object since its start
+type window struct {
+	start      time.Time
+	emitted    int
+	suppressed int
+}
+
+// Recorder is the event recorder of the controllers. Events of an object
+// over the budget of their reason are dropped, and their number is appended
+// to the first event of the reason emitted for the object in the next
+// window, so an object failing on every retry cannot flood etcd, nor use up
+// the budget of the other objects. The type, the reason and the object are
+// mandatory, events missing them are dropped and logged.
+type Recorder struct {
+	recorder record.EventRecorder
+	budgets  Budgets
+
+	mu        sync.Mutex
+	windows   map[windowKey]*window
+	lastSweep time.Time
+}
+
+var _ record.EventRecorder = &Recorder{}
+
+func NewRecorder(recorder record.EventRecorder, budgets Budgets) *Recorder {
+	return &Recorder{
+		recorder: recorder,
+		budgets:  budgets,
+		windows:  map[windowKey]*window{},
+	}
+}
+
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *Recorder) AnnotatedEventf(
+	object runtime.Object,
+	annotations map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if object == nil || reason == "" || (eventtype != corev1.EventTypeNormal && eventtype != corev1.EventTypeWarning) {
+		log.Log.WithName("events").Error(nil, "dropping an event without an object, a reason or a valid type",
+			"type", eventtype, "reason", reason, "message", message)
+		return
+	}
+
+	var uid types.UID
+	if accessor, err := meta.Accessor(object); err == nil {
+		uid = accessor.GetUID()
+	}
+	suppressed, ok := r.admit(windowKey{uid: uid, reason: reason})
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		message += fmt.Sprintf(" (%d more %s events were suppressed by the event budget)", suppressed, reason)
+	}
+	r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+}
+
+// admit counts the event against the budget of the reason for the object.
+// It returns the number of events suppressed in the previous window when
+// the event opens a new one.
+func (r *Recorder) admit(key windowKey) (int, bool) {
+	budget := r.budgets.of(key.reason)
+	if budget.Events <= 0 {
+		return 0, true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+	current, ok := r.windows[key]
+	suppressed := 0
+	if !ok || now.Sub(current.start) >= budget.Window {
+		if ok {
+			suppressed = current.suppressed
+		}
+		current = &window{start: now}
+		r.windows[key] = current
+	}
+	if current.emitted >= budget.Events {
+		current.suppressed++
+		if current.suppressed == 1 {
+			log.Log.WithName("events").Info("event budget exhausted, suppressing events",
+				"uid", key.uid, "reason", key.reason, "events", budget.Events, "window", budget.Window)
+		}
+		return 0, false
+	}
+	current.emitted++
+	return suppressed, true
+}
+
+// sweep forgets the windows of objects that emitted no events for a while,
+// so deleted objects don't pile up. A window with suppressed events is kept
+// for another one, for the count to reach the next event of the object.
+func (r *Recorder) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+	r.lastSweep = now
+	for key, current := range r.windows {
+		expiry := r.budgets.of(key.reason).Window
+		if current.suppressed > 0 {
+			expiry *= 2
+		}
+		if now.Sub(current.start) >= expiry {
+			delete(r.windows, key)
+		}
+	}
+}
@@ -4,14 +4,24 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
+const databaseHeader = "x-ydb-database"
+
 type Client struct {
 	Context context.Context
 	Target  string
+	// PEM encoded CA the server certificate is verified with, the system
+	// store is used when empty
+	CA []byte
+	// Path of the database the request is addressed to, sent in the
+	// x-ydb-database header, required by the database-scoped services
+	Database string
 }
 
 func buildSystemTLSStoreOption() grpc.DialOption {
@@ -23,10 +33,27 @@ func buildSystemTLSStoreOption() grpc.DialOption {
 	return grpc.WithTransportCredentials(tlsCredentials)
 }
 
+func buildCATLSOption(ca []byte) (grpc.DialOption, error) {
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to parse the CA certificate")
+	}
+	tlsCredentials := credentials.NewTLS(&tls.Config{ //nolint
+		RootCAs: certPool,
+	})
+	return grpc.WithTransportCredentials(tlsCredentials), nil
+}
+
 func (client *Client) Invoke(method string, input interface{}, output interface{}, secure bool) error {
 	var opts []grpc.DialOption
 
-	if secure {
+	if secure && len(client.CA) > 0 {
+		opt, err := buildCATLSOption(client.CA)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, opt)
+	} else if secure {
 		opts = append(opts, buildSystemTLSStoreOption())
 	} else {
 		opts = append(opts, grpc.WithInsecure())
@@ -38,7 +65,11 @@ func (client *Client) Invoke(method string, input interface{}, output interface{
 	}
 	defer conn.Close()
 
-	err = conn.Invoke(client.Context, method, input, output)
+	ctx := client.Context
+	if client.Database != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, databaseHeader, client.Database)
+	}
+	err = conn.Invoke(ctx, method, input, output)
 	if err != nil {
 		return err
 	}
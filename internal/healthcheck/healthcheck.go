@@ -15,17 +15,101 @@ const (
 )
 
 func GetSelfCheckResult(ctx context.Context, cluster *resources.StorageClusterBuilder) (*Ydb_Monitoring.SelfCheckResult, error) {
+	return selfCheck(
+		ctx,
+		cluster.GetGRPCEndpoint(),
+		&Ydb_Monitoring.SelfCheckRequest{},
+		cluster.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		nil,
+	)
+}
+
+// CheckConnection calls the storage through its gRPC Service the way clients
+// do, TLS handshake included, with the server certificate verified by the
+// ca. Any self-check reply means the Service routes to a serving node.
+func CheckConnection(ctx context.Context, cluster *resources.StorageClusterBuilder, ca []byte) error {
+	secure := false
+	if tls := cluster.Spec.Service.GRPC.TLSConfiguration; tls != nil {
+		secure = tls.Enabled
+	}
 	client := grpc.Client{
 		Context: ctx,
 		Target:  cluster.GetGRPCEndpoint(),
+		CA:      ca,
+	}
+	return client.Invoke(
+		selfCheckEndpoint,
+		&Ydb_Monitoring.SelfCheckRequest{},
+		&Ydb_Monitoring.SelfCheckResponse{},
+		secure,
+	)
+}
+
+// GetDatabaseSelfCheckResult asks the storage cluster of the database for
+// its self-check and returns the result of the database alone. The
+// database_status entries are only filled in verbose mode.
+func GetDatabaseSelfCheckResult(ctx context.Context, database *resources.DatabaseBuilder) (Ydb_Monitoring.SelfCheck_Result, error) {
+	secure := false
+	if tls := database.Storage.Spec.Service.GRPC.TLSConfiguration; tls != nil {
+		secure = tls.Enabled
+	}
+	result, err := selfCheck(
+		ctx,
+		database.GetStorageEndpoint(),
+		&Ydb_Monitoring.SelfCheckRequest{ReturnVerboseStatus: true},
+		secure,
+		database.StorageCA,
+	)
+	if err != nil {
+		return Ydb_Monitoring.SelfCheck_UNSPECIFIED, err
+	}
+
+	path := database.GetPath()
+	for _, status := range result.DatabaseStatus {
+		if status.Name == path {
+			return databaseResult(status.Overall), nil
+		}
+	}
+	// Clusters not reporting databases separately are judged as a whole
+	return result.SelfCheckResult, nil
+}
+
+// databaseResult maps the overall flag of a database to the self-check
+// result the cluster would report for the same flag
+func databaseResult(flag Ydb_Monitoring.StatusFlag_Status) Ydb_Monitoring.SelfCheck_Result {
+	switch flag {
+	case Ydb_Monitoring.StatusFlag_GREEN:
+		return Ydb_Monitoring.SelfCheck_GOOD
+	case Ydb_Monitoring.StatusFlag_BLUE, Ydb_Monitoring.StatusFlag_YELLOW:
+		return Ydb_Monitoring.SelfCheck_DEGRADED
+	case Ydb_Monitoring.StatusFlag_ORANGE:
+		return Ydb_Monitoring.SelfCheck_MAINTENANCE_REQUIRED
+	case Ydb_Monitoring.StatusFlag_RED:
+		return Ydb_Monitoring.SelfCheck_EMERGENCY
+	default:
+		return Ydb_Monitoring.SelfCheck_UNSPECIFIED
+	}
+}
+
+func selfCheck(
+	ctx context.Context,
+	endpoint string,
+	request *Ydb_Monitoring.SelfCheckRequest,
+	secure bool,
+	ca []byte,
+) (*Ydb_Monitoring.SelfCheckResult, error) {
+	client := grpc.Client{
+		Context: ctx,
+		Target:  endpoint,
+		CA:      ca,
 	}
 
 	response := Ydb_Monitoring.SelfCheckResponse{}
 	err := client.Invoke(
 		selfCheckEndpoint,
-		&Ydb_Monitoring.SelfCheckRequest{},
+		request,
 		&response,
-		cluster.Spec.Service.GRPC.TLSConfiguration.Enabled,
+		secure,
 	)
 
 	result := &Ydb_Monitoring.SelfCheckResult{}
@@ -0,0 +1,79 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Discovery"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/grpc"
+)
+
+const (
+	healthCheckEndpoint   = "/grpc.health.v1.Health/Check"
+	listEndpointsEndpoint = "/Ydb.Discovery.V1.DiscoveryService/ListEndpoints"
+)
+
+var ErrNodeNotRegistered = errors.New("node is not registered in the node broker")
+
+// CheckNodeHealth asks the gRPC health service of a single node whether it
+// is serving
+func CheckNodeHealth(ctx context.Context, endpoint string, secure bool) error {
+	client := grpc.Client{
+		Context: ctx,
+		Target:  endpoint,
+	}
+
+	response := healthpb.HealthCheckResponse{}
+	err := client.Invoke(healthCheckEndpoint, &healthpb.HealthCheckRequest{}, &response, secure)
+	if err != nil {
+		return err
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("node health status is %s", response.Status)
+	}
+	return nil
+}
+
+// CheckNodeRegistered verifies that the dynamic node reachable at endpoint
+// is registered for the database and advertised under host in discovery
+func CheckNodeRegistered(ctx context.Context, endpoint string, secure bool, database, host string) error {
+	_, err := GetNodeID(ctx, endpoint, secure, nil, database, host)
+	return err
+}
+
+// GetNodeID returns the node broker ID of the dynamic node advertised under
+// host in discovery of the database, asking the node at endpoint
+func GetNodeID(ctx context.Context, endpoint string, secure bool, ca []byte, database, host string) (uint32, error) {
+	client := grpc.Client{
+		Context: ctx,
+		Target:  endpoint,
+		CA:      ca,
+	}
+
+	response := Ydb_Discovery.ListEndpointsResponse{}
+	err := client.Invoke(
+		listEndpointsEndpoint,
+		&Ydb_Discovery.ListEndpointsRequest{Database: database},
+		&response,
+		secure,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	result := &Ydb_Discovery.ListEndpointsResult{}
+	if err = proto.Unmarshal(response.GetOperation().GetResult().GetValue(), result); err != nil {
+		return 0, err
+	}
+
+	for _, info := range result.GetEndpoints() {
+		if info.GetAddress() == host {
+			return info.GetNodeId(), nil
+		}
+	}
+	return 0, ErrNodeNotRegistered
+}
@@ -1,6 +1,9 @@
 package labels
 
 import (
+	"crypto/sha256"
+	"fmt"
+
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 )
 
@@ -20,8 +23,19 @@ const (
 	// ServiceComponent The specialization of a Service resource
 	ServiceComponent = "ydb.tech/service-for"
 
+	// DedicatedDatabaseKey The label and taint key marking nodes reserved for a single database
+	DedicatedDatabaseKey = "ydb.tech/dedicated-database"
+
+	// DatabaseStorageKey The label of database pods naming the Storage they run on
+	DatabaseStorageKey = "ydb.tech/storage"
+
+	// ConfigRevisionKey The label of ConfigMap revisions naming the ConfigMap they copy
+	ConfigRevisionKey = "ydb.tech/config-revision-of"
+
 	StorageComponent = "storage-node"
 	DynamicComponent = "dynamic-node"
+	ProxyComponent   = "proxy"
+	UIAuthComponent  = "ui-auth"
 
 	GRPCComponent         = "grpc"
 	InterconnectComponent = "interconnect"
@@ -61,6 +75,27 @@ func DatabaseLabels(database *v1alpha1.Database) Labels {
 	return l
 }
 
+// DedicatedDatabaseValue identifies the database in the DedicatedDatabaseKey
+// label and taint of the nodes reserved for it.
+func DedicatedDatabaseValue(database *v1alpha1.Database) string {
+	return fmt.Sprintf("%s.%s", database.Namespace, database.Name)
+}
+
+// DatabaseStorageValue identifies the Storage of the database in the
+// DatabaseStorageKey label of its pods. Endpoints of external storage
+// clusters do not fit label values, they are identified by a hash.
+func DatabaseStorageValue(database *v1alpha1.Database) string {
+	if external := database.Spec.ExternalStorageEndpoint; external != nil {
+		sum := sha256.Sum256([]byte(external.Endpoint))
+		return fmt.Sprintf("external.%x", sum[:8])
+	}
+	namespace := database.Spec.StorageClusterRef.Namespace
+	if namespace == "" {
+		namespace = database.Namespace
+	}
+	return fmt.Sprintf("%s.%s", namespace, database.Spec.StorageClusterRef.Name)
+}
+
 func (l Labels) AsMap() map[string]string {
 	return l
 }
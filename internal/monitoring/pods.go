@@ -0,0 +1,86 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// The metrics API is optional in a cluster, so pod metrics are read as
+// unstructured objects instead of depending on its client package.
+var podMetricsListGVK = schema.GroupVersionKind{
+	Group:   "metrics.k8s.io",
+	Version: "v1beta1",
+	Kind:    "PodMetricsList",
+}
+
+var ErrMetricsAPIUnavailable = errors.New("metrics API is not available")
+
+type podMetrics struct {
+	Containers []struct {
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// GetPodsResourceUsage sums up CPU and memory usage of the pods matching
+// the labels. ErrMetricsAPIUnavailable is returned when the cluster does
+// not serve the metrics API.
+func GetPodsResourceUsage(
+	ctx context.Context,
+	c client.Reader,
+	namespace string,
+	matchingLabels map[string]string,
+) (*v1alpha1.ResourceUsageStatus, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	err := c.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(matchingLabels))
+	if err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return nil, ErrMetricsAPIUnavailable
+		}
+		return nil, err
+	}
+
+	usage := &v1alpha1.ResourceUsageStatus{
+		CPU:            *resource.NewMilliQuantity(0, resource.DecimalSI),
+		Memory:         *resource.NewQuantity(0, resource.BinarySI),
+		MaxPodCPU:      *resource.NewMilliQuantity(0, resource.DecimalSI),
+		MaxPodMemory:   *resource.NewQuantity(0, resource.BinarySI),
+		LastUpdateTime: metav1.Now(),
+	}
+	for _, item := range list.Items {
+		pod := podMetrics{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pod); err != nil {
+			return nil, err
+		}
+
+		podCPU := resource.NewMilliQuantity(0, resource.DecimalSI)
+		podMemory := resource.NewQuantity(0, resource.BinarySI)
+		for _, container := range pod.Containers {
+			podCPU.Add(*container.Usage.Cpu())
+			podMemory.Add(*container.Usage.Memory())
+		}
+
+		usage.Pods++
+		usage.CPU.Add(*podCPU)
+		usage.Memory.Add(*podMemory)
+		if podCPU.Cmp(usage.MaxPodCPU) > 0 {
+			usage.MaxPodCPU = *podCPU
+		}
+		if podMemory.Cmp(usage.MaxPodMemory) > 0 {
+			usage.MaxPodMemory = *podMemory
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,97 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	tenantInfoPath = "/viewer/json/tenantinfo"
+	requestTimeout = 10 * time.Second
+)
+
+var ErrTenantInfoNotFound = errors.New("tenant info not found")
+
+type tenantInfoResponse struct {
+	TenantInfo []struct {
+		Name                  string      `json:"Name"`
+		StorageAllocatedSize  json.Number `json:"StorageAllocatedSize"`
+		StorageAllocatedLimit json.Number `json:"StorageAllocatedLimit"`
+	} `json:"TenantInfo"`
+}
+
+// StorageUsage is the storage space allocated by a tenant and the
+// limit of its storage units, in bytes.
+type StorageUsage struct {
+	Used  uint64
+	Limit uint64
+}
+
+// Percent returns used space in percents of the limit, zero when the limit is unknown.
+func (u StorageUsage) Percent() int32 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return int32(u.Used * 100 / u.Limit)
+}
+
+// GetTenantStorageUsage queries the viewer of a YDB node at endpoint
+// (host:port of the status service) for the storage usage of the tenant.
+func GetTenantStorageUsage(ctx context.Context, endpoint, path string) (StorageUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	query := url.Values{"path": {path}}
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("http://%s%s?%s", endpoint, tenantInfoPath, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return StorageUsage{}, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return StorageUsage{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return StorageUsage{}, fmt.Errorf("unexpected viewer response status: %s", response.Status)
+	}
+
+	info := tenantInfoResponse{}
+	if err = json.NewDecoder(response.Body).Decode(&info); err != nil {
+		return StorageUsage{}, err
+	}
+
+	for _, tenant := range info.TenantInfo {
+		if tenant.Name != path {
+			continue
+		}
+		usage := StorageUsage{}
+		if tenant.StorageAllocatedSize != "" {
+			used, err := tenant.StorageAllocatedSize.Int64()
+			if err != nil {
+				return StorageUsage{}, err
+			}
+			usage.Used = uint64(used)
+		}
+		if tenant.StorageAllocatedLimit != "" {
+			limit, err := tenant.StorageAllocatedLimit.Int64()
+			if err != nil {
+				return StorageUsage{}, err
+			}
+			usage.Limit = uint64(limit)
+		}
+		return usage, nil
+	}
+	return StorageUsage{}, ErrTenantInfoNotFound
+}
@@ -0,0 +1,21 @@
+package operatorconfig
+
+import (
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+// RequeueRateLimiter returns the rate limiter of a controller queue. The
+// reconciles that fail or wait for another resource are retried after a
+// delay doubling from RequeueBackoffBase up to RequeueBackoffMax, which is
+// reset once a reconcile of the object goes through or makes progress. The
+// overall rate is bounded by the bucket of the controller-runtime default.
+// Every controller needs its own limiter, the delays are kept by the
+// namespaced name of the object.
+func (s Settings) RequeueRateLimiter() ratelimiter.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(s.RequeueBackoffBase, s.RequeueBackoffMax),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
@@ -0,0 +1,170 @@
+package operatorconfig
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// Feature gates known to the operator, all enabled by default
+const (
+	FeatureStorageAutoscaling     = "StorageAutoscaling"
+	FeatureResourceUsageReporting = "ResourceUsageReporting"
+	FeatureDedicatedNodes         = "DedicatedNodes"
+)
+
+var defaultFeatureGates = map[string]bool{
+	FeatureStorageAutoscaling:     true,
+	FeatureResourceUsageReporting: true,
+	FeatureDedicatedNodes:         true,
+}
+
+// Settings are the operator-wide settings used by the controllers
+type Settings struct {
+	StalledThreshold                   time.Duration
+	ResourcesResyncPeriod              time.Duration
+	FinishedJobTTL                     time.Duration
+	ConfigRevisionHistory              int
+	CMSOperationInterval               time.Duration
+	MaxConcurrentTenantInitializations int
+	ResourceUsageInterval              time.Duration
+	PDiskCheckConcurrency              int
+	MaxDatabasesPerNamespace           int
+	ReconcileBudget                    time.Duration
+	RequeueBackoffBase                 time.Duration
+	RequeueBackoffMax                  time.Duration
+	EventDiffs                         bool
+	VersionManifestURL                 string
+	VersionManifestRefreshInterval     time.Duration
+	UIURLTemplate                      string
+	GrafanaURLTemplate                 string
+	FeatureGates                       map[string]bool
+}
+
+// DefaultSettings returns the settings used when the OperatorConfig does not
+// override them
+func DefaultSettings() Settings {
+	return Settings{
+		StalledThreshold:      30 * time.Minute,
+		ResourcesResyncPeriod: 10 * time.Minute,
+		FinishedJobTTL:        24 * time.Hour,
+		ConfigRevisionHistory: 3,
+		CMSOperationInterval:  5 * time.Second,
+		ResourceUsageInterval: 5 * time.Minute,
+		PDiskCheckConcurrency: 10,
+		RequeueBackoffBase:    10 * time.Second,
+		RequeueBackoffMax:     5 * time.Minute,
+
+		VersionManifestRefreshInterval: time.Hour,
+
+		UIURLTemplate: fmt.Sprintf("http://{{.Name}}-status.{{.Namespace}}.svc.cluster.local:%d/monitoring/", v1alpha1.StatusPort),
+	}
+}
+
+// FeatureEnabled reports whether the feature gate is on. Unknown gates are off.
+func (s Settings) FeatureEnabled(name string) bool {
+	if enabled, ok := s.FeatureGates[name]; ok {
+		return enabled
+	}
+	return defaultFeatureGates[name]
+}
+
+// Store holds the settings currently in effect. The base settings are the
+// defaults with the start-only requeue backoff flags and are overridden by
+// the OperatorConfig resource.
+// A nil Store returns DefaultSettings.
+type Store struct {
+	mu      sync.RWMutex
+	base    Settings
+	current Settings
+}
+
+func NewStore(base Settings) *Store {
+	return &Store{base: base, current: base}
+}
+
+func (s *Store) Get() Settings {
+	if s == nil {
+		return DefaultSettings()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Apply replaces the settings in effect with the base ones overridden by
+// config. A nil config restores the base settings.
+func (s *Store) Apply(config *v1alpha1.OperatorConfig) {
+	settings := s.base
+	settings.FeatureGates = map[string]bool{}
+	for name, enabled := range s.base.FeatureGates {
+		settings.FeatureGates[name] = enabled
+	}
+
+	repository, tag := "", ""
+	if config != nil {
+		spec := config.Spec
+		if spec.StalledThreshold != nil {
+			settings.StalledThreshold = spec.StalledThreshold.Duration
+		}
+		if spec.ResourcesResyncPeriod != nil {
+			settings.ResourcesResyncPeriod = spec.ResourcesResyncPeriod.Duration
+		}
+		if spec.FinishedJobTTL != nil {
+			settings.FinishedJobTTL = spec.FinishedJobTTL.Duration
+		}
+		if spec.ConfigRevisionHistory != nil {
+			settings.ConfigRevisionHistory = int(*spec.ConfigRevisionHistory)
+		}
+		if spec.CMSOperationInterval != nil {
+			settings.CMSOperationInterval = spec.CMSOperationInterval.Duration
+		}
+		if spec.MaxConcurrentTenantInitializations != nil {
+			settings.MaxConcurrentTenantInitializations = int(*spec.MaxConcurrentTenantInitializations)
+		}
+		if spec.MaxDatabasesPerNamespace != nil {
+			settings.MaxDatabasesPerNamespace = int(*spec.MaxDatabasesPerNamespace)
+		}
+		if spec.ReconcileBudget != nil {
+			settings.ReconcileBudget = spec.ReconcileBudget.Duration
+		}
+		if spec.EventDiffs != nil {
+			settings.EventDiffs = *spec.EventDiffs
+		}
+		if spec.PDiskCheckConcurrency != nil {
+			settings.PDiskCheckConcurrency = int(*spec.PDiskCheckConcurrency)
+		}
+		if spec.Metrics != nil && spec.Metrics.ResourceUsageInterval != nil {
+			settings.ResourceUsageInterval = spec.Metrics.ResourceUsageInterval.Duration
+		}
+		if spec.VersionManifest != nil {
+			if spec.VersionManifest.URL != "" {
+				settings.VersionManifestURL = spec.VersionManifest.URL
+			}
+			if spec.VersionManifest.RefreshInterval != nil {
+				settings.VersionManifestRefreshInterval = spec.VersionManifest.RefreshInterval.Duration
+			}
+		}
+		if spec.Dashboards != nil {
+			if spec.Dashboards.UIURLTemplate != "" {
+				settings.UIURLTemplate = spec.Dashboards.UIURLTemplate
+			}
+			if spec.Dashboards.GrafanaURLTemplate != "" {
+				settings.GrafanaURLTemplate = spec.Dashboards.GrafanaURLTemplate
+			}
+		}
+		for name, enabled := range spec.FeatureGates {
+			settings.FeatureGates[name] = enabled
+		}
+		if spec.DefaultImage != nil {
+			repository, tag = spec.DefaultImage.Repository, spec.DefaultImage.Tag
+		}
+	}
+	v1alpha1.SetImageDefaults(repository, tag)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = settings
+}
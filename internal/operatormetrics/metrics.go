@@ -0,0 +1,169 @@
+// Package operatormetrics exposes the operator telemetry on the controller
+// manager metrics endpoint. Every series is labeled with the kind, name and
+// namespace of the custom resource it belongs to, so fleets running many
+// clusters can build per-resource SLOs. Series of deleted resources are
+// dropped with Forget.
+package operatormetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	KindStorage       = "Storage"
+	KindDatabase      = "Database"
+	KindOperation     = "Operation"
+	KindDynamicConfig = "DynamicConfig"
+	KindYdbUser       = "YdbUser"
+
+	// StepCompleted is the step label of syncs that ran through all steps
+	StepCompleted = "completed"
+
+	ResultSuccess = "success"
+	ResultRequeue = "requeue"
+	ResultError   = "error"
+)
+
+var (
+	syncSteps = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ydb_operator",
+			Name:      "sync_steps_total",
+			Help:      "Number of syncs by the step that ended them and its result",
+		},
+		[]string{"cr_kind", "cr_name", "namespace", "step", "result"},
+	)
+
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ydb_operator",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of custom resource reconciles",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"cr_kind", "cr_name", "namespace", "result"},
+	)
+
+	resourceState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ydb_operator",
+			Name:      "resource_state",
+			Help:      "Current state of the custom resource, 1 for the state it is in",
+		},
+		[]string{"cr_kind", "cr_name", "namespace", "state"},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(syncSteps, reconcileDuration, resourceState)
+}
+
+type resourceKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// resourceSeries remembers the label values used for a resource, as series
+// can only be deleted by their full label set
+type resourceSeries struct {
+	steps   map[[2]string]struct{}
+	results map[string]struct{}
+	state   string
+}
+
+var (
+	mu     sync.Mutex
+	series = map[resourceKey]*resourceSeries{}
+)
+
+func seriesOf(key resourceKey) *resourceSeries {
+	s, ok := series[key]
+	if !ok {
+		s = &resourceSeries{
+			steps:   map[[2]string]struct{}{},
+			results: map[string]struct{}{},
+		}
+		series[key] = s
+	}
+	return s
+}
+
+// ObserveStep counts a sync that ended at step. An empty step means the
+// sync ran through all steps.
+func ObserveStep(kind, namespace, name, step string, err error) {
+	result := ResultRequeue
+	switch {
+	case err != nil:
+		result = ResultError
+	case step == "":
+		result = ResultSuccess
+	}
+	if step == "" {
+		step = StepCompleted
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	seriesOf(resourceKey{kind, namespace, name}).steps[[2]string{step, result}] = struct{}{}
+	syncSteps.WithLabelValues(kind, name, namespace, step, result).Inc()
+}
+
+// ObserveReconcile records the duration of a reconcile
+func ObserveReconcile(kind, namespace, name string, duration time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	seriesOf(resourceKey{kind, namespace, name}).results[result] = struct{}{}
+	reconcileDuration.WithLabelValues(kind, name, namespace, result).Observe(duration.Seconds())
+}
+
+// SetState exports the current state of the resource, replacing the
+// previous one
+func SetState(kind, namespace, name, state string) {
+	if state == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	s := seriesOf(resourceKey{kind, namespace, name})
+	if s.state == state {
+		return
+	}
+	if s.state != "" {
+		resourceState.DeleteLabelValues(kind, name, namespace, s.state)
+	}
+	s.state = state
+	resourceState.WithLabelValues(kind, name, namespace, state).Set(1)
+}
+
+// Forget drops all series of a deleted resource
+func Forget(kind, namespace, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := resourceKey{kind, namespace, name}
+	s, ok := series[key]
+	if !ok {
+		return
+	}
+	for labels := range s.steps {
+		syncSteps.DeleteLabelValues(kind, name, namespace, labels[0], labels[1])
+	}
+	for result := range s.results {
+		reconcileDuration.DeleteLabelValues(kind, name, namespace, result)
+	}
+	if s.state != "" {
+		resourceState.DeleteLabelValues(kind, name, namespace, s.state)
+	}
+	delete(series, key)
+}
@@ -0,0 +1,237 @@
+// Package preflight checks that a Kubernetes cluster meets the
+// prerequisites of YDB before a Storage is created in it, so problems are
+// reported with what to do about them rather than as pods that never start.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "Pass"
+	StatusWarn Status = "Warn"
+	StatusFail Status = "Fail"
+	StatusSkip Status = "Skip"
+)
+
+const (
+	// Oldest Kubernetes version the operator supports
+	MinKubernetesVersion = "1.20.0"
+
+	// ydbd images are built for x86-64 CPUs with SSE4.2
+	requiredArchitecture = "amd64"
+	// Label node-feature-discovery sets on nodes whose CPUs have SSE4.2
+	sse42Label                    = "feature.node.kubernetes.io/cpu-cpuid.SSE42"
+	nfdLabelPrefix                = "feature.node.kubernetes.io/"
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// Result is the outcome of a single check, Message tells what was found
+// and, for failures, what to do about it
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+}
+
+// Failed reports whether any of the results is a failure
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=list
+
+// Run performs all the checks against the cluster of the config. An error is
+// returned only when the cluster can't be reached at all, failed checks are
+// reported in the results.
+func Run(ctx context.Context, config *rest.Config, c client.Reader) ([]Result, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	serverVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Kubernetes version: %w", err)
+	}
+
+	results := []Result{checkKubernetesVersion(serverVersion.GitVersion)}
+
+	storageClasses := &storagev1.StorageClassList{}
+	if err := c.List(ctx, storageClasses); err != nil {
+		results = append(results, Result{"storage-classes", StatusSkip, fmt.Sprintf("Failed to list StorageClasses: %s", err)})
+	} else {
+		results = append(results, checkStorageClasses(storageClasses.Items))
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		message := fmt.Sprintf("Failed to list nodes: %s", err)
+		return append(results,
+			Result{"cpu-architecture", StatusSkip, message},
+			Result{"cpu-features", StatusSkip, message},
+			Result{"hugepages", StatusSkip, message},
+			checkSysctls(),
+		), nil
+	}
+	return append(results,
+		checkArchitecture(nodes.Items),
+		checkCPUFeatures(nodes.Items),
+		checkHugePages(nodes.Items),
+		checkSysctls(),
+	), nil
+}
+
+func checkKubernetesVersion(gitVersion string) Result {
+	result := Result{Check: "kubernetes-version"}
+	current, err := version.ParseGeneric(gitVersion)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Failed to parse Kubernetes version %q: %s", gitVersion, err)
+		return result
+	}
+	if current.LessThan(version.MustParseGeneric(MinKubernetesVersion)) {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Kubernetes %s is older than %s, upgrade the cluster", gitVersion, MinKubernetesVersion)
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Kubernetes %s", gitVersion)
+	return result
+}
+
+func checkStorageClasses(storageClasses []storagev1.StorageClass) Result {
+	result := Result{Check: "storage-classes"}
+	if len(storageClasses) == 0 {
+		result.Status = StatusFail
+		result.Message = "No StorageClass found, create one for the volumes of spec.dataStore or " +
+			"reference pre-provisioned PersistentVolumes"
+		return result
+	}
+
+	var names, defaults []string
+	for _, storageClass := range storageClasses {
+		names = append(names, storageClass.Name)
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" {
+			defaults = append(defaults, storageClass.Name)
+		}
+	}
+	sort.Strings(names)
+	if len(defaults) == 0 {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("No default StorageClass, set storageClassName in spec.dataStore to one of: %s",
+			strings.Join(names, ", "))
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("StorageClasses: %s, default: %s", strings.Join(names, ", "), strings.Join(defaults, ", "))
+	return result
+}
+
+func checkArchitecture(nodes []corev1.Node) Result {
+	result := Result{Check: "cpu-architecture"}
+	var others []string
+	for _, node := range nodes {
+		if arch := node.Labels[corev1.LabelArchStable]; arch != requiredArchitecture {
+			others = append(others, fmt.Sprintf("%s (%s)", node.Name, arch))
+		}
+	}
+	switch {
+	case len(nodes) == 0:
+		result.Status = StatusFail
+		result.Message = "No nodes found"
+	case len(others) == len(nodes):
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("No %s nodes to run ydbd on, found: %s", requiredArchitecture, strings.Join(others, ", "))
+	case len(others) > 0:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("ydbd can't run on nodes %s, keep YDB pods off them with spec.nodeSelector",
+			strings.Join(others, ", "))
+	default:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("All %d nodes are %s", len(nodes), requiredArchitecture)
+	}
+	return result
+}
+
+// checkCPUFeatures relies on the labels of node-feature-discovery, the
+// CPU flags of the nodes are not published by Kubernetes itself
+func checkCPUFeatures(nodes []corev1.Node) Result {
+	result := Result{Check: "cpu-features"}
+	labeled := false
+	var missing []string
+	for _, node := range nodes {
+		if node.Labels[corev1.LabelArchStable] != requiredArchitecture {
+			continue
+		}
+		for label := range node.Labels {
+			if strings.HasPrefix(label, nfdLabelPrefix) {
+				labeled = true
+				break
+			}
+		}
+		if node.Labels[sse42Label] != "true" {
+			missing = append(missing, node.Name)
+		}
+	}
+	switch {
+	case !labeled:
+		result.Status = StatusSkip
+		result.Message = "No node-feature-discovery labels, install it to verify the nodes support SSE4.2"
+	case len(missing) > 0:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("ydbd needs SSE4.2, not reported by nodes %s", strings.Join(missing, ", "))
+	default:
+		result.Status = StatusPass
+		result.Message = "All nodes support SSE4.2"
+	}
+	return result
+}
+
+func checkHugePages(nodes []corev1.Node) Result {
+	result := Result{Check: "hugepages"}
+	var offering []string
+	for _, node := range nodes {
+		for name, quantity := range node.Status.Allocatable {
+			if strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) && !quantity.IsZero() {
+				offering = append(offering, fmt.Sprintf("%s (%s %s)", node.Name, name, quantity.String()))
+			}
+		}
+	}
+	if len(offering) == 0 {
+		result.Status = StatusWarn
+		result.Message = "No node offers hugepages, pods of Storages with spec.hugePages won't be scheduled"
+		return result
+	}
+	sort.Strings(offering)
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Hugepages offered by %s", strings.Join(offering, ", "))
+	return result
+}
+
+// checkSysctls only explains itself: kernel settings of the nodes are not
+// visible through the Kubernetes API
+func checkSysctls() Result {
+	return Result{
+		Check:  "sysctls",
+		Status: StatusSkip,
+		Message: "Node sysctls are not visible through the API, unsafe sysctls set for YDB pods " +
+			"have to be allowed with the kubelet --allowed-unsafe-sysctls flag, or set with spec.sysctls.mode InitContainer",
+	}
+}
@@ -0,0 +1,70 @@
+// Package probes provides the readiness checks of the operator, so that its
+// own rollouts can be gated on the controllers being able to work rather
+// than on the process being started. Each check is served on
+// /readyz/<name> as well as on /readyz.
+package probes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// checkTimeout bounds each check, probes must answer quickly
+const checkTimeout = 2 * time.Second
+
+// InformerSynced passes once the informer of the kind of obj has synced, i.e.
+// the controller watching it sees the current state of the cluster
+func InformerSynced(c cache.Cache, obj client.Object) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		informer, err := c.GetInformer(ctx, obj)
+		if err != nil {
+			return err
+		}
+		if !informer.HasSynced() {
+			return fmt.Errorf("informer of %T has not synced", obj)
+		}
+		return nil
+	}
+}
+
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+
+// CRDEstablished passes once the CustomResourceDefinition is established
+func CRDEstablished(reader client.Reader, name string) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+		for _, condition := range crd.Status.Conditions {
+			if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+				return nil
+			}
+		}
+		return fmt.Errorf("CustomResourceDefinition %s is not established", name)
+	}
+}
+
+// All passes when every check passes, reporting the first failure
+func All(checks ...healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		for _, check := range checks {
+			if err := check(req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
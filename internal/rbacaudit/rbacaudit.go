@@ -0,0 +1,195 @@
+// Package rbacaudit records the API requests the operator makes and renders
+// the minimal RBAC rules allowing them, so the broad default role can be
+// replaced with one granting only what a deployment actually uses.
+//
+//	GET /rbac-audit                   ClusterRole with all recorded requests
+//	GET /rbac-audit?namespace=<name>  Role with the requests in the namespace
+//
+// The rules only cover the code paths exercised while recording, so the
+// operator should run through the whole lifecycle of its resources first.
+// Discovery requests are not recorded, every authenticated user may make
+// them.
+package rbacaudit
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	Path = "/rbac-audit"
+
+	// Name of the rendered role
+	RoleName = "ydb-operator-minimal"
+)
+
+type request struct {
+	Namespace string
+	Group     string
+	Resource  string
+	Verb      string
+}
+
+// Recorder keeps the distinct requests made through the transports it wraps
+type Recorder struct {
+	mu       sync.Mutex
+	requests map[request]struct{}
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{requests: map[request]struct{}{}}
+}
+
+// Wrap records the requests going through the transport, use it as the
+// WrapTransport of the rest config the clients are built from
+func (r *Recorder) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if parsed, ok := parseRequest(req); ok {
+			r.mu.Lock()
+			r.requests[parsed] = struct{}{}
+			r.mu.Unlock()
+		}
+		return rt.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// parseRequest maps the URL and method of a request to the RBAC verb and
+// resource, e.g. GET /apis/apps/v1/namespaces/ns/statefulsets is list of
+// statefulsets.apps in ns
+func parseRequest(req *http.Request) (request, bool) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var parsed request
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		parsed.Group = parts[1]
+		parts = parts[3:]
+	default:
+		return parsed, false
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		parsed.Namespace = parts[1]
+		parts = parts[2:]
+	}
+
+	// resource, resource/name or resource/name/subresource
+	parsed.Resource = parts[0]
+	named := len(parts) > 1
+	if len(parts) > 2 {
+		parsed.Resource += "/" + parts[2]
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			parsed.Verb = "watch"
+		case named:
+			parsed.Verb = "get"
+		default:
+			parsed.Verb = "list"
+		}
+	case http.MethodPost:
+		parsed.Verb = "create"
+	case http.MethodPut:
+		parsed.Verb = "update"
+	case http.MethodPatch:
+		parsed.Verb = "patch"
+	case http.MethodDelete:
+		parsed.Verb = "delete"
+		if !named {
+			parsed.Verb = "deletecollection"
+		}
+	default:
+		return parsed, false
+	}
+	return parsed, true
+}
+
+// Rules returns the rules allowing the recorded requests, all of them when
+// namespace is empty. Resources of a group needing the same verbs share a
+// rule.
+func (r *Recorder) Rules(namespace string) []rbacv1.PolicyRule {
+	verbs := map[[2]string]map[string]bool{}
+	r.mu.Lock()
+	for req := range r.requests {
+		if namespace != "" && req.Namespace != namespace {
+			continue
+		}
+		key := [2]string{req.Group, req.Resource}
+		if verbs[key] == nil {
+			verbs[key] = map[string]bool{}
+		}
+		verbs[key][req.Verb] = true
+	}
+	r.mu.Unlock()
+
+	rules := map[string]*rbacv1.PolicyRule{}
+	for key, set := range verbs {
+		var list []string
+		for verb := range set {
+			list = append(list, verb)
+		}
+		sort.Strings(list)
+		id := key[0] + " " + strings.Join(list, ",")
+		if rules[id] == nil {
+			rules[id] = &rbacv1.PolicyRule{APIGroups: []string{key[0]}, Verbs: list}
+		}
+		rules[id].Resources = append(rules[id].Resources, key[1])
+	}
+
+	var ids []string
+	for id := range rules {
+		sort.Strings(rules[id].Resources)
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	result := make([]rbacv1.PolicyRule, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, *rules[id])
+	}
+	return result
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var role interface{}
+	namespace := req.URL.Query().Get("namespace")
+	if namespace == "" {
+		role = &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: RoleName},
+			Rules:      r.Rules(""),
+		}
+	} else {
+		role = &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: RoleName, Namespace: namespace},
+			Rules:      r.Rules(namespace),
+		}
+	}
+	data, err := yaml.Marshal(role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(data)
+}
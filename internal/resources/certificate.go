@@ -0,0 +1,311 @@
+package resources
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+const (
+	tlsCAKey          = "ca.crt"
+	tlsCAPrivateKey   = "ca.key"
+	tlsCertificateKey = "tls.crt"
+	tlsPrivateKey     = "tls.key"
+
+	// tlsTopologyAnnotation holds the checksum of the service names in the
+	// operator-managed certificates, pods are restarted to load a
+	// certificate reissued for new names
+	tlsTopologyAnnotation = "ydb.tech/tls-topology"
+
+	caValidity          = 10 * 365 * 24 * time.Hour
+	certificateValidity = 365 * 24 * time.Hour
+	certificateRenewal  = 30 * 24 * time.Hour
+)
+
+// CertificateSecretBuilder keeps the certificate shared by the operator-managed
+// TLS services of a Storage or Database. The certificate is reissued from the same CA whenever the DNS
+// names it has to cover change, e.g. when nodes are added or the external
+// host changes, and some time before it expires.
+type CertificateSecretBuilder struct {
+	client.Object
+
+	DNSNames []string
+	Labels   map[string]string
+	// Secret of the CA to issue the certificate from, a CA of its own is
+	// generated when empty
+	Issuer *corev1.Secret
+	// Rotation reissues the certificate with a new key on request, the CA
+	// is kept so that clients go on trusting it
+	Rotation CredentialsRotation
+}
+
+func (b *CertificateSecretBuilder) Build(obj client.Object) error {
+	sec, ok := obj.(*corev1.Secret)
+	if !ok {
+		return errors.New("failed to cast to Secret object")
+	}
+
+	if sec.ObjectMeta.Name == "" {
+		sec.ObjectMeta.Name = TLSSecretName(b.GetName())
+	}
+	sec.ObjectMeta.Namespace = b.GetNamespace()
+	sec.Labels = b.Labels
+	sec.Type = corev1.SecretTypeOpaque
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+
+	var ca *x509.Certificate
+	var caKey *ecdsa.PrivateKey
+	var err error
+	if b.Issuer != nil {
+		ca, caKey, err = parseKeyPair(b.Issuer.Data[tlsCAKey], b.Issuer.Data[tlsCAPrivateKey])
+		if err != nil {
+			return fmt.Errorf("failed to load CA from secret %s: %w", b.Issuer.Name, err)
+		}
+		sec.Data[tlsCAKey] = b.Issuer.Data[tlsCAKey]
+		delete(sec.Data, tlsCAPrivateKey)
+	} else {
+		ca, caKey, err = parseKeyPair(sec.Data[tlsCAKey], sec.Data[tlsCAPrivateKey])
+	}
+	if err != nil {
+		ca, caKey, err = issueCA(b.GetName())
+		if err != nil {
+			return err
+		}
+		sec.Data[tlsCAKey] = encodeCertificate(ca)
+		sec.Data[tlsCAPrivateKey], err = encodePrivateKey(caKey)
+		if err != nil {
+			return err
+		}
+		delete(sec.Data, tlsCertificateKey)
+	}
+
+	now := time.Now()
+	if certificateValid(sec.Data[tlsCertificateKey], ca, b.DNSNames) && !b.Rotation.due(sec, now) {
+		return nil
+	}
+	certificate, key, err := issueCertificate(ca, caKey, b.DNSNames)
+	if err != nil {
+		return err
+	}
+	b.Rotation.record(sec, now)
+	sec.Data[tlsCertificateKey] = encodeCertificate(certificate)
+	sec.Data[tlsPrivateKey], err = encodePrivateKey(key)
+	return err
+}
+
+func (b *CertificateSecretBuilder) Placeholder(cr client.Object) client.Object {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TLSSecretName(cr.GetName()),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+// tlsDNSNames returns the names of the services of the resource and, apart,
+// of its pods. Adding pods only extends the second list.
+func tlsDNSNames(name, namespace string, nodes int32, externalHost string) ([]string, []string) {
+	var services []string
+	for _, format := range []string{grpcServiceNameFormat, interconnectServiceNameFormat, datastreamsServiceNameFormat} {
+		service := fmt.Sprintf(format, name)
+		services = append(services,
+			service,
+			fmt.Sprintf("%s.%s", service, namespace),
+			fmt.Sprintf("%s.%s.svc", service, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+		)
+	}
+	podsHost := fmt.Sprintf(interconnectServiceNameFormat+".%s.svc.cluster.local", name, namespace)
+	if externalHost != "" {
+		services = append(services, externalHost)
+	}
+
+	pods := make([]string, 0, 2*nodes)
+	for i := int32(0); i < nodes; i++ {
+		pods = append(pods, fmt.Sprintf("%s-%d.%s", name, i, podsHost))
+		if externalHost != "" {
+			pods = append(pods, fmt.Sprintf("%s-%d.%s", name, i, externalHost))
+		}
+	}
+	return services, pods
+}
+
+// tlsTopologyChecksum changes when the services in the certificates change,
+// new pods get the reissued certificate on start without restarting the rest
+func tlsTopologyChecksum(services []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(services, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func tlsManaged(configurations ...*v1alpha1.TLSConfiguration) bool {
+	for _, configuration := range configurations {
+		if configuration != nil && configuration.Enabled && configuration.OperatorManaged {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSSecretName returns the name of the Secret with the operator-managed
+// certificate of a Storage or Database
+func TLSSecretName(owner string) string {
+	return fmt.Sprintf(tlsSecretNameFormat, owner)
+}
+
+// TLSCertificateAuthority returns the PEM encoded CA certificate kept in the
+// Secret of an operator-managed certificate
+func TLSCertificateAuthority(secret *corev1.Secret) []byte {
+	return secret.Data[tlsCAKey]
+}
+
+// StorageTLSManaged reports whether the operator manages the certificate of
+// any TLS service of the storage
+func StorageTLSManaged(storage *v1alpha1.Storage) bool {
+	return tlsManaged(storage.Spec.Service.GRPC.TLSConfiguration, storage.Spec.Service.Interconnect.TLSConfiguration)
+}
+
+// DatabaseTLSManaged reports whether the operator manages the certificate of
+// any TLS service of the database
+func DatabaseTLSManaged(database *v1alpha1.Database) bool {
+	return tlsManaged(
+		database.Spec.Service.GRPC.TLSConfiguration,
+		database.Spec.Service.Interconnect.TLSConfiguration,
+		database.Spec.Service.Datastreams.TLSConfiguration,
+	)
+}
+
+// tlsTopologyAnnotations adds the checksum of the certificate services to the
+// pod annotations, so that renaming a service restarts the pods
+func tlsTopologyAnnotations(annotations map[string]string, services []string) map[string]string {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[tlsTopologyAnnotation] = tlsTopologyChecksum(services)
+	return annotations
+}
+
+func certificateValid(data []byte, ca *x509.Certificate, dnsNames []string) bool {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	if certificate.CheckSignatureFrom(ca) != nil || time.Until(certificate.NotAfter) < certificateRenewal {
+		return false
+	}
+
+	current := append([]string{}, certificate.DNSNames...)
+	desired := append([]string{}, dnsNames...)
+	sort.Strings(current)
+	sort.Strings(desired)
+	return strings.Join(current, ",") == strings.Join(desired, ",")
+}
+
+func issueCA(name string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s CA", name)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(der)
+	return ca, key, err
+}
+
+func issueCertificate(ca *x509.Certificate, caKey *ecdsa.PrivateKey, dnsNames []string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		// Interconnect uses the same certificate on both ends
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	certificate, err := x509.ParseCertificate(der)
+	return certificate, key, err
+}
+
+func parseKeyPair(certificateData, keyData []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certificateBlock, _ := pem.Decode(certificateData)
+	keyBlock, _ := pem.Decode(keyData)
+	if certificateBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New("missing key pair")
+	}
+	certificate, err := x509.ParseCertificate(certificateBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certificate, key, nil
+}
+
+func encodeCertificate(certificate *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+}
+
+func encodePrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,11 +1,17 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 )
 
 type ConfigMapBuilder struct {
@@ -41,3 +47,47 @@ func (b *ConfigMapBuilder) Placeholder(cr client.Object) client.Object {
 		},
 	}
 }
+
+// ConfigRevisionBuilder keeps a copy of the ConfigMap of ConfigMapBuilder
+// named after the hash of its data, so a new revision is created whenever
+// the data changes. The superseded revisions are the history pruned by
+// Garbage.
+type ConfigRevisionBuilder struct {
+	ConfigMapBuilder
+}
+
+func (b *ConfigRevisionBuilder) Build(obj client.Object) error {
+	if err := b.ConfigMapBuilder.Build(obj); err != nil {
+		return err
+	}
+	revisionLabels := labels.Labels{}
+	revisionLabels.Merge(b.Labels)
+	revisionLabels.Merge(map[string]string{labels.ConfigRevisionKey: b.Name})
+	obj.SetLabels(revisionLabels)
+	return nil
+}
+
+func (b *ConfigRevisionBuilder) Placeholder(cr client.Object) client.Object {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configRevisionName(b.Name, b.Data),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+// configRevisionName returns the name of the revision of the ConfigMap with
+// the data
+func configRevisionName(name string, data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s\x00%s\x00", key, data[key])
+	}
+	return fmt.Sprintf("%s-rev-%s", name, hex.EncodeToString(hash.Sum(nil))[:10])
+}
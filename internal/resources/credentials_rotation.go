@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// CredentialsRotatedAtAnnotation keeps the time the credentials of a
+// generated Secret were generated last
+const CredentialsRotatedAtAnnotation = "ydb.tech/credentials-rotated-at"
+
+// CredentialsRotation decides when the credentials generated into a Secret
+// are regenerated. The Secret keeps the request it was last rotated for in
+// api.RotateCredentialsAnnotation, so a request is served once.
+type CredentialsRotation struct {
+	// Value of api.RotateCredentialsAnnotation of the owner
+	Request string
+	// Regenerate the credentials once they are older, never when zero
+	Interval time.Duration
+}
+
+// credentialsRotation reads the rotation request of the owner
+func credentialsRotation(owner client.Object, interval *metav1.Duration) CredentialsRotation {
+	rotation := CredentialsRotation{Request: owner.GetAnnotations()[api.RotateCredentialsAnnotation]}
+	if interval != nil {
+		rotation.Interval = interval.Duration
+	}
+	return rotation
+}
+
+// due reports whether the credentials in the Secret have to be regenerated.
+// Secrets generated before the rotation was introduced count from their
+// creation.
+func (r CredentialsRotation) due(sec *corev1.Secret, now time.Time) bool {
+	if r.Request != "" && sec.Annotations[api.RotateCredentialsAnnotation] != r.Request {
+		return true
+	}
+	if r.Interval <= 0 {
+		return false
+	}
+	rotatedAt := sec.CreationTimestamp.Time
+	if value, ok := sec.Annotations[CredentialsRotatedAtAnnotation]; ok {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			rotatedAt = parsed
+		}
+	}
+	return now.Sub(rotatedAt) >= r.Interval
+}
+
+// record marks the credentials in the Secret as generated now
+func (r CredentialsRotation) record(sec *corev1.Secret, now time.Time) {
+	if sec.Annotations == nil {
+		sec.Annotations = map[string]string{}
+	}
+	sec.Annotations[CredentialsRotatedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	if r.Request != "" {
+		sec.Annotations[api.RotateCredentialsAnnotation] = r.Request
+	}
+}
+
+// credentialsRotationAnnotations copies the rotation request of the owner to
+// the pod annotations, so that the pods restart to load the regenerated
+// certificate. The certificate Secret is updated before the pods.
+func credentialsRotationAnnotations(annotations map[string]string, owner client.Object) map[string]string {
+	request := owner.GetAnnotations()[api.RotateCredentialsAnnotation]
+	if request == "" {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[api.RotateCredentialsAnnotation] = request
+	return annotations
+}
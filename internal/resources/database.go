@@ -1,7 +1,9 @@
 package resources
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,9 +14,21 @@ import (
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
 )
 
+var ErrUnknownStorageUnitKind = errors.New("unknown storage unit kind")
+
 type DatabaseBuilder struct {
 	*api.Database
-	Storage *api.Storage
+	Storage        *api.Storage
+	SharedDatabase *api.Database
+	// Secret with the CA of the storage when its certificates are managed
+	// by the operator
+	CertificateIssuer *corev1.Secret
+	// PEM encoded CA of the external storage cluster, the system store is
+	// used when empty
+	StorageCA []byte
+	// Keep a revision of the configuration ConfigMap when positive, see
+	// ConfigRevisionBuilder
+	ConfigRevisionHistory int
 }
 
 func NewDatabase(ydbCr *api.Database) DatabaseBuilder {
@@ -22,7 +36,7 @@ func NewDatabase(ydbCr *api.Database) DatabaseBuilder {
 
 	api.SetDatabaseSpecDefaults(cr, &cr.Spec)
 
-	return DatabaseBuilder{Database: cr, Storage: nil}
+	return DatabaseBuilder{Database: cr, Storage: nil, SharedDatabase: nil}
 }
 
 func (b *DatabaseBuilder) SetStatusOnFirstReconcile() bool {
@@ -48,6 +62,9 @@ func (b *DatabaseBuilder) GetStorageEndpointWithProto() string {
 }
 
 func (b *DatabaseBuilder) GetStorageEndpoint() string {
+	if b.Spec.ExternalStorageEndpoint != nil {
+		return b.Spec.ExternalStorageEndpoint.Endpoint
+	}
 	host := fmt.Sprintf("%s-grpc.%s.svc.cluster.local", b.Spec.StorageClusterRef.Name, b.Spec.StorageClusterRef.Namespace)
 	if b.Storage.Spec.Service.GRPC.ExternalHost != "" {
 		host = b.Storage.Spec.Service.GRPC.ExternalHost
@@ -56,8 +73,186 @@ func (b *DatabaseBuilder) GetStorageEndpoint() string {
 	return fmt.Sprintf("%s:%d", host, api.GRPCPort)
 }
 
+// GetEndpointWithProto returns the gRPC endpoint clients of the database
+// connect to
+func (b *DatabaseBuilder) GetEndpointWithProto() string {
+	proto := api.GRPCProto
+	if b.Spec.Service.GRPC.TLSConfiguration != nil && b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		proto = api.GRPCSProto
+	}
+	host := fmt.Sprintf(grpcServiceNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+	if b.Spec.Service.GRPC.ExternalHost != "" {
+		host = b.Spec.Service.GRPC.ExternalHost
+	}
+
+	return fmt.Sprintf("%s%s:%d", proto, host, api.GRPCPort)
+}
+
+// GetEndpoints returns the gRPC endpoints (host:port) clients of the
+// database connect to, the proxy one included when it is enabled
+func (b *DatabaseBuilder) GetEndpoints() []string {
+	host := fmt.Sprintf(grpcServiceNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+	if b.Spec.Service.GRPC.ExternalHost != "" {
+		host = b.Spec.Service.GRPC.ExternalHost
+	}
+	endpoints := []string{fmt.Sprintf("%s:%d", host, api.GRPCPort)}
+	if b.Spec.Proxy != nil && b.Spec.Proxy.Enabled {
+		proxyHost := fmt.Sprintf(proxyNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", proxyHost, api.GRPCPort))
+	}
+	return endpoints
+}
+
+// GetQueryEndpoint returns the gRPC endpoint (host:port) queries to the
+// database are sent to and whether it is secured with TLS. Serverless
+// databases are served by the nodes of their shared database, which has to
+// be set in SharedDatabase.
+func (b *DatabaseBuilder) GetQueryEndpoint() (string, bool) {
+	served := b.Database
+	if b.SharedDatabase != nil {
+		served = b.SharedDatabase
+	}
+	host := fmt.Sprintf(grpcServiceNameFormat+".%s.svc.cluster.local", served.Name, served.Namespace)
+	if served.Spec.Service.GRPC.ExternalHost != "" {
+		host = served.Spec.Service.GRPC.ExternalHost
+	}
+	tls := served.Spec.Service.GRPC.TLSConfiguration
+
+	return fmt.Sprintf("%s:%d", host, api.GRPCPort), tls != nil && tls.Enabled
+}
+
+func (b *DatabaseBuilder) GetStatusEndpoint() string {
+	host := fmt.Sprintf(statusServiceNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+
+	return fmt.Sprintf("%s:%d", host, api.StatusPort)
+}
+
+// GetTenantAttributes returns the tenant user attributes of spec.attributes
+// and the ones selected by spec.tenantAttributes. Empty values are skipped,
+// as CMS treats them as removal of the attribute.
+func (b *DatabaseBuilder) GetTenantAttributes() map[string]string {
+	attributes := CopyDict(b.Spec.Attributes)
+	if b.Spec.TenantAttributes == nil {
+		return attributes
+	}
+	for _, key := range b.Spec.TenantAttributes.Labels {
+		if value := b.Labels[key]; value != "" {
+			attributes[key] = value
+		}
+	}
+	for _, key := range b.Spec.TenantAttributes.Annotations {
+		if value := b.Annotations[key]; value != "" {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
+
+// GetTenantQuotas returns the quotas CMS has to enforce on the tenant, nil
+// when the tenant is not limited
+func (b *DatabaseBuilder) GetTenantQuotas() *api.TenantQuotas {
+	if b.Spec.ServerlessResources != nil {
+		return b.Spec.ServerlessResources.Quotas
+	}
+	return nil
+}
+
+// GetNodePublicHost returns the host the pod advertises in discovery, see
+// --grpc-public-host in the StatefulSet container args
+func (b *DatabaseBuilder) GetNodePublicHost(pod *corev1.Pod) string {
+	if b.Spec.Workload == api.WorkloadDeployment {
+		return pod.Status.PodIP
+	}
+	host := b.Spec.Service.GRPC.ExternalHost
+	if host == "" {
+		host = fmt.Sprintf(interconnectServiceNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+	}
+	return fmt.Sprintf("%s.%s", pod.Name, host)
+}
+
+// GetNodeGRPCEndpoint returns the gRPC endpoint of a single database pod
+func (b *DatabaseBuilder) GetNodeGRPCEndpoint(pod *corev1.Pod) string {
+	if b.Spec.Workload == api.WorkloadDeployment {
+		return fmt.Sprintf("%s:%d", pod.Status.PodIP, api.GRPCPort)
+	}
+	return NodeGRPCEndpoint(pod.Name, b.Name, b.Namespace)
+}
+
 func (b *DatabaseBuilder) GetPath() string {
-	return fmt.Sprintf(api.TenantNameFormat, b.Spec.Domain, b.Name)
+	return b.TenantPath()
+}
+
+// GetPodSelector returns the selector of the dynamic node pods, published
+// for the scale subresource
+func (b *DatabaseBuilder) GetPodSelector() string {
+	return metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels.DatabaseLabels(b.Unwrap())})
+}
+
+// GetResourcesKind returns the kind of the compute resources in spec
+func (b *DatabaseBuilder) GetResourcesKind() string {
+	switch {
+	case b.Spec.SharedResources != nil:
+		return api.ResourcesKindShared
+	case b.Spec.ServerlessResources != nil:
+		return api.ResourcesKindServerless
+	default:
+		return api.ResourcesKindDedicated
+	}
+}
+
+// GetStorageUnits returns the storage units of the database with unit kinds
+// mapped to the storage pool kinds available in the referenced Storage.
+func (b *DatabaseBuilder) GetStorageUnits() ([]api.StorageUnit, error) {
+	var units []api.StorageUnit
+	switch {
+	case b.Spec.Resources != nil:
+		units = b.Spec.Resources.StorageUnits
+	case b.Spec.SharedResources != nil:
+		units = b.Spec.SharedResources.StorageUnits
+	default:
+		return nil, nil
+	}
+
+	kinds := make(map[string]string)
+	var available []string
+	if len(b.Storage.Spec.StoragePoolKinds) > 0 {
+		for _, kind := range b.Storage.Spec.StoragePoolKinds {
+			poolKind := kind.PoolKind
+			if poolKind == "" {
+				poolKind = kind.Name
+			}
+			kinds[kind.Name] = poolKind
+			available = append(available, kind.Name)
+		}
+	} else {
+		poolKinds, err := configuration.StoragePoolKinds(b.Storage, b.Spec.Domain)
+		if err != nil {
+			return nil, err
+		}
+		if len(poolKinds) == 0 {
+			// Nothing to validate against, pass the kinds to YDB as is
+			return units, nil
+		}
+		for _, kind := range poolKinds {
+			kinds[kind] = kind
+		}
+		available = poolKinds
+	}
+
+	mapped := make([]api.StorageUnit, 0, len(units))
+	for _, unit := range units {
+		poolKind, ok := kinds[unit.UnitKind]
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w %q, available kinds: %s",
+				ErrUnknownStorageUnitKind,
+				unit.UnitKind,
+				strings.Join(available, ", "),
+			)
+		}
+		mapped = append(mapped, api.StorageUnit{UnitKind: poolKind, Count: unit.Count})
+	}
+	return mapped, nil
 }
 
 func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
@@ -73,6 +268,7 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 	interconnectServiceLabels := databaseLabels.Copy()
 	interconnectServiceLabels.Merge(b.Spec.Service.Interconnect.AdditionalLabels)
 	interconnectServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.InterconnectComponent})
+	interconnectIPFamilies, interconnectIPFamilyPolicy := b.Spec.Service.Interconnect.ServiceIPFamilies()
 
 	statusServiceLabels := databaseLabels.Copy()
 	statusServiceLabels.Merge(b.Spec.Service.Status.AdditionalLabels)
@@ -86,15 +282,16 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 
 	cfg, _ := configuration.Build(b.Storage, b.Unwrap())
 
-	optionalBuilders = append(
-		optionalBuilders,
-		&ConfigMapBuilder{
-			Object: b,
-			Name:   b.GetName(),
-			Data:   cfg,
-			Labels: databaseLabels,
-		},
-	)
+	configMapBuilder := ConfigMapBuilder{
+		Object: b,
+		Name:   b.GetName(),
+		Data:   cfg,
+		Labels: databaseLabels,
+	}
+	optionalBuilders = append(optionalBuilders, &configMapBuilder)
+	if b.ConfigRevisionHistory > 0 {
+		optionalBuilders = append(optionalBuilders, &ConfigRevisionBuilder{ConfigMapBuilder: configMapBuilder})
+	}
 
 	if b.Spec.Monitoring != nil && b.Spec.Monitoring.Enabled {
 		optionalBuilders = append(optionalBuilders,
@@ -111,6 +308,39 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 		)
 	}
 
+	if b.Spec.Monitoring != nil && b.Spec.Monitoring.IsolatedCredentials {
+		optionalBuilders = append(optionalBuilders,
+			&MonitoringSecretBuilder{
+				Object: b,
+				Endpoints: map[string]string{
+					"endpoint":       b.GetEndpointWithProto(),
+					"database":       b.GetPath(),
+					"statusEndpoint": b.GetStatusEndpoint(),
+				},
+				Labels:   databaseLabels,
+				Rotation: credentialsRotation(b, b.Spec.Monitoring.CredentialsRotationInterval),
+			},
+		)
+	}
+
+	if DatabaseTLSManaged(b.Database) {
+		nodes := b.Spec.Nodes
+		if b.Spec.Workload == api.WorkloadDeployment {
+			// Deployment pods have no DNS names of their own
+			nodes = 0
+		}
+		services, pods := tlsDNSNames(b.Name, b.Namespace, nodes, b.Spec.Service.GRPC.ExternalHost)
+		optionalBuilders = append(optionalBuilders,
+			&CertificateSecretBuilder{
+				Object:   b,
+				DNSNames: append(services, pods...),
+				Labels:   databaseLabels,
+				Issuer:   b.CertificateIssuer,
+				Rotation: credentialsRotation(b, nil),
+			},
+		)
+	}
+
 	if b.Spec.Encryption != nil && b.Spec.Encryption.Enabled && b.Spec.Encryption.Key == nil {
 		var pin string
 		if b.Spec.Encryption.Pin == nil || len(*b.Spec.Encryption.Pin) == 0 {
@@ -134,7 +364,7 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 			Object:         b,
 			NameFormat:     grpcServiceNameFormat,
 			Labels:         grpcServiceLabels,
-			SelectorLabels: databaseLabels,
+			SelectorLabels: nodeSelector(databaseLabels, b.Spec.Service.GRPC.Service, b.Spec.AdditionalPodLabels),
 			Annotations:    b.Spec.Service.GRPC.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.GRPCServicePortName,
@@ -154,14 +384,14 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 				Name: api.InterconnectServicePortName,
 				Port: api.InterconnectPort,
 			}},
-			IPFamilies:     b.Spec.Service.Interconnect.IPFamilies,
-			IPFamilyPolicy: b.Spec.Service.Interconnect.IPFamilyPolicy,
+			IPFamilies:     interconnectIPFamilies,
+			IPFamilyPolicy: interconnectIPFamilyPolicy,
 		},
 		&ServiceBuilder{
 			Object:         b,
 			NameFormat:     statusServiceNameFormat,
 			Labels:         statusServiceLabels,
-			SelectorLabels: databaseLabels,
+			SelectorLabels: nodeSelector(databaseLabels, b.Spec.Service.Status.Service, b.Spec.AdditionalPodLabels),
 			Annotations:    b.Spec.Service.Status.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.StatusServicePortName,
@@ -179,7 +409,7 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 				Object:         b,
 				NameFormat:     datastreamsServiceNameFormat,
 				Labels:         datastreamsServiceLabels,
-				SelectorLabels: databaseLabels,
+				SelectorLabels: nodeSelector(databaseLabels, b.Spec.Service.Datastreams.Service, b.Spec.AdditionalPodLabels),
 				Annotations:    b.Spec.Service.Datastreams.AdditionalAnnotations,
 				Ports: []corev1.ServicePort{{
 					Name: api.DatastreamsServicePortName,
@@ -191,10 +421,55 @@ func (b *DatabaseBuilder) GetResourceBuilders() []ResourceBuilder {
 		)
 	}
 
+	if b.Spec.Proxy != nil && b.Spec.Proxy.Enabled {
+		proxyLabels := databaseLabels.Copy()
+		proxyLabels.Merge(map[string]string{labels.ComponentKey: labels.ProxyComponent})
+
+		proxyServiceLabels := proxyLabels.Copy()
+		proxyServiceLabels.Merge(b.Spec.Proxy.Service.AdditionalLabels)
+
+		optionalBuilders = append(
+			optionalBuilders,
+			&ProxyDeploymentBuilder{Database: b.Unwrap(), Labels: proxyLabels},
+			&ServiceBuilder{
+				Object:         b,
+				NameFormat:     proxyNameFormat,
+				Labels:         proxyServiceLabels,
+				SelectorLabels: proxyLabels,
+				Annotations:    b.Spec.Proxy.Service.AdditionalAnnotations,
+				Ports: []corev1.ServicePort{{
+					Name: api.GRPCServicePortName,
+					Port: api.GRPCPort,
+				}},
+				IPFamilies:     b.Spec.Proxy.Service.IPFamilies,
+				IPFamilyPolicy: b.Spec.Proxy.Service.IPFamilyPolicy,
+			},
+		)
+
+		if b.Spec.Proxy.Autoscaling != nil {
+			optionalBuilders = append(
+				optionalBuilders,
+				&ProxyAutoscalerBuilder{Object: b, Autoscaling: b.Spec.Proxy.Autoscaling, Labels: proxyLabels},
+			)
+		}
+	}
+
 	optionalBuilders = append(
 		optionalBuilders,
-		&DatabaseStatefulSetBuilder{Database: b.Unwrap(), Labels: databaseLabels, Storage: b.Storage},
+		uiAuthBuilders(b, b.Spec.UIAuth, databaseLabels, b.Spec.AdditionalAnnotations, b.Spec.NodeSelector, b.Spec.Tolerations)...,
 	)
 
+	statefulSetBuilder := DatabaseStatefulSetBuilder{
+		Database:      b.Unwrap(),
+		Labels:        databaseLabels,
+		Storage:       b.Storage,
+		Configuration: cfg,
+	}
+	if b.Spec.Workload == api.WorkloadDeployment {
+		optionalBuilders = append(optionalBuilders, &DatabaseDeploymentBuilder{DatabaseStatefulSetBuilder: statefulSetBuilder})
+	} else {
+		optionalBuilders = append(optionalBuilders, &statefulSetBuilder)
+	}
+
 	return optionalBuilders
 }
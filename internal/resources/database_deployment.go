@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+// DatabaseDeploymentBuilder runs the dynamic nodes in a Deployment, see
+// spec.workload. The pods are the same as in the StatefulSet, except that
+// nodes advertise their pod IP instead of the per-pod DNS name.
+type DatabaseDeploymentBuilder struct {
+	DatabaseStatefulSetBuilder
+}
+
+func (b *DatabaseDeploymentBuilder) Build(obj client.Object) error {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New("failed to cast to Deployment object")
+	}
+
+	if deployment.ObjectMeta.Name == "" {
+		deployment.ObjectMeta.Name = b.Name
+	}
+	deployment.ObjectMeta.Namespace = b.Namespace
+	deployment.ObjectMeta.Annotations = CopyDict(b.Spec.AdditionalAnnotations)
+
+	deployment.Spec.Replicas = ptr.Int32(b.Replicas())
+	deployment.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: b.Labels,
+	}
+	deployment.Spec.RevisionHistoryLimit = ptr.Int32(10)
+	deployment.Spec.Template = rolloutPodTemplate(deployment.Spec.Template, b.buildPodTemplateSpec())
+
+	return nil
+}
+
+func (b *DatabaseDeploymentBuilder) Placeholder(cr client.Object) client.Object {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.GetName(),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
 )
 
@@ -21,6 +23,9 @@ type DatabaseStatefulSetBuilder struct {
 
 	Labels  map[string]string
 	Storage *v1alpha1.Storage
+
+	// Data of the rendered configuration ConfigMap
+	Configuration map[string]string
 }
 
 func (b *DatabaseStatefulSetBuilder) Build(obj client.Object) error {
@@ -35,15 +40,30 @@ func (b *DatabaseStatefulSetBuilder) Build(obj client.Object) error {
 	sts.ObjectMeta.Namespace = b.Namespace
 	sts.ObjectMeta.Annotations = CopyDict(b.Spec.AdditionalAnnotations)
 
+	template := rolloutPodTemplate(sts.Spec.Template, b.buildPodTemplateSpec())
 	sts.Spec = appsv1.StatefulSetSpec{
-		Replicas: ptr.Int32(b.Spec.Nodes),
+		Replicas: ptr.Int32(b.Replicas()),
 		Selector: &metav1.LabelSelector{
 			MatchLabels: b.Labels,
 		},
-		PodManagementPolicy:  appsv1.ParallelPodManagement,
+		PodManagementPolicy:  podManagementPolicy(b.Spec.PodManagementPolicy),
 		RevisionHistoryLimit: ptr.Int32(10),
 		ServiceName:          fmt.Sprintf(interconnectServiceNameFormat, b.Name),
-		Template:             b.buildPodTemplateSpec(),
+		Template:             template,
+	}
+	if b.Spec.CoordinatedRollout {
+		// The pods are deleted by the operator once CMS allows it
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.OnDeleteStatefulSetStrategyType,
+		}
+	} else if upgrade := b.Status.Upgrade; upgrade != nil {
+		// The operator lowers the partition one batch at a time
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: ptr.Int32(upgrade.Partition),
+			},
+		}
 	}
 
 	return nil
@@ -62,28 +82,166 @@ func (b *DatabaseStatefulSetBuilder) buildEnv() []corev1.EnvVar {
 		},
 	})
 
+	if b.Spec.Workload == v1alpha1.WorkloadDeployment {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: "POD_IP", // for `--node-host` flag, Deployment pods have no DNS names
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "status.podIP",
+				},
+			},
+		})
+	}
+
 	return envVars
 }
 
+// buildAffinity merges the rules generated from spec.storageLocality into
+// the user provided affinity
+func (b *DatabaseStatefulSetBuilder) buildAffinity() *corev1.Affinity {
+	locality := b.Spec.StorageLocality
+	if locality == nil || (locality.SameZone == "" && !locality.AvoidStorageNodes && locality.AvoidOtherDatabases == "") {
+		return b.Spec.Affinity
+	}
+
+	affinity := &corev1.Affinity{}
+	if b.Spec.Affinity != nil {
+		affinity = b.Spec.Affinity.DeepCopy()
+	}
+
+	// An external storage cluster has no pods to follow, the webhook
+	// rejects sameZone and avoidStorageNodes with it
+	if b.Spec.StorageClusterRef != nil {
+		addStorageAffinity(affinity, locality, b.Spec.StorageClusterRef)
+	}
+
+	// Pods of the other databases carry the same storage label, the
+	// instance label tells them apart
+	otherDatabasePods := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				labels.DatabaseStorageKey: labels.DatabaseStorageValue(b.Database),
+				labels.ComponentKey:       labels.DynamicComponent,
+			},
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      labels.InstanceKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{b.Name},
+			}},
+		},
+		NamespaceSelector: &metav1.LabelSelector{},
+		TopologyKey:       corev1.LabelHostname,
+	}
+	switch locality.AvoidOtherDatabases {
+	case v1alpha1.ZoneAffinityPreferred:
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight:          100,
+				PodAffinityTerm: otherDatabasePods,
+			},
+		)
+	case v1alpha1.ZoneAffinityRequired:
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			otherDatabasePods,
+		)
+	}
+
+	return affinity
+}
+
+// addStorageAffinity adds the rules following the pods of the Storage
+func addStorageAffinity(affinity *corev1.Affinity, locality *v1alpha1.StorageLocality, storage *v1alpha1.StorageRef) {
+	storagePods := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			labels.InstanceKey:  storage.Name,
+			labels.ComponentKey: labels.StorageComponent,
+		},
+	}
+	storageNamespaces := []string{storage.Namespace}
+
+	switch locality.SameZone {
+	case v1alpha1.ZoneAffinityPreferred:
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: storagePods,
+					Namespaces:    storageNamespaces,
+					TopologyKey:   corev1.LabelTopologyZone,
+				},
+			},
+		)
+	case v1alpha1.ZoneAffinityRequired:
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			corev1.PodAffinityTerm{
+				LabelSelector: storagePods,
+				Namespaces:    storageNamespaces,
+				TopologyKey:   corev1.LabelTopologyZone,
+			},
+		)
+	}
+
+	if locality.AvoidStorageNodes {
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			corev1.PodAffinityTerm{
+				LabelSelector: storagePods,
+				Namespaces:    storageNamespaces,
+				TopologyKey:   corev1.LabelHostname,
+			},
+		)
+	}
+}
+
+// buildPodLabels returns the selector labels with the storage label and the
+// additional pod labels. They stay out of the selector, which existing
+// StatefulSets cannot change.
+func (b *DatabaseStatefulSetBuilder) buildPodLabels() map[string]string {
+	podLabels := withAdditionalPodLabels(b.Labels, b.Spec.AdditionalPodLabels)
+	podLabels[labels.DatabaseStorageKey] = labels.DatabaseStorageValue(b.Database)
+	return podLabels
+}
+
 func (b *DatabaseStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpec {
-	dnsConfigSearches := []string{
-		fmt.Sprintf(
+	var dnsConfigSearches []string
+	if b.Spec.StorageClusterRef != nil {
+		dnsConfigSearches = append(dnsConfigSearches, fmt.Sprintf(
 			"%s-interconnect.%s.svc.cluster.local",
 			b.Spec.StorageClusterRef.Name,
 			b.Namespace,
-		),
+		))
 	}
 
 	podTemplate := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:      b.Labels,
-			Annotations: CopyDict(b.Spec.AdditionalAnnotations),
+			Labels:      b.buildPodLabels(),
+			Annotations: b.buildPodAnnotations(),
 		},
 		Spec: corev1.PodSpec{
 			Containers:     []corev1.Container{b.buildContainer()},
 			InitContainers: b.Spec.InitContainers,
 			NodeSelector:   b.Spec.NodeSelector,
-			Affinity:       b.Spec.Affinity,
+			Affinity:       b.buildAffinity(),
 			Tolerations:    b.Spec.Tolerations,
 
 			Volumes: b.buildVolumes(),
@@ -96,9 +254,40 @@ func (b *DatabaseStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSp
 	if b.Spec.Image.PullSecret != nil {
 		podTemplate.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: *b.Spec.Image.PullSecret}}
 	}
+	if b.Spec.ReadinessGate {
+		podTemplate.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: v1alpha1.NodeReadyGateConditionType}}
+	}
+	if b.Spec.DedicatedNodes != nil {
+		dedicatedValue := labels.DedicatedDatabaseValue(b.Database)
+		podTemplate.Spec.NodeSelector = labels.Labels(CopyDict(b.Spec.NodeSelector)).Merge(map[string]string{
+			labels.DedicatedDatabaseKey: dedicatedValue,
+		})
+		podTemplate.Spec.Tolerations = append(
+			append([]corev1.Toleration{}, b.Spec.Tolerations...),
+			corev1.Toleration{
+				Key:      labels.DedicatedDatabaseKey,
+				Operator: corev1.TolerationOpEqual,
+				Value:    dedicatedValue,
+				Effect:   b.Spec.DedicatedNodes.TaintEffect,
+			},
+		)
+	}
 	return podTemplate
 }
 
+func (b *DatabaseStatefulSetBuilder) buildPodAnnotations() map[string]string {
+	annotations := CopyDict(b.Spec.AdditionalAnnotations)
+	if DatabaseTLSManaged(b.Database) {
+		services, _ := tlsDNSNames(b.Name, b.Namespace, 0, b.Spec.Service.GRPC.ExternalHost)
+		annotations = tlsTopologyAnnotations(annotations, services)
+		annotations = credentialsRotationAnnotations(annotations, b.Database)
+	}
+	if b.Configuration != nil {
+		annotations[configurationChecksumAnnotation] = configurationChecksum(b.Configuration)
+	}
+	return annotations
+}
+
 func (b *DatabaseStatefulSetBuilder) buildVolumes() []corev1.Volume {
 	configMapName := b.Name
 
@@ -114,28 +303,73 @@ func (b *DatabaseStatefulSetBuilder) buildVolumes() []corev1.Volume {
 	}
 
 	if b.Spec.Service.GRPC.TLSConfiguration != nil && b.Spec.Service.GRPC.TLSConfiguration.Enabled {
-		volumes = append(volumes, buildTLSVolume(grpcTLSVolumeName, b.Spec.Service.GRPC.TLSConfiguration))
+		volumes = append(volumes, buildTLSVolume(grpcTLSVolumeName, b.Name, b.Spec.Service.GRPC.TLSConfiguration))
 	}
 
 	if b.Spec.Service.Interconnect.TLSConfiguration != nil && b.Spec.Service.Interconnect.TLSConfiguration.Enabled {
-		volumes = append(volumes, buildTLSVolume(interconnectTLSVolumeName, b.Spec.Service.Interconnect.TLSConfiguration))
+		volumes = append(volumes, buildTLSVolume(interconnectTLSVolumeName, b.Name, b.Spec.Service.Interconnect.TLSConfiguration))
+	}
+
+	if ca := b.storageCA(); ca != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: storageCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ca.Name,
+					Items:      []corev1.KeyToPath{{Key: ca.Key, Path: "ca.crt"}},
+				},
+			},
+		})
 	}
 
 	if b.Spec.Encryption != nil && b.Spec.Encryption.Enabled {
 		volumes = append(volumes, b.buildEncryptionVolume())
 	}
 
+	if b.Spec.Tiering != nil {
+		for _, tier := range b.Spec.Tiering.Tiers {
+			volumes = append(volumes, buildTierCredentialsVolume(tier))
+		}
+	}
+
 	if b.Spec.Datastreams != nil && b.Spec.Datastreams.Enabled {
 		volumes = append(volumes, b.buildDatastreamsIAMServiceAccountKeyVolume())
 		if b.Spec.Service.Datastreams.TLSConfiguration != nil && b.Spec.Service.Datastreams.TLSConfiguration.Enabled {
-			volumes = append(volumes, buildTLSVolume(datastreamsTLSVolumeName, b.Spec.Service.Datastreams.TLSConfiguration))
+			volumes = append(volumes, buildTLSVolume(datastreamsTLSVolumeName, b.Name, b.Spec.Service.Datastreams.TLSConfiguration))
 		}
 	}
 
 	return volumes
 }
 
-func buildTLSVolume(name string, configuration *v1alpha1.TLSConfiguration) corev1.Volume { // fixme move somewhere?
+// storageCA returns the selector of the CA the nodes verify the node
+// broker of an external storage cluster with
+func (b *DatabaseStatefulSetBuilder) storageCA() *corev1.SecretKeySelector {
+	if b.Spec.ExternalStorageEndpoint == nil {
+		return nil
+	}
+	return b.Spec.ExternalStorageEndpoint.CA()
+}
+
+// buildTLSVolume mounts the certificate of a TLS service, operator-managed
+// certificates are taken from the secret of the owner
+func buildTLSVolume(name, owner string, configuration *v1alpha1.TLSConfiguration) corev1.Volume { // fixme move somewhere?
+	if configuration.OperatorManaged {
+		return corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: TLSSecretName(owner),
+					Items: []corev1.KeyToPath{
+						{Key: tlsCAKey, Path: "ca.crt"},
+						{Key: tlsCertificateKey, Path: "tls.crt"},
+						{Key: tlsPrivateKey, Path: "tls.key"},
+					},
+				},
+			},
+		}
+	}
+
 	volume := corev1.Volume{
 		Name: name,
 		VolumeSource: corev1.VolumeSource{
@@ -205,6 +439,42 @@ func (b *DatabaseStatefulSetBuilder) buildDatastreamsIAMServiceAccountKeyVolume(
 	}
 }
 
+// buildTierCredentialsVolume projects the keys of a storage tier, which may
+// come from different secrets, into a single directory
+func buildTierCredentialsVolume(tier v1alpha1.StorageTier) corev1.Volume {
+	return corev1.Volume{
+		Name: fmt.Sprintf(tierVolumeNameFormat, tier.Name),
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: tier.AccessKey.LocalObjectReference,
+							Items: []corev1.KeyToPath{
+								{
+									Key:  tier.AccessKey.Key,
+									Path: configuration.DatabaseTierAccessKeyFile,
+								},
+							},
+						},
+					},
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: tier.SecretKey.LocalObjectReference,
+							Items: []corev1.KeyToPath{
+								{
+									Key:  tier.SecretKey.Key,
+									Path: configuration.DatabaseTierSecretKeyFile,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (b *DatabaseStatefulSetBuilder) buildContainer() corev1.Container {
 	command, args := b.buildContainerArgs()
 	container := corev1.Container{
@@ -273,6 +543,14 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.storageCA() != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      storageCAVolumeName,
+			ReadOnly:  true,
+			MountPath: storageCADir,
+		})
+	}
+
 	if b.Spec.Encryption != nil && b.Spec.Encryption.Enabled {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      encryptionVolumeName,
@@ -281,6 +559,16 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.Spec.Tiering != nil {
+		for _, tier := range b.Spec.Tiering.Tiers {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      fmt.Sprintf(tierVolumeNameFormat, tier.Name),
+				ReadOnly:  true,
+				MountPath: path.Join(configuration.DatabaseTiersPath, tier.Name),
+			})
+		}
+	}
+
 	if b.Spec.Datastreams != nil && b.Spec.Datastreams.Enabled {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      datastreamsIAMServiceAccountKeyVolumeName,
@@ -305,7 +593,7 @@ func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 	db := NewDatabase(b.DeepCopy())
 	db.Storage = b.Storage
 
-	tenantName := fmt.Sprintf(v1alpha1.TenantNameFormat, b.Spec.Domain, b.Name)
+	tenantName := b.TenantPath()
 
 	args := []string{
 		"server",
@@ -325,6 +613,30 @@ func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		"--node-broker",
 		db.GetStorageEndpointWithProto(),
 	}
+	if b.storageCA() != nil {
+		args = append(args, "--ca", path.Join(storageCADir, "ca.crt"))
+	}
+
+	if b.Spec.Workload == v1alpha1.WorkloadDeployment {
+		// Nodes register in the node broker by address and get a new node
+		// ID on every start, so pods do not need stable ordinals
+		args = append(
+			args,
+
+			"--node-host",
+			"$(POD_IP)",
+
+			"--node-address",
+			"$(POD_IP)",
+
+			"--grpc-public-host",
+			"$(POD_IP)",
+
+			"--grpc-public-port",
+			strconv.Itoa(v1alpha1.GRPCPort),
+		)
+		return command, append(args, b.Spec.AdditionalArgs...)
+	}
 
 	if b.Spec.Service.GRPC.ExternalHost == "" {
 		service := fmt.Sprintf(interconnectServiceNameFormat, b.GetName())
@@ -344,7 +656,7 @@ func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		strconv.Itoa(v1alpha1.GRPCPort),
 	)
 
-	return command, args
+	return command, append(args, b.Spec.AdditionalArgs...)
 }
 
 func (b *DatabaseStatefulSetBuilder) Placeholder(cr client.Object) client.Object {
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// ChildDeletions remembers when a child object of a resource was last
+// deleted. Resources sync is skipped while the spec is unchanged, a
+// deletion makes the next reconcile recreate the child right away instead
+// of after the resync period.
+type ChildDeletions struct {
+	mu      sync.Mutex
+	deleted map[types.NamespacedName]time.Time
+}
+
+func NewChildDeletions() *ChildDeletions {
+	return &ChildDeletions{deleted: map[types.NamespacedName]time.Time{}}
+}
+
+// Predicate records the deletions of the objects controlled by a resource
+// of ownerKind. It passes all events, the owner is enqueued by Owns.
+func (d *ChildDeletions) Predicate(ownerKind string) predicate.Predicate {
+	return predicate.Funcs{
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			owner := metav1.GetControllerOf(e.Object)
+			if owner == nil || owner.Kind != ownerKind || owner.APIVersion != api.GroupVersion.String() {
+				return true
+			}
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.deleted[types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: owner.Name}] = time.Now()
+			return true
+		},
+	}
+}
+
+// SyncRequired reports whether a child of the resource was deleted after
+// the last resources sync
+func (d *ChildDeletions) SyncRequired(owner types.NamespacedName, status *api.ResourcesSyncStatus) bool {
+	if d == nil || status == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deleted, ok := d.deleted[owner]
+	return ok && !deleted.Before(status.LastSyncTime.Time)
+}
+
+// Forget drops the record of a deleted resource
+func (d *ChildDeletions) Forget(owner types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.deleted, owner)
+}
@@ -0,0 +1,96 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	maxDiffValueLength = 80
+	maxEventDiffLength = 1024
+	redactedValue      = "<redacted>"
+)
+
+// Diff lists the fields changed by a patch, one "path: new value" entry per
+// leaf, e.g. "spec.template.spec.containers[name=ydb-dynamic].image: ...".
+// Removed fields are reported as null. Values of Secrets are redacted and
+// long values are truncated.
+func Diff(obj runtime.Object, patch []byte) ([]string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, err
+	}
+
+	_, redact := obj.(*corev1.Secret)
+	var diff []string
+	flattenDiff("", fields, redact, &diff)
+	sort.Strings(diff)
+	return diff, nil
+}
+
+func flattenDiff(path string, value interface{}, redact bool, diff *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			// Strategic merge patch directives
+			if strings.HasPrefix(key, "$") {
+				continue
+			}
+			flattenDiff(joinDiffPath(path, key), field, redact, diff)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flattenDiff(fmt.Sprintf("%s[%s]", path, listItemKey(item, i)), item, redact, diff)
+		}
+	default:
+		*diff = append(*diff, fmt.Sprintf("%s: %s", path, formatDiffValue(v, redact)))
+	}
+}
+
+// listItemKey names list items by their merge key when they have one
+func listItemKey(item interface{}, index int) string {
+	if fields, ok := item.(map[string]interface{}); ok {
+		for _, key := range []string{"name", "mountPath", "containerPort", "key"} {
+			if value, ok := fields[key]; ok {
+				return fmt.Sprintf("%s=%v", key, value)
+			}
+		}
+	}
+	return fmt.Sprint(index)
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func formatDiffValue(value interface{}, redact bool) string {
+	if value == nil {
+		return "null"
+	}
+	if redact {
+		return redactedValue
+	}
+	text := fmt.Sprint(value)
+	if len(text) > maxDiffValueLength {
+		return text[:maxDiffValueLength] + "..."
+	}
+	return text
+}
+
+// FormatDiff joins the diff for an event message, events are kept short so
+// long diffs are cut
+func FormatDiff(diff []string) string {
+	text := strings.Join(diff, ", ")
+	if len(text) > maxEventDiffLength {
+		return text[:maxEventDiffLength] + "..."
+	}
+	return text
+}
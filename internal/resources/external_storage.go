@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// ExternalStorage stands in for the Storage of a database running on an
+// external storage cluster. It carries what the database steps read from a
+// Storage: the domain, the configuration and the TLS of the gRPC endpoint.
+// It has no nodes, so the hosts come from the configuration.
+func ExternalStorage(database *api.Database) *api.Storage {
+	external := database.Spec.ExternalStorageEndpoint
+	host, _, err := net.SplitHostPort(external.Endpoint)
+	if err != nil {
+		host = external.Endpoint
+	}
+
+	storage := &api.Storage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      host,
+			Namespace: database.Namespace,
+		},
+	}
+	storage.Spec.Domain = external.Domain
+	storage.Spec.Configuration = external.Configuration
+	storage.Spec.Service.GRPC.TLSConfiguration = &api.TLSConfiguration{Enabled: external.Secure()}
+	storage.Spec.Service.Interconnect.TLSConfiguration = &api.TLSConfiguration{}
+	return storage
+}
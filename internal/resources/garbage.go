@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+)
+
+// Garbage returns the child objects of cr that are no longer needed: the
+// ConfigMaps and the objects of the listed kinds the builders do not
+// produce anymore, e.g. the CA bundle once spec.caBundle is removed or the
+// Deployment of a disabled feature, the revisions of the configuration
+// ConfigMap beyond the newest configRevisions superseded ones, and the Jobs
+// that finished more than jobTTL ago. A jobTTL of zero keeps finished Jobs.
+func Garbage(
+	ctx context.Context,
+	c client.Reader,
+	cr client.Object,
+	builders []ResourceBuilder,
+	kinds []client.ObjectList,
+	configRevisions int,
+	jobTTL time.Duration,
+	now time.Time,
+) ([]client.Object, error) {
+	built := map[string]bool{}
+	for _, builder := range builders {
+		built[garbageKey(builder.Placeholder(cr))] = true
+	}
+
+	var garbage, revisions []client.Object
+	for _, list := range append([]client.ObjectList{&corev1.ConfigMapList{}}, kinds...) {
+		if err := c.List(ctx, list, client.InNamespace(cr.GetNamespace())); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !ownedBy(obj, cr) || built[garbageKey(obj)] {
+				continue
+			}
+			if _, ok := obj.(*corev1.ConfigMap); ok && obj.GetLabels()[labels.ConfigRevisionKey] != "" {
+				revisions = append(revisions, obj)
+				continue
+			}
+			garbage = append(garbage, obj)
+		}
+	}
+	if len(revisions) > configRevisions {
+		// Newest first
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].GetCreationTimestamp().After(revisions[j].GetCreationTimestamp().Time)
+		})
+		garbage = append(garbage, revisions[configRevisions:]...)
+	}
+
+	if jobTTL <= 0 {
+		return garbage, nil
+	}
+	jobs := &batchv1.JobList{}
+	if err := c.List(ctx, jobs, client.InNamespace(cr.GetNamespace())); err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !ownedBy(job, cr) {
+			continue
+		}
+		if finished, ok := jobFinishTime(job); ok && now.Sub(finished) > jobTTL {
+			garbage = append(garbage, job)
+		}
+	}
+	return garbage, nil
+}
+
+func jobFinishTime(job *batchv1.Job) (time.Time, bool) {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func garbageKey(obj client.Object) string {
+	return fmt.Sprintf("%T/%s", obj, obj.GetName())
+}
+
+func ownedBy(obj, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
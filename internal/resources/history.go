@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// MaxHistoryEntries bounds status.history, older entries are dropped
+const MaxHistoryEntries = 20
+
+const (
+	HistoryOutcomeSucceeded = "Succeeded"
+	HistoryOutcomeFailed    = "Failed"
+
+	HistoryActionResourcesUpdated    = "ResourcesUpdated"
+	HistoryActionScaled              = "Scaled"
+	HistoryActionNodesDrained        = "NodesDrained"
+	HistoryActionStorageInitialized  = "StorageInitialized"
+	HistoryActionTenantCreated       = "TenantCreated"
+	HistoryActionTenantAdopted       = "TenantAdopted"
+	HistoryActionStorageUnitsAdded   = "StorageUnitsAdded"
+	HistoryActionResourcesMigrated   = "ResourcesMigrated"
+	HistoryActionAutoUpdated         = "AutoUpdated"
+	HistoryActionDisasterRecovery    = "DisasterRecovery"
+	HistoryActionNodesDecommissioned = "NodesDecommissioned"
+	HistoryActionUpgraded            = "Upgraded"
+)
+
+// AppendHistory appends an entry for the action initiated by the given
+// spec generation, keeping at most MaxHistoryEntries latest entries.
+func AppendHistory(
+	history []api.HistoryEntry,
+	action string,
+	outcome string,
+	generation int64,
+	message string,
+	now time.Time,
+) []api.HistoryEntry {
+	history = append(history, api.HistoryEntry{
+		Action:     action,
+		Time:       metav1.NewTime(now),
+		Outcome:    outcome,
+		Generation: generation,
+		Message:    message,
+	})
+	if len(history) > MaxHistoryEntries {
+		history = history[len(history)-MaxHistoryEntries:]
+	}
+	return history
+}
+
+// StatefulSetReplicas returns the replicas of obj if it is a StatefulSet
+// that already exists in the cluster
+func StatefulSetReplicas(obj client.Object) (int32, bool) {
+	sts, ok := obj.(*appsv1.StatefulSet)
+	if !ok || sts.CreationTimestamp.IsZero() || sts.Spec.Replicas == nil {
+		return 0, false
+	}
+	return *sts.Spec.Replicas, true
+}
+
+// DeploymentReplicas returns the replicas of obj if it is a Deployment
+// that already exists in the cluster
+func DeploymentReplicas(obj client.Object) (int32, bool) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok || deployment.CreationTimestamp.IsZero() || deployment.Spec.Replicas == nil {
+		return 0, false
+	}
+	return *deployment.Spec.Replicas, true
+}
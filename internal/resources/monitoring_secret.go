@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	monitoringUsernameKey = "username"
+	monitoringPasswordKey = "password"
+)
+
+// YDB user names are identifiers, the object names are DNS subdomains
+var monitoringUsernameReplacer = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// MonitoringSecretBuilder keeps the metrics scrape credentials of a single
+// Storage or Database together with its endpoints. The credentials are
+// generated once and regenerated as the Rotation says. The controllers
+// register them as a YDB user, see MonitoringCredentials.
+type MonitoringSecretBuilder struct {
+	client.Object
+
+	// Endpoints are stored as is and refreshed on every sync
+	Endpoints map[string]string
+	Labels    map[string]string
+	Rotation  CredentialsRotation
+}
+
+func (b *MonitoringSecretBuilder) Build(obj client.Object) error {
+	sec, ok := obj.(*corev1.Secret)
+	if !ok {
+		return errors.New("failed to cast to Secret object")
+	}
+
+	if sec.ObjectMeta.Name == "" {
+		sec.ObjectMeta.Name = fmt.Sprintf(monitoringSecretNameFormat, b.GetName())
+	}
+	sec.ObjectMeta.Namespace = b.GetNamespace()
+
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+	now := time.Now()
+	username := MonitoringUsername(b.GetName())
+	if string(sec.Data[monitoringUsernameKey]) != username || len(sec.Data[monitoringPasswordKey]) == 0 || b.Rotation.due(sec, now) {
+		password, err := generatePassword()
+		if err != nil {
+			return err
+		}
+		sec.Data[monitoringUsernameKey] = []byte(username)
+		sec.Data[monitoringPasswordKey] = []byte(password)
+		b.Rotation.record(sec, now)
+	}
+	for key, value := range b.Endpoints {
+		sec.Data[key] = []byte(value)
+	}
+	sec.Labels = b.Labels
+	sec.Type = corev1.SecretTypeOpaque
+
+	return nil
+}
+
+func (b *MonitoringSecretBuilder) Placeholder(cr client.Object) client.Object {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(monitoringSecretNameFormat, cr.GetName()),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+// MonitoringSecretName returns the name of the Secret built by
+// MonitoringSecretBuilder for the Storage or Database
+func MonitoringSecretName(name string) string {
+	return fmt.Sprintf(monitoringSecretNameFormat, name)
+}
+
+// MonitoringUsername returns the name of the YDB user the metrics of the
+// Storage or Database are scraped with
+func MonitoringUsername(name string) string {
+	return "monitoring_" + monitoringUsernameReplacer.ReplaceAllString(name, "_")
+}
+
+// MonitoringCredentials returns the username and the password kept in the
+// Secret built by MonitoringSecretBuilder
+func MonitoringCredentials(sec *corev1.Secret) (string, string, error) {
+	username, password := sec.Data[monitoringUsernameKey], sec.Data[monitoringPasswordKey]
+	if len(username) == 0 || len(password) == 0 {
+		return "", "", fmt.Errorf("no credentials in secret %s", sec.Name)
+	}
+	return string(username), string(password), nil
+}
+
+// monitoringSecretKeys returns the credentials of the Secret built by
+// MonitoringSecretBuilder for the ServiceMonitor endpoints
+func monitoringSecretKeys(name string) (corev1.SecretKeySelector, corev1.SecretKeySelector) {
+	ref := corev1.LocalObjectReference{Name: fmt.Sprintf(monitoringSecretNameFormat, name)}
+	return corev1.SecretKeySelector{LocalObjectReference: ref, Key: monitoringUsernameKey},
+		corev1.SecretKeySelector{LocalObjectReference: ref, Key: monitoringPasswordKey}
+}
+
+func generatePassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
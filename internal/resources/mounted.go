@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// MountedObjects returns the ConfigMaps and Secrets the pod spec uses with
+// the hashes of their current data, sorted by kind and name. Drift
+// detection tools compare them to what they expect to be mounted.
+func MountedObjects(ctx context.Context, reader client.Reader, namespace string, spec corev1.PodSpec) ([]api.MountedObject, error) {
+	configMaps, secrets := podSpecObjects(spec)
+
+	var objects []api.MountedObject
+	for _, name := range configMaps {
+		object := api.MountedObject{Kind: api.MountedObjectConfigMap, Name: name}
+		configMap := &corev1.ConfigMap{}
+		err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap)
+		switch {
+		case err == nil:
+			data := map[string][]byte{}
+			for key, value := range configMap.Data {
+				data[key] = []byte(value)
+			}
+			for key, value := range configMap.BinaryData {
+				data[key] = value
+			}
+			object.Hash = dataHash(data)
+		case !apierrors.IsNotFound(err):
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	for _, name := range secrets {
+		object := api.MountedObject{Kind: api.MountedObjectSecret, Name: name}
+		secret := &corev1.Secret{}
+		err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret)
+		switch {
+		case err == nil:
+			object.Hash = dataHash(secret.Data)
+		case !apierrors.IsNotFound(err):
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// podSpecObjects returns the sorted names of the ConfigMaps and Secrets
+// referenced by the volumes and the environment of the containers
+func podSpecObjects(spec corev1.PodSpec) ([]string, []string) {
+	configMaps, secrets := map[string]bool{}, map[string]bool{}
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secrets[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, source := range container.EnvFrom {
+			if source.ConfigMapRef != nil {
+				configMaps[source.ConfigMapRef.Name] = true
+			}
+			if source.SecretRef != nil {
+				secrets[source.SecretRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+	return sortedKeys(configMaps), sortedKeys(secrets)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dataHash hashes the data as documented on MountedObject.Hash
+func dataHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hasher := sha256.New()
+	for _, key := range keys {
+		hasher.Write([]byte(key))
+		hasher.Write([]byte{0})
+		hasher.Write(data[key])
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
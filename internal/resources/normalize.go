@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// changedOnServer reports whether applying updated to current would change
+// the object once the API server defaulted and normalized it. The patch of
+// the desired object is not enough: fields the server defaults or rewrites,
+// e.g. "1000m" CPU stored as "1", would make every reconcile update the
+// object. An update in dry-run mode returns the object as it would be stored.
+func changedOnServer(ctx context.Context, c client.Client, current, updated client.Object) (bool, error) {
+	dryRun := updated.DeepCopyObject().(client.Object)
+	if err := c.Update(ctx, dryRun, client.DryRunAll); err != nil {
+		return false, err
+	}
+
+	currentFields, err := comparableFields(current)
+	if err != nil {
+		return false, err
+	}
+	dryRunFields, err := comparableFields(dryRun)
+	if err != nil {
+		return false, err
+	}
+	return !equality.Semantic.DeepEqual(currentFields, dryRunFields), nil
+}
+
+// comparableFields drops the fields every update changes and the ones the
+// operator does not manage
+func comparableFields(obj client.Object) (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Typed objects lose their TypeMeta in decoding, depending on the client
+	for _, key := range []string{"apiVersion", "kind", "status"} {
+		delete(fields, key)
+	}
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		for _, key := range []string{"resourceVersion", "generation", "managedFields"} {
+			delete(metadata, key)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return fields, nil
+}
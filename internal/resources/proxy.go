@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+const defaultProxyTargetCPUUtilization = 80
+
+type ProxyDeploymentBuilder struct {
+	*v1alpha1.Database
+
+	Labels map[string]string
+}
+
+func (b *ProxyDeploymentBuilder) Build(obj client.Object) error {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New("failed to cast to Deployment object")
+	}
+
+	if deployment.ObjectMeta.Name == "" {
+		deployment.ObjectMeta.Name = fmt.Sprintf(proxyNameFormat, b.Name)
+	}
+	deployment.ObjectMeta.Namespace = b.Namespace
+	deployment.ObjectMeta.Labels = b.Labels
+	deployment.ObjectMeta.Annotations = CopyDict(b.Spec.AdditionalAnnotations)
+
+	proxy := b.Spec.Proxy
+	// Leave replicas to the HorizontalPodAutoscaler when it is enabled,
+	// otherwise every sync would reset the scaled value
+	if proxy.Autoscaling == nil {
+		deployment.Spec.Replicas = proxy.Replicas
+	} else if deployment.Spec.Replicas == nil {
+		deployment.Spec.Replicas = proxy.Autoscaling.MinReplicas
+	}
+	deployment.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: b.Labels,
+	}
+	deployment.Spec.Template = b.buildPodTemplateSpec()
+
+	return nil
+}
+
+func (b *ProxyDeploymentBuilder) buildPodTemplateSpec() corev1.PodTemplateSpec {
+	proxy := b.Spec.Proxy
+
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      b.Labels,
+			Annotations: CopyDict(b.Spec.AdditionalAnnotations),
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "ydb-proxy",
+				Image:           proxy.Image.Name,
+				ImagePullPolicy: *proxy.Image.PullPolicyName,
+				Args:            proxy.Args,
+				Env: []corev1.EnvVar{{
+					Name: "YDB_ENDPOINT",
+					Value: fmt.Sprintf(
+						"%s.%s.svc.cluster.local:%d",
+						fmt.Sprintf(grpcServiceNameFormat, b.Name),
+						b.Namespace,
+						v1alpha1.GRPCPort,
+					),
+				}, {
+					Name:  "YDB_DATABASE",
+					Value: b.TenantPath(),
+				}},
+				Ports: []corev1.ContainerPort{{
+					Name: "grpc", ContainerPort: v1alpha1.GRPCPort,
+				}},
+				Resources: proxy.Resources,
+			}},
+			NodeSelector: b.Spec.NodeSelector,
+			Tolerations:  b.Spec.Tolerations,
+		},
+	}
+	if proxy.Image.PullSecret != nil {
+		podTemplate.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: *proxy.Image.PullSecret}}
+	}
+	return podTemplate
+}
+
+func (b *ProxyDeploymentBuilder) Placeholder(cr client.Object) client.Object {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(proxyNameFormat, cr.GetName()),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+type ProxyAutoscalerBuilder struct {
+	client.Object
+
+	Autoscaling *v1alpha1.ProxyAutoscaling
+	Labels      map[string]string
+}
+
+func (b *ProxyAutoscalerBuilder) Build(obj client.Object) error {
+	hpa, ok := obj.(*autoscalingv1.HorizontalPodAutoscaler)
+	if !ok {
+		return errors.New("failed to cast to HorizontalPodAutoscaler object")
+	}
+
+	if hpa.ObjectMeta.Name == "" {
+		hpa.ObjectMeta.Name = fmt.Sprintf(proxyNameFormat, b.GetName())
+	}
+	hpa.ObjectMeta.Namespace = b.GetNamespace()
+	hpa.ObjectMeta.Labels = b.Labels
+
+	hpa.Spec.ScaleTargetRef = autoscalingv1.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       fmt.Sprintf(proxyNameFormat, b.GetName()),
+	}
+	hpa.Spec.MinReplicas = b.Autoscaling.MinReplicas
+	hpa.Spec.MaxReplicas = b.Autoscaling.MaxReplicas
+	hpa.Spec.TargetCPUUtilizationPercentage = b.Autoscaling.TargetCPUUtilizationPercentage
+	if hpa.Spec.TargetCPUUtilizationPercentage == nil {
+		hpa.Spec.TargetCPUUtilizationPercentage = ptr.Int32(defaultProxyTargetCPUUtilization)
+	}
+
+	return nil
+}
+
+func (b *ProxyAutoscalerBuilder) Placeholder(cr client.Object) client.Object {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(proxyNameFormat, cr.GetName()),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// NotReadyPods lists the ordinals of the StatefulSet pods that are not
+// ready along with the reason, e.g. "2 (CrashLoopBackOff)". Ordinals
+// without a pod are reported as NotCreated.
+func NotReadyPods(statefulSetName string, replicas int32, pods []corev1.Pod) []string {
+	byOrdinal := make(map[int32]*corev1.Pod, len(pods))
+	for i := range pods {
+		suffix := strings.TrimPrefix(pods[i].Name, statefulSetName+"-")
+		ordinal, err := strconv.ParseInt(suffix, 10, 32)
+		if err != nil || suffix == pods[i].Name {
+			continue
+		}
+		byOrdinal[int32(ordinal)] = &pods[i]
+	}
+
+	var notReady []string
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		reason := podNotReadyReason(byOrdinal[ordinal])
+		if reason != "" {
+			notReady = append(notReady, fmt.Sprintf("%d (%s)", ordinal, reason))
+		}
+	}
+	return notReady
+}
+
+// NotReadyPodNames lists the pods without stable ordinals, e.g. of a
+// Deployment, that are not ready along with the reason
+func NotReadyPodNames(pods []corev1.Pod) []string {
+	var notReady []string
+	for i := range pods {
+		reason := podNotReadyReason(&pods[i])
+		if reason != "" {
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", pods[i].Name, reason))
+		}
+	}
+	return notReady
+}
+
+func podNotReadyReason(pod *corev1.Pod) string {
+	if pod == nil {
+		return "NotCreated"
+	}
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			return status.State.Waiting.Reason
+		case status.State.Waiting != nil:
+			return "Waiting"
+		case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+			return status.State.Terminated.Reason
+		case status.State.Terminated != nil:
+			return "Terminated"
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		if pod.Status.Reason != "" {
+			return pod.Status.Reason
+		}
+		return string(pod.Status.Phase)
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue {
+			return "NotReady"
+		}
+	}
+	return ""
+}
+
+// NodeGRPCEndpoint returns the gRPC endpoint of a single StatefulSet pod
+// through the headless interconnect service
+func NodeGRPCEndpoint(podName, statefulSetName, namespace string) string {
+	return fmt.Sprintf(
+		"%s.%s.%s.svc.cluster.local:%d", // FIXME .svc.cluster.local should not be hardcoded
+		podName,
+		fmt.Sprintf(interconnectServiceNameFormat, statefulSetName),
+		namespace,
+		api.GRPCPort,
+	)
+}
+
+// SetNodeReadyGate sets the ydb.tech/node-ready pod condition and reports
+// whether it changed
+func SetNodeReadyGate(pod *corev1.Pod, ready bool, reason, message string) bool {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	for i := range pod.Status.Conditions {
+		condition := &pod.Status.Conditions[i]
+		if condition.Type != api.NodeReadyGateConditionType {
+			continue
+		}
+		if condition.Status == status && condition.Reason == reason {
+			return false
+		}
+		if condition.Status != status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		condition.Status = status
+		condition.Reason = reason
+		condition.Message = message
+		return true
+	}
+
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               api.NodeReadyGateConditionType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+	return true
+}
+
+// ContainersReady reports whether the pod is running and all its
+// containers passed their own probes
+func ContainersReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.ContainersReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
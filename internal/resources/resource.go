@@ -2,11 +2,13 @@ package resources
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,10 +20,16 @@ const (
 	interconnectServiceNameFormat = "%s-interconnect"
 	statusServiceNameFormat       = "%s-status"
 	datastreamsServiceNameFormat  = "%s-datastreams"
+	proxyNameFormat               = "%s-proxy"
+	uiAuthNameFormat              = "%s-ui"
+	monitoringSecretNameFormat    = "%s-monitoring"
+	tlsSecretNameFormat           = "%s-tls"
 
 	grpcTLSVolumeName         = "grpc-tls-volume"
 	interconnectTLSVolumeName = "interconnect-tls-volume"
 	datastreamsTLSVolumeName  = "datastreams-tls-volume"
+	storageCAVolumeName       = "storage-ca-volume"
+	tierVolumeNameFormat      = "tier-%s"
 
 	systemCertsVolumeName = "init-main-shared-certs-volume"
 	localCertsVolumeName  = "init-main-shared-source-dir-volume"
@@ -33,6 +41,7 @@ const (
 	localCertsDir  = "/usr/local/share/ca-certificates"
 	tmpCertsDir    = "/etc/temporary-certs"
 	systemCertsDir = "/etc/ssl/certs"
+	storageCADir   = "/tls/storage"
 
 	lastAppliedAnnotation                     = "ydb.tech/last-applied"
 	encryptionVolumeName                      = "encryption"
@@ -53,6 +62,27 @@ var (
 
 var CreateOrUpdate = ctrl.CreateOrUpdate
 
+// ErrRecreating is returned by CreateOrUpdateIgnoreStatus while an object
+// is deleted to be created again with a changed immutable field
+var ErrRecreating = goerrors.New("object is being recreated")
+
+func podManagementPolicy(policy appsv1.PodManagementPolicyType) appsv1.PodManagementPolicyType {
+	if policy == "" {
+		return appsv1.ParallelPodManagement
+	}
+	return policy
+}
+
+// recreateRequired reports whether updated changes immutable fields of current
+func recreateRequired(current, updated client.Object) bool {
+	currentStatefulSet, ok := current.(*appsv1.StatefulSet)
+	if !ok {
+		return false
+	}
+	updatedStatefulSet := updated.(*appsv1.StatefulSet)
+	return currentStatefulSet.Spec.PodManagementPolicy != updatedStatefulSet.Spec.PodManagementPolicy
+}
+
 func mutate(f ctrlutil.MutateFn, key client.ObjectKey, obj client.Object) error {
 	if err := f(); err != nil {
 		return err
@@ -65,52 +95,80 @@ func mutate(f ctrlutil.MutateFn, key client.ObjectKey, obj client.Object) error
 }
 
 func CreateOrUpdateIgnoreStatus(ctx context.Context, c client.Client, obj client.Object, f ctrlutil.MutateFn) (ctrlutil.OperationResult, error) {
+	result, _, err := CreateOrUpdateIgnoreStatusWithDiff(ctx, c, obj, f)
+	return result, err
+}
+
+// CreateOrUpdateIgnoreStatusWithDiff is CreateOrUpdateIgnoreStatus that also
+// returns the fields changed by an update, see Diff
+func CreateOrUpdateIgnoreStatusWithDiff(ctx context.Context, c client.Client, obj client.Object, f ctrlutil.MutateFn) (ctrlutil.OperationResult, []string, error) {
 	key := client.ObjectKeyFromObject(obj)
 	if err := c.Get(ctx, key, obj); err != nil {
 		if !errors.IsNotFound(err) {
-			return ctrlutil.OperationResultNone, err
+			return ctrlutil.OperationResultNone, nil, err
 		}
 		if err := mutate(f, key, obj); err != nil {
-			return ctrlutil.OperationResultNone, err
+			return ctrlutil.OperationResultNone, nil, err
 		}
 		if err := annotator.SetLastAppliedAnnotation(obj); err != nil {
-			return ctrlutil.OperationResultNone, err
+			return ctrlutil.OperationResultNone, nil, err
 		}
 		if err := c.Create(ctx, obj); err != nil {
-			return ctrlutil.OperationResultNone, err
+			return ctrlutil.OperationResultNone, nil, err
 		}
-		return ctrlutil.OperationResultCreated, nil
+		return ctrlutil.OperationResultCreated, nil, nil
+	}
+
+	if obj.GetDeletionTimestamp() != nil {
+		return ctrlutil.OperationResultNone, nil, ErrRecreating
 	}
 
 	existing := obj.DeepCopyObject()
 	if err := mutate(f, key, obj); err != nil {
-		return ctrlutil.OperationResultNone, err
+		return ctrlutil.OperationResultNone, nil, err
+	}
+	if recreateRequired(existing.(client.Object), obj) {
+		// Orphan the pods, the new object adopts them once created
+		if err := c.Delete(ctx, existing.(client.Object), client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil {
+			return ctrlutil.OperationResultNone, nil, err
+		}
+		return ctrlutil.OperationResultNone, nil, ErrRecreating
+	}
+	patchResult, err := calculatePatchIgnoreStatus(existing, obj)
+	if err != nil || patchResult.IsEmpty() {
+		return ctrlutil.OperationResultNone, nil, err
 	}
-	changed, err := CheckObjectUpdatedIgnoreStatus(existing, obj)
-	if err != nil || !changed {
-		return ctrlutil.OperationResultNone, err
+	// Failing dry-run would fail the update as well, let it report the error
+	if changed, err := changedOnServer(ctx, c, existing.(client.Object), obj); err == nil && !changed {
+		return ctrlutil.OperationResultNone, nil, nil
 	}
+	// The diff only explains the update, failing to compute it is not fatal
+	diff, _ := Diff(obj, patchResult.Patch)
 	if err := annotator.SetLastAppliedAnnotation(obj); err != nil {
-		return ctrlutil.OperationResultNone, err
+		return ctrlutil.OperationResultNone, nil, err
 	}
 	if err := c.Update(ctx, obj); err != nil {
-		return ctrlutil.OperationResultNone, err
+		return ctrlutil.OperationResultNone, nil, err
 	}
-	return ctrlutil.OperationResultUpdated, nil
+	return ctrlutil.OperationResultUpdated, diff, nil
 }
 
 func CheckObjectUpdatedIgnoreStatus(current, updated runtime.Object) (bool, error) {
+	patchResult, err := calculatePatchIgnoreStatus(current, updated)
+	if err != nil {
+		return false, err
+	}
+	return !patchResult.IsEmpty(), nil
+}
+
+func calculatePatchIgnoreStatus(current, updated runtime.Object) (*patch.PatchResult, error) {
 	opts := []patch.CalculateOption{
 		patch.IgnoreStatusFields(),
 	}
 	if _, ok := updated.(*appsv1.StatefulSet); ok {
 		opts = append(opts, patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus())
 	}
-	patchResult, err := patchMaker.Calculate(current, updated, opts...)
-	if err != nil {
-		return false, err
-	}
-	return !patchResult.IsEmpty(), nil
+	return patchMaker.Calculate(current, updated, opts...)
 }
 
 func CopyDict(src map[string]string) map[string]string {
@@ -120,3 +178,13 @@ func CopyDict(src map[string]string) map[string]string {
 	}
 	return dst
 }
+
+// withAdditionalPodLabels returns the labels of the nodes with the
+// additional pod labels, the labels of the nodes win on conflicts
+func withAdditionalPodLabels(nodeLabels, additional map[string]string) map[string]string {
+	podLabels := CopyDict(additional)
+	for k, v := range nodeLabels {
+		podLabels[k] = v
+	}
+	return podLabels
+}
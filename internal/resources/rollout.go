@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// rolloutRevisionAnnotation holds the checksum of the pod template built
+	// by the operator. It is the only thing that decides whether the pod
+	// template is replaced and the pods are rolled out.
+	rolloutRevisionAnnotation = "ydb.tech/rollout-revision"
+
+	// configurationChecksumAnnotation holds the checksum of the rendered
+	// configuration mounted into the pods, so a configuration change gives
+	// a new template revision and restarts the pods
+	configurationChecksumAnnotation = "ydb.tech/configuration-checksum"
+)
+
+// rolloutPodTemplate returns the pod template for the workload. The current
+// template is kept while the revision of the desired one matches it, so that
+// fields defaulted or reordered by the API server and by other controllers
+// never cause a rollout. Any change of the image, configuration, certificates
+// or other pod settings gives a new revision and replaces the template.
+func rolloutPodTemplate(current, desired corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	revision := rolloutRevision(desired)
+	if current.Annotations[rolloutRevisionAnnotation] == revision {
+		return current
+	}
+	desired.Annotations = CopyDict(desired.Annotations)
+	desired.Annotations[rolloutRevisionAnnotation] = revision
+	return desired
+}
+
+func rolloutRevision(template corev1.PodTemplateSpec) string {
+	// Marshaling sorts the map keys, the checksum only depends on the content
+	data, _ := json.Marshal(template)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:8])
+}
+
+// configurationChecksum returns the checksum of the ConfigMap data
+func configurationChecksum(data map[string]string) string {
+	encoded, _ := json.Marshal(data)
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:8])
+}
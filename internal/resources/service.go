@@ -7,6 +7,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 )
 
 const (
@@ -77,3 +79,12 @@ func (b *ServiceBuilder) Placeholder(cr client.Object) client.Object {
 		},
 	}
 }
+
+// nodeSelector returns the selector of a Service of the nodes, narrowed to
+// the pods carrying the additional pod labels when the Service asks for it
+func nodeSelector(nodeLabels map[string]string, service v1alpha1.Service, additional map[string]string) map[string]string {
+	if !service.SelectAdditionalPodLabels || len(additional) == 0 {
+		return nodeLabels
+	}
+	return withAdditionalPodLabels(nodeLabels, additional)
+}
@@ -55,6 +55,12 @@ func (b *ServiceMonitorBuilder) Build(obj client.Object) error {
 func (b *ServiceMonitorBuilder) buildEndpoints() []monitoringv1.Endpoint {
 	endpoints := make([]monitoringv1.Endpoint, 0, len(b.MetricsServices))
 
+	var basicAuth *monitoringv1.BasicAuth
+	if b.Options.IsolatedCredentials {
+		username, password := monitoringSecretKeys(b.Object.GetName())
+		basicAuth = &monitoringv1.BasicAuth{Username: username, Password: password}
+	}
+
 	for _, service := range b.MetricsServices {
 		metricRelabelings := service.Relabelings
 		if len(b.Options.MetricRelabelings) > 0 {
@@ -65,6 +71,7 @@ func (b *ServiceMonitorBuilder) buildEndpoints() []monitoringv1.Endpoint {
 			Path:                 service.Path,
 			TargetPort:           &intstr.IntOrString{IntVal: int32(b.TargetPort)},
 			MetricRelabelConfigs: metricRelabelings,
+			BasicAuth:            basicAuth,
 		})
 	}
 
@@ -14,12 +14,15 @@ import (
 
 type StorageClusterBuilder struct {
 	*api.Storage
+	// Keep a revision of the configuration ConfigMap when positive, see
+	// ConfigRevisionBuilder
+	ConfigRevisionHistory int
 }
 
 func NewCluster(ydbCr *api.Storage) StorageClusterBuilder {
 	cr := ydbCr.DeepCopy()
 
-	return StorageClusterBuilder{cr}
+	return StorageClusterBuilder{Storage: cr}
 }
 
 func (b *StorageClusterBuilder) SetStatusOnFirstReconcile() bool {
@@ -43,6 +46,49 @@ func (b *StorageClusterBuilder) GetGRPCEndpoint() string {
 	return fmt.Sprintf("%s:%d", host, api.GRPCPort)
 }
 
+func (b *StorageClusterBuilder) GetStatusEndpoint() string {
+	host := fmt.Sprintf(statusServiceNameFormat+".%s.svc.cluster.local", b.Name, b.Namespace)
+
+	return fmt.Sprintf("%s:%d", host, api.StatusPort)
+}
+
+// GetBlockDevicePaths returns the paths of the block devices the data
+// stores are attached at in the container of the static node with the
+// given index within the pod, none with the FileBacked disk access mode
+func (b *StorageClusterBuilder) GetBlockDevicePaths(node int) []string {
+	statefulSet := StorageStatefulSetBuilder{Storage: b.Storage}
+	if statefulSet.fileBacked() {
+		return nil
+	}
+	var paths []string
+	for i, spec := range b.Spec.DataStore {
+		if spec.VolumeMode != nil && *spec.VolumeMode == corev1.PersistentVolumeBlock {
+			paths = append(paths, statefulSet.GenerateDeviceName(b.DiskIndex(node, i)))
+		}
+	}
+	return paths
+}
+
+// GetDrivePaths returns the paths the drives of the static node with the
+// given index within the pod are known by in the configuration: the Block
+// data store devices, or their PDisk files with the FileBacked disk access
+// mode
+func (b *StorageClusterBuilder) GetDrivePaths(node int) []string {
+	statefulSet := StorageStatefulSetBuilder{Storage: b.Storage}
+	var paths []string
+	for i, spec := range b.Spec.DataStore {
+		if spec.VolumeMode == nil || *spec.VolumeMode != corev1.PersistentVolumeBlock {
+			continue
+		}
+		if statefulSet.fileBacked() {
+			paths = append(paths, api.DiskFile(b.DiskIndex(node, i)))
+		} else {
+			paths = append(paths, statefulSet.GenerateDeviceName(b.DiskIndex(node, i)))
+		}
+	}
+	return paths
+}
+
 func (b *StorageClusterBuilder) appendCAConfigMapIfNeeded(optionalBuilders []ResourceBuilder) []ResourceBuilder {
 	additionalCAs := make(map[string]string)
 
@@ -80,15 +126,16 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 
 	cfg, _ := configuration.Build(b.Unwrap(), nil)
 
-	optionalBuilders = append(
-		optionalBuilders,
-		&ConfigMapBuilder{
-			Object: b,
-			Name:   b.Storage.GetName(),
-			Data:   cfg,
-			Labels: storageLabels,
-		},
-	)
+	configMapBuilder := ConfigMapBuilder{
+		Object: b,
+		Name:   b.Storage.GetName(),
+		Data:   cfg,
+		Labels: storageLabels,
+	}
+	optionalBuilders = append(optionalBuilders, &configMapBuilder)
+	if b.ConfigRevisionHistory > 0 {
+		optionalBuilders = append(optionalBuilders, &ConfigRevisionBuilder{ConfigMapBuilder: configMapBuilder})
+	}
 
 	grpcServiceLabels := storageLabels.Copy()
 	grpcServiceLabels.Merge(b.Spec.Service.GRPC.AdditionalLabels)
@@ -97,6 +144,7 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 	interconnectServiceLabels := storageLabels.Copy()
 	interconnectServiceLabels.Merge(b.Spec.Service.Interconnect.AdditionalLabels)
 	interconnectServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.InterconnectComponent})
+	interconnectIPFamilies, interconnectIPFamilyPolicy := b.Spec.Service.Interconnect.ServiceIPFamilies()
 
 	statusServiceLabels := storageLabels.Copy()
 	statusServiceLabels.Merge(b.Spec.Service.Status.AdditionalLabels)
@@ -117,6 +165,36 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 		)
 	}
 
+	if b.Spec.Monitoring.IsolatedCredentials {
+		optionalBuilders = append(optionalBuilders,
+			&MonitoringSecretBuilder{
+				Object: b,
+				Endpoints: map[string]string{
+					"endpoint": b.GetGRPCEndpointWithProto(),
+				},
+				Labels:   storageLabels,
+				Rotation: credentialsRotation(b, b.Spec.Monitoring.CredentialsRotationInterval),
+			},
+		)
+	}
+
+	if StorageTLSManaged(b.Storage) {
+		services, pods := tlsDNSNames(b.Name, b.Namespace, b.TotalNodes(), b.Spec.Service.GRPC.ExternalHost)
+		optionalBuilders = append(optionalBuilders,
+			&CertificateSecretBuilder{
+				Object:   b,
+				DNSNames: append(services, pods...),
+				Labels:   storageLabels,
+				Rotation: credentialsRotation(b, nil),
+			},
+		)
+	}
+
+	optionalBuilders = append(
+		optionalBuilders,
+		uiAuthBuilders(b, b.Spec.UIAuth, storageLabels, b.Spec.AdditionalAnnotations, b.Spec.NodeSelector, b.Spec.Tolerations)...,
+	)
+
 	optionalBuilders = b.appendCAConfigMapIfNeeded(optionalBuilders)
 
 	return append(
@@ -125,7 +203,7 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 			Object:         b,
 			NameFormat:     grpcServiceNameFormat,
 			Labels:         grpcServiceLabels,
-			SelectorLabels: storageLabels,
+			SelectorLabels: nodeSelector(storageLabels, b.Spec.Service.GRPC.Service, b.Spec.AdditionalPodLabels),
 			Annotations:    b.Spec.Service.GRPC.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.GRPCServicePortName,
@@ -145,14 +223,14 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 				Name: api.InterconnectServicePortName,
 				Port: api.InterconnectPort,
 			}},
-			IPFamilies:     b.Spec.Service.Interconnect.IPFamilies,
-			IPFamilyPolicy: b.Spec.Service.Interconnect.IPFamilyPolicy,
+			IPFamilies:     interconnectIPFamilies,
+			IPFamilyPolicy: interconnectIPFamilyPolicy,
 		},
 		&ServiceBuilder{
 			Object:         b,
 			NameFormat:     statusServiceNameFormat,
 			Labels:         statusServiceLabels,
-			SelectorLabels: storageLabels,
+			SelectorLabels: nodeSelector(storageLabels, b.Spec.Service.Status.Service, b.Spec.AdditionalPodLabels),
 			Annotations:    b.Spec.Service.GRPC.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.StatusServicePortName,
@@ -162,8 +240,9 @@ func (b *StorageClusterBuilder) GetResourceBuilders() []ResourceBuilder {
 			IPFamilyPolicy: b.Spec.Service.Status.IPFamilyPolicy,
 		},
 		&StorageStatefulSetBuilder{
-			Storage: b.Unwrap(),
-			Labels:  storageLabels,
+			Storage:       b.Unwrap(),
+			Labels:        storageLabels,
+			Configuration: cfg,
 		},
 	)
 }
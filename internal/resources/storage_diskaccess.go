@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+const (
+	diskFilesInitContainerName = "ydb-storage-pdisk-files"
+
+	// Takes the PDisk files as arguments. A missing file is allocated with
+	// 90% of the free space of its volume, the rest is left to the
+	// filesystem. Existing files are never touched.
+	diskFilesScript = `for f in "$@"; do ` +
+		`[ -e "$f" ] || fallocate -l $(( $(df --output=avail -B1 "$(dirname "$f")" | tail -n 1) * 90 / 100 )) "$f" || exit 1; ` +
+		`done`
+)
+
+// fileBacked reports whether the Block data stores are turned into PDisk
+// files on Filesystem volumes
+func (b *StorageStatefulSetBuilder) fileBacked() bool {
+	return b.DiskAccessMode() == v1alpha1.DiskAccessFileBacked
+}
+
+// buildDataStoreClaim returns the claim of the data store. With the
+// FileBacked disk access mode Block data stores are claimed as Filesystem.
+func (b *StorageStatefulSetBuilder) buildDataStoreClaim(spec corev1.PersistentVolumeClaimSpec) corev1.PersistentVolumeClaimSpec {
+	if !b.fileBacked() || spec.VolumeMode == nil || *spec.VolumeMode != corev1.PersistentVolumeBlock {
+		return spec
+	}
+	claim := *spec.DeepCopy()
+	mode := corev1.PersistentVolumeFilesystem
+	claim.VolumeMode = &mode
+	return claim
+}
+
+// buildDiskFilesInitContainer allocates the PDisk files of the Block data
+// stores of every node of the pod with the FileBacked disk access mode
+func (b *StorageStatefulSetBuilder) buildDiskFilesInitContainer() corev1.Container {
+	args := []string{diskFilesScript, diskFilesInitContainerName}
+	var volumeMounts []corev1.VolumeMount
+	for node := 0; node < configuration.NodesPerPod(b.Storage); node++ {
+		for i, spec := range b.Spec.DataStore {
+			if *spec.VolumeMode != corev1.PersistentVolumeBlock {
+				continue
+			}
+			index := b.DiskIndex(node, i)
+			args = append(args, v1alpha1.DiskFile(index))
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      b.GeneratePVCName(index),
+				MountPath: path.Dir(v1alpha1.DiskFile(index)),
+			})
+		}
+	}
+
+	return corev1.Container{
+		Name:            diskFilesInitContainerName,
+		Image:           b.Spec.Image.Name,
+		ImagePullPolicy: *b.Spec.Image.PullPolicyName,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            args,
+		VolumeMounts:    volumeMounts,
+	}
+}
+
+// buildStorageSecurityContext runs the storage containers privileged with
+// the Privileged disk access mode
+func (b *StorageStatefulSetBuilder) buildStorageSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		Privileged: ptr.Bool(b.DiskAccessMode() == v1alpha1.DiskAccessPrivileged),
+	}
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -12,17 +13,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
 )
 
 const (
 	configVolumeName = "ydb-config"
+
+	storageContainerName = "ydb-storage"
 )
 
 type StorageStatefulSetBuilder struct {
 	*v1alpha1.Storage
 
 	Labels map[string]string
+
+	// Data of the rendered configuration ConfigMap
+	Configuration map[string]string
 }
 
 func StringRJust(str, pad string, length int) string {
@@ -39,7 +46,16 @@ func (b *StorageStatefulSetBuilder) GeneratePVCName(index int) string {
 }
 
 func (b *StorageStatefulSetBuilder) GenerateDeviceName(index int) string {
-	return v1alpha1.DiskPathPrefix + "_" + StringRJust(strconv.Itoa(index), "0", v1alpha1.DiskNumberMaxDigits)
+	return v1alpha1.DiskDevice(index)
+}
+
+// StorageNodeContainerName returns the name of the container running the
+// static node with the given index within the pod
+func StorageNodeContainerName(index int) string {
+	if index == 0 {
+		return storageContainerName
+	}
+	return fmt.Sprintf("%s-%d", storageContainerName, index)
 }
 
 func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
@@ -54,28 +70,32 @@ func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 	sts.ObjectMeta.Namespace = b.Namespace
 	sts.ObjectMeta.Annotations = CopyDict(b.Spec.AdditionalAnnotations)
 
+	template := rolloutPodTemplate(sts.Spec.Template, b.buildPodTemplateSpec())
 	sts.Spec = appsv1.StatefulSetSpec{
-		Replicas: ptr.Int32(b.Spec.Nodes),
+		Replicas: ptr.Int32(b.TotalNodes()),
 		Selector: &metav1.LabelSelector{
 			MatchLabels: b.Labels,
 		},
-		PodManagementPolicy:  appsv1.ParallelPodManagement,
+		PodManagementPolicy:  podManagementPolicy(b.Spec.PodManagementPolicy),
 		RevisionHistoryLimit: ptr.Int32(10),
 		ServiceName:          fmt.Sprintf(interconnectServiceNameFormat, b.GetName()),
-		Template:             b.buildPodTemplateSpec(),
-	}
-
-	pvcList := make([]corev1.PersistentVolumeClaim, 0, len(b.Spec.DataStore))
-	for i, pvcSpec := range b.Spec.DataStore {
-		pvcList = append(
-			pvcList,
-			corev1.PersistentVolumeClaim{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: b.GeneratePVCName(i),
+		Template:             template,
+	}
+
+	nodesPerPod := configuration.NodesPerPod(b.Storage)
+	pvcList := make([]corev1.PersistentVolumeClaim, 0, len(b.Spec.DataStore)*nodesPerPod)
+	for node := 0; node < nodesPerPod; node++ {
+		for i, pvcSpec := range b.Spec.DataStore {
+			pvcList = append(
+				pvcList,
+				corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: b.GeneratePVCName(b.DiskIndex(node, i)),
+					},
+					Spec: b.buildDataStoreClaim(pvcSpec),
 				},
-				Spec: pvcSpec,
-			},
-		)
+			)
+		}
 	}
 	sts.Spec.VolumeClaimTemplates = pvcList
 
@@ -93,11 +113,11 @@ func (b *StorageStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpe
 
 	podTemplate := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:      b.Labels,
-			Annotations: CopyDict(b.Spec.AdditionalAnnotations),
+			Labels:      withAdditionalPodLabels(b.Labels, b.Spec.AdditionalPodLabels),
+			Annotations: b.buildPodAnnotations(),
 		},
 		Spec: corev1.PodSpec{
-			Containers:   []corev1.Container{b.buildContainer()},
+			Containers:   b.buildContainers(),
 			NodeSelector: b.Spec.NodeSelector,
 			Affinity:     b.Spec.Affinity,
 			Tolerations:  b.Spec.Tolerations,
@@ -121,6 +141,19 @@ func (b *StorageStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpe
 	} else {
 		podTemplate.Spec.InitContainers = b.Spec.InitContainers
 	}
+	if b.fileBacked() {
+		podTemplate.Spec.InitContainers = append(
+			[]corev1.Container{b.buildDiskFilesInitContainer()},
+			podTemplate.Spec.InitContainers...,
+		)
+	}
+	if b.needsSysctlInitContainer() {
+		podTemplate.Spec.InitContainers = append(
+			[]corev1.Container{b.buildSysctlInitContainer()},
+			podTemplate.Spec.InitContainers...,
+		)
+	}
+	podTemplate.Spec.SecurityContext = b.buildPodSecurityContext()
 
 	if b.Spec.HostNetwork {
 		podTemplate.Spec.HostNetwork = true
@@ -130,6 +163,9 @@ func (b *StorageStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpe
 	if b.Spec.Image.PullSecret != nil {
 		podTemplate.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: *b.Spec.Image.PullSecret}}
 	}
+	if b.Spec.ReadinessGate {
+		podTemplate.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: v1alpha1.NodeReadyGateConditionType}}
+	}
 	return podTemplate
 }
 
@@ -154,6 +190,19 @@ func (b *StorageStatefulSetBuilder) buildTopologySpreadConstraints() []corev1.To
 	}
 }
 
+func (b *StorageStatefulSetBuilder) buildPodAnnotations() map[string]string {
+	annotations := CopyDict(b.Spec.AdditionalAnnotations)
+	if StorageTLSManaged(b.Storage) {
+		services, _ := tlsDNSNames(b.Name, b.Namespace, b.Spec.Nodes, b.Spec.Service.GRPC.ExternalHost)
+		annotations = tlsTopologyAnnotations(annotations, services)
+		annotations = credentialsRotationAnnotations(annotations, b.Storage)
+	}
+	if b.Configuration != nil {
+		annotations[configurationChecksumAnnotation] = configurationChecksum(b.Configuration)
+	}
+	return annotations
+}
+
 func (b *StorageStatefulSetBuilder) buildVolumes() []corev1.Volume {
 	configMapName := b.Name
 
@@ -169,11 +218,11 @@ func (b *StorageStatefulSetBuilder) buildVolumes() []corev1.Volume {
 	}
 
 	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
-		volumes = append(volumes, buildTLSVolume(grpcTLSVolumeName, b.Spec.Service.GRPC.TLSConfiguration))
+		volumes = append(volumes, buildTLSVolume(grpcTLSVolumeName, b.Name, b.Spec.Service.GRPC.TLSConfiguration))
 	}
 
 	if b.Spec.Service.Interconnect.TLSConfiguration.Enabled {
-		volumes = append(volumes, buildTLSVolume(interconnectTLSVolumeName, b.Spec.Service.Interconnect.TLSConfiguration))
+		volumes = append(volumes, buildTLSVolume(interconnectTLSVolumeName, b.Name, b.Spec.Service.Interconnect.TLSConfiguration))
 	}
 
 	if b.areAnyCertificatesAddedToStore() {
@@ -203,6 +252,10 @@ func (b *StorageStatefulSetBuilder) buildVolumes() []corev1.Volume {
 		})
 	}
 
+	if b.Spec.HugePages != nil {
+		volumes = append(volumes, b.buildHugePagesVolume())
+	}
+
 	return volumes
 }
 
@@ -278,7 +331,7 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 	command, args := b.buildContainerArgs()
 
 	container := corev1.Container{
-		Name:            "ydb-storage",
+		Name:            storageContainerName,
 		Image:           b.Spec.Image.Name,
 		ImagePullPolicy: *b.Spec.Image.PullPolicyName,
 		Command:         command,
@@ -291,9 +344,7 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 			},
 		},
 
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: ptr.Bool(false),
-		},
+		SecurityContext: b.buildStorageSecurityContext(),
 
 		Ports: []corev1.ContainerPort{{
 			Name: "grpc", ContainerPort: v1alpha1.GRPCPort,
@@ -304,33 +355,85 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 		}},
 
 		VolumeMounts: b.buildVolumeMounts(),
-		Resources:    b.Spec.Resources,
+		Resources:    b.buildContainerResources(),
 	}
 
+	container.VolumeDevices, container.VolumeMounts = b.buildDataStoreVolumes(0, container.VolumeMounts)
+
+	return container
+}
+
+// buildDataStoreVolumes attaches the volumes of the data stores of the
+// static node with the given index within the pod, in addition to the
+// volume mounts shared by all nodes
+func (b *StorageStatefulSetBuilder) buildDataStoreVolumes(node int, volumeMounts []corev1.VolumeMount) ([]corev1.VolumeDevice, []corev1.VolumeMount) {
 	var volumeDeviceList []corev1.VolumeDevice // todo decide on PVC volumeMode?
-	var volumeMountList []corev1.VolumeMount
+	volumeMountList := append([]corev1.VolumeMount{}, volumeMounts...)
 	for i, spec := range b.Spec.DataStore {
+		index := b.DiskIndex(node, i)
 		if *spec.VolumeMode == corev1.PersistentVolumeFilesystem {
 			volumeMountList = append(
 				volumeMountList,
 				corev1.VolumeMount{
-					Name:      b.GeneratePVCName(i),
+					Name:      b.GeneratePVCName(index),
 					MountPath: v1alpha1.DiskFilePath,
 				},
 			)
 		}
-		if *spec.VolumeMode == corev1.PersistentVolumeBlock {
+		if *spec.VolumeMode == corev1.PersistentVolumeBlock && b.fileBacked() {
+			volumeMountList = append(
+				volumeMountList,
+				corev1.VolumeMount{
+					Name:      b.GeneratePVCName(index),
+					MountPath: path.Dir(v1alpha1.DiskFile(index)),
+				},
+			)
+		} else if *spec.VolumeMode == corev1.PersistentVolumeBlock {
 			volumeDeviceList = append(
 				volumeDeviceList,
 				corev1.VolumeDevice{
-					Name:       b.GeneratePVCName(i),
-					DevicePath: b.GenerateDeviceName(i),
+					Name:       b.GeneratePVCName(index),
+					DevicePath: b.GenerateDeviceName(index),
 				},
 			)
 		}
 	}
-	container.VolumeDevices = append(container.VolumeDevices, volumeDeviceList...)
-	container.VolumeMounts = append(container.VolumeMounts, volumeMountList...)
+	return volumeDeviceList, volumeMountList
+}
+
+func (b *StorageStatefulSetBuilder) buildContainers() []corev1.Container {
+	containers := []corev1.Container{b.buildContainer()}
+	for index := 1; index < configuration.NodesPerPod(b.Storage); index++ {
+		container := buildNodeContainer(containers[0], index)
+		container.VolumeDevices, container.VolumeMounts = b.buildDataStoreVolumes(index, b.buildVolumeMounts())
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// buildNodeContainer derives the container of an additional static node of
+// the pod from the first one, shifting the ports by the node index. The
+// data store volumes of the node are attached separately.
+func buildNodeContainer(first corev1.Container, index int) corev1.Container {
+	container := *first.DeepCopy()
+	container.Name = StorageNodeContainerName(index)
+	container.LivenessProbe.TCPSocket.Port = intstr.FromInt(v1alpha1.GRPCPort + index)
+
+	for i := range container.Ports {
+		container.Ports[i].Name = fmt.Sprintf("%s-%d", container.Ports[i].Name, index)
+		container.Ports[i].ContainerPort += int32(index)
+	}
+
+	ports := map[string]int{
+		"--mon-port": v1alpha1.StatusPort,
+		"--ic-port":  v1alpha1.InterconnectPort,
+	}
+	for i := 0; i+1 < len(container.Args); i++ {
+		if port, ok := ports[container.Args[i]]; ok {
+			container.Args[i+1] = strconv.Itoa(port + index)
+		}
+	}
+	container.Args = append(container.Args, "--grpc-port", strconv.Itoa(v1alpha1.GRPCPort+index))
 
 	return container
 }
@@ -372,6 +475,13 @@ func (b *StorageStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.Spec.HugePages != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      hugePagesVolumeName,
+			MountPath: hugePagesDir,
+		})
+	}
+
 	return volumeMounts
 }
 
@@ -421,7 +531,7 @@ func (b *StorageStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		"static",
 	)
 
-	return command, args
+	return command, append(args, b.Spec.AdditionalArgs...)
 }
 
 func (b *StorageStatefulSetBuilder) Placeholder(cr client.Object) client.Object {
@@ -0,0 +1,95 @@
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+const (
+	hugePagesVolumeName = "hugepages"
+	hugePagesDir        = "/dev/hugepages"
+
+	sysctlInitContainerName = "ydb-storage-sysctl"
+
+	// Takes pairs of /proc/sys paths and values as arguments, so values
+	// never end up in the script itself
+	sysctlScript = `while [ $# -gt 1 ]; do echo "$2" > "/proc/sys/$1" || exit 1; shift 2; done`
+)
+
+// buildContainerResources returns the resources of a storage container,
+// the ones from the spec with the hugepages added to requests and limits,
+// which Kubernetes requires to be equal for hugepages
+func (b *StorageStatefulSetBuilder) buildContainerResources() corev1.ResourceRequirements {
+	resources := *b.Spec.Resources.DeepCopy()
+	if b.Spec.HugePages == nil {
+		return resources
+	}
+
+	name := b.Spec.HugePages.ResourceName()
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+	resources.Requests[name] = b.Spec.HugePages.Size.DeepCopy()
+	resources.Limits[name] = b.Spec.HugePages.Size.DeepCopy()
+	return resources
+}
+
+func (b *StorageStatefulSetBuilder) buildHugePagesVolume() corev1.Volume {
+	pageSize := b.Spec.HugePages.PageSize
+	if pageSize == "" {
+		pageSize = v1alpha1.DefaultHugePageSize
+	}
+	return corev1.Volume{
+		Name: hugePagesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMedium(string(corev1.StorageMediumHugePagesPrefix) + pageSize),
+			},
+		},
+	}
+}
+
+// buildPodSecurityContext puts the sysctls into the pod, unless they are
+// set by the init container
+func (b *StorageStatefulSetBuilder) buildPodSecurityContext() *corev1.PodSecurityContext {
+	sysctls := b.Spec.Sysctls
+	if sysctls == nil || len(sysctls.Values) == 0 || sysctls.Mode == v1alpha1.SysctlModeInitContainer {
+		return nil
+	}
+	return &corev1.PodSecurityContext{Sysctls: sysctls.Values}
+}
+
+func (b *StorageStatefulSetBuilder) needsSysctlInitContainer() bool {
+	return b.Spec.Sysctls != nil &&
+		len(b.Spec.Sysctls.Values) > 0 &&
+		b.Spec.Sysctls.Mode == v1alpha1.SysctlModeInitContainer
+}
+
+// buildSysctlInitContainer writes the sysctls to /proc/sys of the node. It
+// has to be privileged, as /proc/sys is mounted read-only in containers.
+func (b *StorageStatefulSetBuilder) buildSysctlInitContainer() corev1.Container {
+	args := []string{sysctlScript, sysctlInitContainerName}
+	for _, sysctl := range b.Spec.Sysctls.Values {
+		args = append(args, strings.ReplaceAll(sysctl.Name, ".", "/"), sysctl.Value)
+	}
+
+	return corev1.Container{
+		Name:            sysctlInitContainerName,
+		Image:           b.Spec.Image.Name,
+		ImagePullPolicy: *b.Spec.Image.PullPolicyName,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            args,
+
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  new(int64),
+			Privileged: ptr.Bool(true),
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/version"
+)
+
+// ResourcesSyncRequired reports whether the child resources have to be
+// applied again. A sync is skipped only when the last one applied the same
+// rendered resources with the same operator version. The resyncPeriod
+// bounds how long child resources may go without a full sync, so that
+// manual changes to them are eventually reverted.
+func ResourcesSyncRequired(status *api.ResourcesSyncStatus, hash string, resyncPeriod time.Duration, now time.Time) bool {
+	if status == nil {
+		return true
+	}
+	return status.Hash != hash ||
+		status.OperatorVersion != version.Version ||
+		now.Sub(status.LastSyncTime.Time) > resyncPeriod
+}
+
+// RenderedHash renders builders into fresh placeholders and hashes them.
+// Everything a builder reads goes into the hash, e.g. the configuration and
+// the hosts of the Storage a Database runs on. Secrets hold generated keys
+// and passwords that differ on every build, only their names and keys are
+// hashed.
+func RenderedHash(cr client.Object, builders []ResourceBuilder) (string, error) {
+	hasher := sha256.New()
+	for _, builder := range builders {
+		obj := builder.Placeholder(cr)
+		if err := builder.Build(obj); err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(hashedObject(obj))
+		if err != nil {
+			return "", err
+		}
+		hasher.Write(data)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// hashedObject returns the deterministic part of a rendered object
+func hashedObject(obj client.Object) interface{} {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return obj
+	}
+	keys := make([]string, 0, len(secret.Data)+len(secret.StringData))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	for key := range secret.StringData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return struct {
+		Kind string
+		Name string
+		Keys []string
+	}{"Secret", secret.Name, keys}
+}
+
+// NewResourcesSyncStatus returns the sync record for the resources applied
+// with the given rendered hash
+func NewResourcesSyncStatus(cr client.Object, hash string, now time.Time) *api.ResourcesSyncStatus {
+	return &api.ResourcesSyncStatus{
+		Generation:      cr.GetGeneration(),
+		OperatorVersion: version.Version,
+		Hash:            hash,
+		LastSyncTime:    metav1.NewTime(now),
+	}
+}
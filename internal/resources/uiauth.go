@@ -0,0 +1,178 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+)
+
+// UIAuthDeploymentBuilder runs oauth2-proxy in front of the status service
+// of a Storage or Database
+type UIAuthDeploymentBuilder struct {
+	client.Object
+
+	UIAuth       *v1alpha1.UIAuth
+	Labels       map[string]string
+	Annotations  map[string]string
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+}
+
+func (b *UIAuthDeploymentBuilder) Build(obj client.Object) error {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return errors.New("failed to cast to Deployment object")
+	}
+
+	if deployment.ObjectMeta.Name == "" {
+		deployment.ObjectMeta.Name = fmt.Sprintf(uiAuthNameFormat, b.GetName())
+	}
+	deployment.ObjectMeta.Namespace = b.GetNamespace()
+	deployment.ObjectMeta.Labels = b.Labels
+	deployment.ObjectMeta.Annotations = CopyDict(b.Annotations)
+
+	deployment.Spec.Replicas = b.UIAuth.Replicas
+	deployment.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: b.Labels,
+	}
+	deployment.Spec.Template = b.buildPodTemplateSpec()
+
+	return nil
+}
+
+func (b *UIAuthDeploymentBuilder) buildPodTemplateSpec() corev1.PodTemplateSpec {
+	auth := b.UIAuth.DeepCopy()
+	auth.SetDefaults()
+
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      b.Labels,
+			Annotations: CopyDict(b.Annotations),
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "oauth2-proxy",
+				Image:           auth.Image.Name,
+				ImagePullPolicy: *auth.Image.PullPolicyName,
+				Args:            b.buildArgs(auth),
+				Env: []corev1.EnvVar{{
+					Name:      "OAUTH2_PROXY_CLIENT_SECRET",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &auth.ClientSecret},
+				}, {
+					Name:      "OAUTH2_PROXY_COOKIE_SECRET",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &auth.CookieSecret},
+				}},
+				Ports: []corev1.ContainerPort{{
+					Name: v1alpha1.UIAuthServicePortName, ContainerPort: v1alpha1.UIAuthPort,
+				}},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path: "/ping",
+							Port: intstr.FromInt(v1alpha1.UIAuthPort),
+						},
+					},
+				},
+				Resources: auth.Resources,
+			}},
+			NodeSelector: b.NodeSelector,
+			Tolerations:  b.Tolerations,
+		},
+	}
+	if auth.Image.PullSecret != nil {
+		podTemplate.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: *auth.Image.PullSecret}}
+	}
+	return podTemplate
+}
+
+// buildArgs points oauth2-proxy at the status service of the nodes, the
+// user arguments come last so they override the generated ones
+func (b *UIAuthDeploymentBuilder) buildArgs(auth *v1alpha1.UIAuth) []string {
+	args := []string{
+		"--provider=oidc",
+		"--oidc-issuer-url=" + auth.IssuerURL,
+		"--client-id=" + auth.ClientID,
+		fmt.Sprintf("--upstream=http://%s.%s.svc.cluster.local:%d/",
+			fmt.Sprintf(statusServiceNameFormat, b.GetName()), b.GetNamespace(), v1alpha1.StatusPort),
+		fmt.Sprintf("--http-address=0.0.0.0:%d", v1alpha1.UIAuthPort),
+		"--reverse-proxy=true",
+		"--skip-provider-button=true",
+	}
+	if auth.RedirectURL != "" {
+		args = append(args, "--redirect-url="+auth.RedirectURL)
+	}
+	emailDomains := auth.EmailDomains
+	if len(emailDomains) == 0 {
+		emailDomains = []string{"*"}
+	}
+	for _, domain := range emailDomains {
+		args = append(args, "--email-domain="+domain)
+	}
+	for _, group := range auth.AllowedGroups {
+		args = append(args, "--allowed-group="+group)
+	}
+	return append(args, auth.Args...)
+}
+
+func (b *UIAuthDeploymentBuilder) Placeholder(cr client.Object) client.Object {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(uiAuthNameFormat, cr.GetName()),
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+// uiAuthBuilders returns the builders of the oauth2-proxy Deployment and
+// its Service, none when the proxy is disabled. The objects left from an
+// enabled proxy are deleted by the garbage collection then.
+func uiAuthBuilders(
+	obj client.Object,
+	auth *v1alpha1.UIAuth,
+	ownerLabels labels.Labels,
+	annotations map[string]string,
+	nodeSelector map[string]string,
+	tolerations []corev1.Toleration,
+) []ResourceBuilder {
+	if auth == nil || !auth.Enabled {
+		return nil
+	}
+
+	authLabels := ownerLabels.Copy()
+	authLabels.Merge(map[string]string{labels.ComponentKey: labels.UIAuthComponent})
+
+	serviceLabels := authLabels.Copy()
+	serviceLabels.Merge(auth.Service.AdditionalLabels)
+
+	return []ResourceBuilder{
+		&UIAuthDeploymentBuilder{
+			Object:       obj,
+			UIAuth:       auth,
+			Labels:       authLabels,
+			Annotations:  annotations,
+			NodeSelector: nodeSelector,
+			Tolerations:  tolerations,
+		},
+		&ServiceBuilder{
+			Object:         obj,
+			NameFormat:     uiAuthNameFormat,
+			Labels:         serviceLabels,
+			SelectorLabels: authLabels,
+			Annotations:    auth.Service.AdditionalAnnotations,
+			Ports: []corev1.ServicePort{{
+				Name: v1alpha1.UIAuthServicePortName,
+				Port: v1alpha1.UIAuthPort,
+			}},
+			IPFamilies:     auth.Service.IPFamilies,
+			IPFamilyPolicy: auth.Service.IPFamilyPolicy,
+		},
+	}
+}
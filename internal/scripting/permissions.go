@@ -0,0 +1,60 @@
+package scripting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Scheme"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/grpc"
+)
+
+const modifyPermissionsMethod = "/Ydb.Scheme.V1.SchemeService/ModifyPermissions"
+
+// Grant grants the permissions on the scheme object at path to the subject,
+// permissions granted already are kept
+func (c *Client) Grant(ctx context.Context, path, subject string, permissions []string) error {
+	return c.modifyPermissions(ctx, path, &Ydb_Scheme.PermissionsAction{
+		Action: &Ydb_Scheme.PermissionsAction_Grant{
+			Grant: &Ydb_Scheme.Permissions{Subject: subject, PermissionNames: permissions},
+		},
+	})
+}
+
+// Revoke revokes the permissions on the scheme object at path from the
+// subject
+func (c *Client) Revoke(ctx context.Context, path, subject string, permissions []string) error {
+	return c.modifyPermissions(ctx, path, &Ydb_Scheme.PermissionsAction{
+		Action: &Ydb_Scheme.PermissionsAction_Revoke{
+			Revoke: &Ydb_Scheme.Permissions{Subject: subject, PermissionNames: permissions},
+		},
+	})
+}
+
+func (c *Client) modifyPermissions(ctx context.Context, path string, action *Ydb_Scheme.PermissionsAction) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context:  ctx,
+		Target:   c.Endpoint,
+		Database: c.Database,
+	}
+	request := &Ydb_Scheme.ModifyPermissionsRequest{
+		Path:    path,
+		Actions: []*Ydb_Scheme.PermissionsAction{action},
+	}
+	response := &Ydb_Scheme.ModifyPermissionsResponse{}
+	logger.Info(fmt.Sprintf("modifying permissions, path: %s, action: %s", path, action))
+	err := client.Invoke(modifyPermissionsMethod, request, response, c.UseGrpcSecureChannel)
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReply
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package scripting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Scripting"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/grpc"
+)
+
+const executeYqlMethod = "/Ydb.Scripting.V1.ScriptingService/ExecuteYql"
+
+var (
+	ErrEmptyReply = errors.New("empty reply from database")
+
+	quoter = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+)
+
+type Client struct {
+	Endpoint             string
+	UseGrpcSecureChannel bool
+	// Path of the database, e.g. /root/db
+	Database string
+}
+
+// Execute runs the YQL script in the database. Scripts may mix schema and
+// data queries, results of the data queries are discarded.
+func (c *Client) Execute(ctx context.Context, script string) error {
+	logger := log.FromContext(ctx)
+	client := grpc.Client{
+		Context:  ctx,
+		Target:   c.Endpoint,
+		Database: c.Database,
+	}
+	request := &Ydb_Scripting.ExecuteYqlRequest{Script: script}
+	response := &Ydb_Scripting.ExecuteYqlResponse{}
+	logger.Info(fmt.Sprintf("executing script, endpoint: %s, database: %s, secure: %t", c.Endpoint, c.Database, c.UseGrpcSecureChannel))
+	err := client.Invoke(executeYqlMethod, request, response, c.UseGrpcSecureChannel)
+	if err != nil {
+		return err
+	}
+	if response.Operation == nil {
+		return ErrEmptyReply
+	}
+	if response.Operation.Status != Ydb.StatusIds_SUCCESS {
+		return fmt.Errorf("YDB response error: %v %v", response.Operation.Status, response.Operation.Issues)
+	}
+	return nil
+}
+
+// SetUserPassword creates the user with the password, or changes the
+// password of the user when it exists
+func (c *Client) SetUserPassword(ctx context.Context, name, password string) error {
+	err := c.Execute(ctx, fmt.Sprintf("CREATE USER %s PASSWORD %s;", name, Quote(password)))
+	if err == nil {
+		return nil
+	}
+	if alterErr := c.Execute(ctx, fmt.Sprintf("ALTER USER %s PASSWORD %s;", name, Quote(password))); alterErr != nil {
+		return fmt.Errorf("failed to create user %s: %w", name, err)
+	}
+	return nil
+}
+
+// Quote returns the string literal of the value for YQL statements
+func Quote(value string) string {
+	return "'" + quoter.Replace(value) + "'"
+}
@@ -0,0 +1,5 @@
+package version
+
+// Version of the operator, set at build time with
+// -ldflags "-X github.com/ydb-platform/ydb-kubernetes-operator/internal/version.Version=..."
+var Version = "dev"
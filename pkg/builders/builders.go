@@ -0,0 +1,204 @@
+// Package builders renders the Kubernetes objects the operator manages for
+// Storage and Database resources. It needs no cluster connection, so
+// external tools (compositions, test harnesses, manifest generators) can
+// produce exactly the manifests the operator would apply.
+//
+// The functions and types of this package follow Version: they are kept
+// backward compatible within it, while the operator internals behind them
+// may change between releases.
+package builders
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/operatorconfig"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// Version of the package API
+const Version = "v1"
+
+// ResourceBuilder renders a single Kubernetes object owned by a YDB resource
+type ResourceBuilder interface {
+	// Placeholder returns an empty object of the right type with its name
+	// and namespace derived from the owner cr
+	Placeholder(cr client.Object) client.Object
+	// Build fills the placeholder with the desired state
+	Build(client.Object) error
+}
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(monitoringv1.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+}
+
+// Options are the operator settings the rendering depends on, the zero
+// value renders the objects as an operator with the default settings does
+type Options struct {
+	// Number of superseded configuration revisions kept, as set by
+	// spec.configRevisionHistory of the OperatorConfig. Nil keeps the
+	// default of the operator.
+	ConfigRevisionHistory *int
+	// Secret with the operator-managed certificate of the storage, e.g. the
+	// one rendered by RenderStorage, the database certificates are issued
+	// from. When nil it is rendered from the storage.
+	StorageCertificate *corev1.Secret
+}
+
+func (o Options) configRevisionHistory() int {
+	if o.ConfigRevisionHistory != nil {
+		return *o.ConfigRevisionHistory
+	}
+	return operatorconfig.DefaultSettings().ConfigRevisionHistory
+}
+
+// StorageBuilders returns the builders of all objects the operator manages
+// for the storage with the default Options. The storage is expected to have
+// defaults applied.
+func StorageBuilders(storage *v1alpha1.Storage) []ResourceBuilder {
+	return StorageBuildersWithOptions(storage, Options{})
+}
+
+// StorageBuildersWithOptions is StorageBuilders with the operator settings
+// of opts
+func StorageBuildersWithOptions(storage *v1alpha1.Storage, opts Options) []ResourceBuilder {
+	cluster := resources.NewCluster(storage)
+	cluster.ConfigRevisionHistory = opts.configRevisionHistory()
+	return wrap(cluster.GetResourceBuilders())
+}
+
+// DatabaseBuilders returns the builders of all objects the operator manages
+// for the database running on top of storage with the default Options. Both
+// resources are expected to have defaults applied.
+func DatabaseBuilders(database *v1alpha1.Database, storage *v1alpha1.Storage) []ResourceBuilder {
+	builders, err := DatabaseBuildersWithOptions(database, storage, Options{})
+	if err != nil {
+		// The certificate of the storage failed to render, the database one
+		// gets a CA of its own
+		return databaseBuilders(database, storage, Options{}, nil)
+	}
+	return builders
+}
+
+// DatabaseBuildersWithOptions is DatabaseBuilders with the operator settings
+// of opts. When the operator manages the certificates of both resources the
+// database certificate is issued from the one of the storage, as the nodes
+// have to trust each other over interconnect.
+func DatabaseBuildersWithOptions(database *v1alpha1.Database, storage *v1alpha1.Storage, opts Options) ([]ResourceBuilder, error) {
+	var issuer *corev1.Secret
+	if resources.DatabaseTLSManaged(database) && resources.StorageTLSManaged(storage) {
+		issuer = opts.StorageCertificate
+		if issuer == nil {
+			var err error
+			if issuer, err = storageCertificate(storage, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return databaseBuilders(database, storage, opts, issuer), nil
+}
+
+func databaseBuilders(database *v1alpha1.Database, storage *v1alpha1.Storage, opts Options, issuer *corev1.Secret) []ResourceBuilder {
+	builder := resources.NewDatabase(database)
+	builder.Storage = storage
+	builder.ConfigRevisionHistory = opts.configRevisionHistory()
+	builder.CertificateIssuer = issuer
+	return wrap(builder.GetResourceBuilders())
+}
+
+// RenderStorage applies the defaults to a copy of storage and returns the
+// rendered objects with their kind and owner reference set. The data of
+// the Secrets is generated on every call, see Snapshot for a deterministic
+// rendering.
+func RenderStorage(storage *v1alpha1.Storage) ([]client.Object, error) {
+	return RenderStorageWithOptions(storage, Options{})
+}
+
+// RenderStorageWithOptions is RenderStorage with the operator settings of
+// opts
+func RenderStorageWithOptions(storage *v1alpha1.Storage, opts Options) ([]client.Object, error) {
+	storage = storage.DeepCopy()
+	storage.Default()
+	return render(storage, StorageBuildersWithOptions(storage, opts))
+}
+
+// RenderDatabase applies the defaults to copies of database and storage and
+// returns the rendered objects with their kind and owner reference set.
+// The data of the Secrets is generated on every call as by RenderStorage.
+// Serverless databases have no objects of their own.
+func RenderDatabase(database *v1alpha1.Database, storage *v1alpha1.Storage) ([]client.Object, error) {
+	return RenderDatabaseWithOptions(database, storage, Options{})
+}
+
+// RenderDatabaseWithOptions is RenderDatabase with the operator settings of
+// opts
+func RenderDatabaseWithOptions(database *v1alpha1.Database, storage *v1alpha1.Storage, opts Options) ([]client.Object, error) {
+	database = database.DeepCopy()
+	database.Default()
+	storage = storage.DeepCopy()
+	storage.Default()
+	if database.Spec.ServerlessResources != nil {
+		return nil, nil
+	}
+	builders, err := DatabaseBuildersWithOptions(database, storage, opts)
+	if err != nil {
+		return nil, err
+	}
+	return render(database, builders)
+}
+
+// storageCertificate renders the Secret with the certificate of the storage
+func storageCertificate(storage *v1alpha1.Storage, opts Options) (*corev1.Secret, error) {
+	for _, builder := range StorageBuildersWithOptions(storage, opts) {
+		if _, ok := builder.(*resources.CertificateSecretBuilder); !ok {
+			continue
+		}
+		secret := builder.Placeholder(storage).(*corev1.Secret)
+		if err := builder.Build(secret); err != nil {
+			return nil, fmt.Errorf("failed to build the certificate of storage %s: %w", storage.Name, err)
+		}
+		return secret, nil
+	}
+	return nil, fmt.Errorf("storage %s has no certificate rendered", storage.Name)
+}
+
+func render(owner client.Object, builders []ResourceBuilder) ([]client.Object, error) {
+	objects := make([]client.Object, 0, len(builders))
+	for _, builder := range builders {
+		obj := builder.Placeholder(owner)
+		if err := builder.Build(obj); err != nil {
+			return nil, fmt.Errorf("failed to build %T %s: %w", obj, obj.GetName(), err)
+		}
+		if err := ctrl.SetControllerReference(owner, obj, scheme); err != nil {
+			return nil, err
+		}
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func wrap(builders []resources.ResourceBuilder) []ResourceBuilder {
+	result := make([]ResourceBuilder, 0, len(builders))
+	for _, builder := range builders {
+		result = append(result, builder)
+	}
+	return result
+}
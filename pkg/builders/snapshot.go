@@ -0,0 +1,127 @@
+package builders
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const (
+	snapshotSeparator = "---\n"
+
+	// Value of the Secret keys in snapshots
+	SnapshotGeneratedValue = "<generated>"
+)
+
+// Snapshot renders the objects into a multi-document YAML, deterministic
+// for the same input: the documents are ordered by kind, namespace and
+// name, and the fields of every object are sorted. The data of Secrets is
+// generated anew on every render (passwords, keys, certificates), so in
+// the snapshot every key of a Secret has SnapshotGeneratedValue and the
+// time of the generation is left out. It is meant to be compared with a
+// golden file kept by the caller, see CompareSnapshot.
+func Snapshot(objects []client.Object) ([]byte, error) {
+	sorted := make([]client.Object, len(objects))
+	for i, obj := range objects {
+		sorted[i] = obj
+		if secret, ok := obj.(*corev1.Secret); ok {
+			sorted[i] = redactSecret(secret)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return snapshotKey(sorted[i]) < snapshotKey(sorted[j])
+	})
+
+	var buf bytes.Buffer
+	for _, obj := range sorted {
+		document, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", snapshotKey(obj), err)
+		}
+		buf.WriteString(snapshotSeparator)
+		buf.Write(document)
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotStorage renders the objects of the storage with RenderStorage and
+// returns their Snapshot
+func SnapshotStorage(storage *v1alpha1.Storage) ([]byte, error) {
+	objects, err := RenderStorage(storage)
+	if err != nil {
+		return nil, err
+	}
+	return Snapshot(objects)
+}
+
+// SnapshotDatabase renders the objects of the database with RenderDatabase
+// and returns their Snapshot
+func SnapshotDatabase(database *v1alpha1.Database, storage *v1alpha1.Storage) ([]byte, error) {
+	objects, err := RenderDatabase(database, storage)
+	if err != nil {
+		return nil, err
+	}
+	return Snapshot(objects)
+}
+
+// CompareSnapshot returns nil when the snapshots are equal, or an error
+// naming the first line that differs
+func CompareSnapshot(expected, actual []byte) error {
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got {
+			return fmt.Errorf("snapshot differs at line %d: expected %q, got %q", i+1, want, got)
+		}
+	}
+	return nil
+}
+
+// redactSecret returns a copy of the Secret with the values of its keys
+// replaced and the rotation time removed
+func redactSecret(secret *corev1.Secret) *corev1.Secret {
+	redacted := secret.DeepCopy()
+	redacted.Data = nil
+	redacted.StringData = nil
+	for key := range secret.Data {
+		setGeneratedValue(redacted, key)
+	}
+	for key := range secret.StringData {
+		setGeneratedValue(redacted, key)
+	}
+	delete(redacted.Annotations, resources.CredentialsRotatedAtAnnotation)
+	if len(redacted.Annotations) == 0 {
+		redacted.Annotations = nil
+	}
+	return redacted
+}
+
+func setGeneratedValue(secret *corev1.Secret, key string) {
+	if secret.StringData == nil {
+		secret.StringData = map[string]string{}
+	}
+	secret.StringData[key] = SnapshotGeneratedValue
+}
+
+func snapshotKey(obj client.Object) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName())
+}